@@ -28,6 +28,7 @@ import (
 
 type flags struct {
 	OutputFile string
+	OnConflict string
 }
 
 // MakeCommand returns an `aggregate` command.
@@ -38,12 +39,23 @@ func MakeCommand() *cobra.Command {
 		Short: "Aggregates multiple Go coverage files.",
 		Long: `Given multiple Go coverage files from identical binaries recorded in
 "count" or "atomic" mode, produces a new Go coverage file in the same mode
-that counts how many of those coverage profiles hit a block at least once.`,
+that counts how many of those coverage profiles hit a block at least once.
+
+If the files are not from identical binaries, --on-conflict decides what happens to each file whose
+coverage blocks differ, as it does for merge:
+
+  error   fail the aggregation (the default)
+  union   split the blocks of all files into line ranges at every block boundary and count the
+          profiles that hit each range; column information for that file is lost
+  latest  keep only the blocks from the file given last
+
+Every file that had to be reconciled is reported on stderr.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			run(flags, cmd, args)
 		},
 	}
 	cmd.Flags().StringVarP(&flags.OutputFile, "output", "o", "-", "output file")
+	cmd.Flags().StringVar(&flags.OnConflict, "on-conflict", string(cov.ConflictError), "how to handle files that differ between profiles: one of error, union or latest")
 	return cmd
 }
 
@@ -64,11 +76,14 @@ func run(flags *flags, cmd *cobra.Command, args []string) {
 		profiles = append(profiles, profile)
 	}
 
-	aggregated, err := cov.AggregateProfiles(profiles)
+	aggregated, conflicts, err := cov.AggregateProfilesWithPolicy(profiles, cov.ConflictPolicy(flags.OnConflict))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to aggregate files: %v", err)
 		os.Exit(1)
 	}
+	for _, c := range conflicts {
+		fmt.Fprintf(os.Stderr, "reconciled %s (%s): %s\n", c.FileName, flags.OnConflict, c.Reason)
+	}
 
 	if err := util.DumpProfile(flags.OutputFile, aggregated); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package convert
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/cov"
+	"k8s.io/test-infra/gopherage/pkg/cov/convert"
+	"k8s.io/test-infra/gopherage/pkg/util"
+)
+
+type flags struct {
+	outputFile string
+	format     string
+}
+
+// MakeCommand returns a `convert` command.
+func MakeCommand() *cobra.Command {
+	flags := &flags{}
+	cmd := &cobra.Command{
+		Use:   "convert [profile]",
+		Short: "Converts a coverage profile to another format.",
+		Long: `Converts a Go coverage profile (or LCOV tracefile) to one of the following formats:
+
+  cobertura  Cobertura XML, with line rates per file, package and in total
+  lcov       LCOV tracefile, with the hit count of every line
+  json       a summary of line and statement rates per file, package and in total
+  go         a Go coverage profile
+
+Packages are the directories containing the profiled files. A line counts as
+covered if any block on it was hit.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			run(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVarP(&flags.outputFile, "output", "o", "-", "output file")
+	cmd.Flags().StringVarP(&flags.format, "format", "f", "cobertura", "output format: one of cobertura, lcov, json or go")
+	return cmd
+}
+
+func run(flags *flags, cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Expected exactly one argument: coverage file path")
+		cmd.Usage()
+		os.Exit(2)
+	}
+
+	var write func([]*cover.Profile, io.Writer) error
+	switch flags.format {
+	case "cobertura":
+		write = func(profiles []*cover.Profile, w io.Writer) error {
+			return convert.WriteCobertura(profiles, time.Now(), w)
+		}
+	case "lcov":
+		write = convert.WriteLCOV
+	case "json":
+		write = convert.WriteJSON
+	case "go":
+		write = cov.DumpProfile
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown format %q.\n", flags.format)
+		os.Exit(2)
+	}
+
+	profiles, err := util.LoadProfile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse profile file: %v.", err)
+		os.Exit(1)
+	}
+
+	var file io.WriteCloser
+	if flags.outputFile == "-" {
+		file = os.Stdout
+	} else {
+		file, err = os.Create(flags.outputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create file: %v.", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+	}
+
+	if err := write(profiles, file); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write output: %v.", err)
+		os.Exit(1)
+	}
+}
@@ -18,38 +18,56 @@ package diff
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
 	"k8s.io/test-infra/gopherage/pkg/cov"
+	"k8s.io/test-infra/gopherage/pkg/cov/patch"
 	"k8s.io/test-infra/gopherage/pkg/util"
 )
 
 type flags struct {
 	OutputFile string
+	Format     string
+	Changes    util.ChangeFlags
 }
 
 // MakeCommand returns a `diff` command.
 func MakeCommand() *cobra.Command {
 	flags := &flags{}
 	cmd := &cobra.Command{
-		Use:   "diff [first] [second]",
+		Use:   "diff [first] [second] | diff --patch=<diff> [profile] | diff --git-base=<rev> [profile]",
 		Short: "Diffs two Go coverage files.",
 		Long: `Takes the difference between two Go coverage files, producing another Go coverage file
 showing only what was covered between the two files being generated. This works best when using
 files generated in "count" or "atomic" mode; "set" may drastically underreport.
 
 It is assumed that both files came from the same execution, and so all values in the second file are
-at least equal to those in the first file.`,
+at least equal to those in the first file.
+
+If --patch or --git-base is given, only a single coverage file is expected, recorded after the change.
+Instead of subtracting two files, the coverage of only the lines added or modified by the patch (or
+since the git base revision) is reported, per file and overall. Lines that are not part of any
+coverage block, such as comments, are ignored. With --format=profile, a Go coverage file is produced
+instead, with one single-statement block per changed line, which can be passed to other commands
+such as junit.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			run(flags, cmd, args)
 		},
 	}
 	cmd.Flags().StringVarP(&flags.OutputFile, "output", "o", "-", "output file")
+	cmd.Flags().StringVarP(&flags.Format, "format", "f", "text", "output format for changed lines: one of text or profile")
+	flags.Changes.AddFlags(cmd.Flags())
 	return cmd
 }
 
 func run(flags *flags, cmd *cobra.Command, args []string) {
+	if flags.Changes.Enabled() {
+		runChanged(flags, cmd, args)
+		return
+	}
+
 	if len(args) != 2 {
 		fmt.Fprintln(os.Stderr, "Expected two files.")
 		cmd.Usage()
@@ -64,7 +82,7 @@ func run(flags *flags, cmd *cobra.Command, args []string) {
 
 	after, err := util.LoadProfile(args[1])
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Couldn't load %s: %v.", args[0], err)
+		fmt.Fprintf(os.Stderr, "Couldn't load %s: %v.", args[1], err)
 		os.Exit(1)
 	}
 
@@ -79,3 +97,52 @@ func run(flags *flags, cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 }
+
+func runChanged(flags *flags, cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Expected exactly one file when diffing against a patch.")
+		cmd.Usage()
+		os.Exit(2)
+	}
+
+	changes, err := flags.Changes.LoadChanges()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Couldn't load changes: %v.\n", err)
+		os.Exit(1)
+	}
+
+	profiles, err := util.LoadProfile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Couldn't load %s: %v.", args[0], err)
+		os.Exit(1)
+	}
+
+	changed := patch.ChangedLines(profiles, changes)
+
+	switch flags.Format {
+	case "profile":
+		if len(changed) == 0 {
+			fmt.Fprintln(os.Stderr, "No changed lines are covered by the profile.")
+			os.Exit(1)
+		}
+		err = util.DumpProfile(flags.OutputFile, changed)
+	case "text":
+		var file io.WriteCloser = os.Stdout
+		if flags.OutputFile != "-" {
+			file, err = os.Create(flags.OutputFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to create file: %v.", err)
+				os.Exit(1)
+			}
+			defer file.Close()
+		}
+		err = patch.WriteReport(changed, file)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown format %q.\n", flags.Format)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
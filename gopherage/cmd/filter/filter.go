@@ -30,6 +30,8 @@ type flags struct {
 	OutputFile   string
 	IncludePaths []string
 	ExcludePaths []string
+	SourceRoot   string
+	Config       util.ConfigFlags
 }
 
 // MakeCommand returns a `filter` command.
@@ -38,7 +40,11 @@ func MakeCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "filter [file]",
 		Short: "Filters a Go coverage file.",
-		Long:  `Filters a Go coverage file, removing entries that do not match the given flags.`,
+		Long: `Filters a Go coverage file, removing entries that do not match the given flags.
+
+If a config file (.gopherage.yaml by default) is found or --source-root is set, files and code
+excluded by the config file or by //coverage:ignore annotations in the source are removed too, as
+are generated files unless the config file includes them.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			run(flags, cmd, args)
 		},
@@ -46,6 +52,8 @@ func MakeCommand() *cobra.Command {
 	cmd.Flags().StringVarP(&flags.OutputFile, "output", "o", "-", "output file")
 	cmd.Flags().StringSliceVar(&flags.IncludePaths, "include-path", nil, "If specified at least once, only files with paths matching one of these regexes are included.")
 	cmd.Flags().StringSliceVar(&flags.ExcludePaths, "exclude-path", nil, "Files with paths matching one of these regexes are excluded. Can be used repeatedly.")
+	cmd.Flags().StringVar(&flags.SourceRoot, "source-root", "", "Directory containing the source files referenced by the profile, whose annotations are applied. Defaults to the directory containing the config file, if any.")
+	flags.Config.AddFlags(cmd.Flags())
 	return cmd
 }
 
@@ -77,6 +85,17 @@ func run(flags *flags, cmd *cobra.Command, args []string) {
 		}
 	}
 
+	cfg, err := flags.Config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	output, err = util.ApplyIgnores(output, cfg, flags.SourceRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Couldn't apply ignore rules: %v.", err)
+		os.Exit(1)
+	}
+
 	if err := util.DumpProfile(flags.OutputFile, output); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package function
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/test-infra/gopherage/pkg/cov/funccov"
+	"k8s.io/test-infra/gopherage/pkg/cov/junit"
+	"k8s.io/test-infra/gopherage/pkg/util"
+)
+
+type flags struct {
+	outputFile   string
+	format       string
+	sourceRoot   string
+	threshold    float32
+	exportedOnly bool
+}
+
+// MakeCommand returns a `func` command.
+func MakeCommand() *cobra.Command {
+	flags := &flags{}
+	cmd := &cobra.Command{
+		Use:   "func [profile]",
+		Short: "Summarize coverage profile per function.",
+		Long: `Summarize coverage profile per function, by parsing the Go source files it refers to.
+Output can be produced as text (in the same format as 'go tool cover -func'), json or junit xml.
+In junit xml, any function with coverage below threshold will be marked with a <failure> tag.
+
+Source files are looked up in source-root. If source-root contains a go.mod file, files in that
+module are looked up relative to it.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			run(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVarP(&flags.outputFile, "output", "o", "-", "output file")
+	cmd.Flags().StringVarP(&flags.format, "format", "f", "text", "output format: one of text, json or junit")
+	cmd.Flags().StringVar(&flags.sourceRoot, "source-root", ".", "directory containing the source files referenced by the profile")
+	cmd.Flags().Float32VarP(&flags.threshold, "threshold", "t", .8, "per-function code coverage threshold, for junit output")
+	cmd.Flags().BoolVar(&flags.exportedOnly, "exported-only", false, "only report exported functions and methods")
+	return cmd
+}
+
+func run(flags *flags, cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Expected exactly one argument: coverage file path")
+		cmd.Usage()
+		os.Exit(2)
+	}
+
+	if flags.threshold < 0 || flags.threshold > 1 {
+		fmt.Fprintln(os.Stderr, "coverage threshold must be a float number between 0 to 1, inclusively")
+		os.Exit(1)
+	}
+
+	profiles, err := util.LoadProfile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse profile file: %v.", err)
+		os.Exit(1)
+	}
+
+	funcs, err := util.LoadFunctions(profiles, flags.sourceRoot, flags.exportedOnly)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to summarize functions: %v.", err)
+		os.Exit(1)
+	}
+
+	var file io.WriteCloser
+	if flags.outputFile == "-" {
+		file = os.Stdout
+	} else {
+		file, err = os.Create(flags.outputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create file: %v.", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+	}
+
+	switch flags.format {
+	case "text":
+		err = funccov.WriteText(funcs, file)
+	case "json":
+		err = funccov.WriteJSON(funcs, file)
+	case "junit":
+		var text []byte
+		text, err = junit.FunctionsToTestsuiteXML(funcs, flags.threshold)
+		if err == nil {
+			_, err = file.Write(text)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown format %q.\n", flags.format)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write output: %v.", err)
+		os.Exit(1)
+	}
+}
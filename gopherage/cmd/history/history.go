@@ -0,0 +1,198 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/test-infra/gopherage/cmd/metadata"
+	"k8s.io/test-infra/gopherage/pkg/cov/convert"
+	"k8s.io/test-infra/gopherage/pkg/history"
+	"k8s.io/test-infra/gopherage/pkg/util"
+)
+
+type flags struct {
+	store        string
+	metadataFile string
+	commitID     string
+	ref          string
+	path         string
+	last         int
+	points       float32
+	files        bool
+}
+
+// MakeCommand returns a `history` command.
+func MakeCommand() *cobra.Command {
+	flags := &flags{}
+	baseCmd := &cobra.Command{
+		Use:   "history",
+		Short: "Record and query coverage over a series of commits.",
+		Long: `Record and query coverage over a series of commits.
+
+The history is kept in a local append-only store, with one JSON summary of a
+profile per line, keyed by commit ID. Each summary holds the statement and line
+coverage overall, per package (directory) and per file.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Println("Sub command required [append, trend, regressions]")
+			os.Exit(1)
+		},
+	}
+	baseCmd.PersistentFlags().StringVarP(&flags.store, "store", "s", "coverage-history.jsonl", "history store file")
+
+	appendCmd := &cobra.Command{
+		Use:   "append [profile]",
+		Short: "Add the summary of a profile to the history.",
+		Long: `Add the summary of a profile to the history, keyed by the commit it was
+recorded at. The commit is read from the commit_id and ref of a metadata file
+produced by 'gopherage metadata abs', or given directly with --commit.
+Recording a commit again replaces its previous summary.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			runAppend(flags, cmd, args)
+		},
+	}
+	appendCmd.Flags().StringVarP(&flags.metadataFile, "metadata", "m", "", "metadata file produced by 'gopherage metadata abs'")
+	appendCmd.Flags().StringVarP(&flags.commitID, "commit", "c", "", "commit ID, overriding the metadata file")
+	appendCmd.Flags().StringVarP(&flags.ref, "ref", "r", "", "branch ref, overriding the metadata file")
+
+	trendCmd := &cobra.Command{
+		Use:   "trend",
+		Short: "Show coverage over the recorded commits.",
+		Long: `Show the statement coverage of a package or file over the recorded commits,
+oldest first. Without --path, the overall coverage is shown. Paths match the end
+of package and file names, so "pkg/foo" matches "k8s.io/repo/pkg/foo".`,
+		Run: func(cmd *cobra.Command, args []string) {
+			runTrend(flags, cmd, args)
+		},
+	}
+	trendCmd.Flags().StringVarP(&flags.path, "path", "p", "", "package or file to show")
+	trendCmd.Flags().IntVarP(&flags.last, "last", "n", 30, "number of most recent commits to show; 0 shows all of them")
+
+	regressionsCmd := &cobra.Command{
+		Use:   "regressions",
+		Short: "Flag coverage drops in the most recent commit.",
+		Long: `Compare the most recent commit with the one before it, and list the packages
+whose statement coverage dropped by more than --points percentage points, as
+well as the overall coverage. Exits with status 1 if any are found.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			runRegressions(flags, cmd, args)
+		},
+	}
+	regressionsCmd.Flags().Float32Var(&flags.points, "points", 1, "percentage points of coverage that may be lost without being flagged")
+	regressionsCmd.Flags().BoolVar(&flags.files, "files", false, "also flag individual files")
+
+	baseCmd.AddCommand(appendCmd, trendCmd, regressionsCmd)
+	return baseCmd
+}
+
+func runAppend(flags *flags, cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Expected exactly one argument: coverage file path")
+		cmd.Usage()
+		os.Exit(2)
+	}
+
+	var meta metadata.AbsMetadata
+	if flags.metadataFile != "" {
+		data, err := os.ReadFile(flags.metadataFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read metadata: %v.\n", err)
+			os.Exit(1)
+		}
+		if err := json.Unmarshal(data, &meta); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to parse metadata: %v.\n", err)
+			os.Exit(1)
+		}
+	}
+	if flags.commitID != "" {
+		meta.CommitID = flags.commitID
+	}
+	if flags.ref != "" {
+		meta.Ref = flags.ref
+	}
+	if meta.CommitID == "" {
+		fmt.Fprintln(os.Stderr, "A commit ID is required, from --metadata or --commit.")
+		os.Exit(2)
+	}
+
+	profiles, err := util.LoadProfile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse profile file: %v.\n", err)
+		os.Exit(1)
+	}
+
+	store := &history.Store{Path: flags.store}
+	entry := history.Entry{
+		CommitID: meta.CommitID,
+		Ref:      meta.Ref,
+		Time:     time.Now().UTC(),
+		Summary:  *convert.Summarize(profiles),
+	}
+	if err := store.Append(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to record coverage: %v.\n", err)
+		os.Exit(1)
+	}
+}
+
+func loadEntries(flags *flags) []history.Entry {
+	store := &history.Store{Path: flags.store}
+	entries, err := store.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load history: %v.\n", err)
+		os.Exit(1)
+	}
+	return entries
+}
+
+func runTrend(flags *flags, cmd *cobra.Command, args []string) {
+	points := history.Trend(loadEntries(flags), flags.path, flags.last)
+	if len(points) == 0 {
+		fmt.Fprintf(os.Stderr, "No coverage recorded for %q.\n", flags.path)
+		os.Exit(1)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 1, 8, 1, '\t', 0)
+	fmt.Fprintln(tw, "Commit\tRef\tRecorded\tStatements\tCoverage")
+	for _, p := range points {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d/%d\t%.1f%%\n", p.CommitID, p.Ref, p.Time.Format(time.RFC3339), p.Rate.Covered, p.Rate.Total, 100*p.Rate.Rate)
+	}
+	tw.Flush()
+}
+
+func runRegressions(flags *flags, cmd *cobra.Command, args []string) {
+	regressions := history.FindRegressions(loadEntries(flags), flags.points, flags.files)
+	if len(regressions) == 0 {
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 1, 8, 1, '\t', 0)
+	fmt.Fprintln(tw, "Name\tBefore\tAfter\tChange")
+	for _, r := range regressions {
+		name := r.Name
+		if name == "" {
+			name = "OVERALL"
+		}
+		fmt.Fprintf(tw, "%s\t%.1f%% (%s)\t%.1f%% (%s)\t-%.1f\n", name, 100*r.Before.Rate.Rate, r.Before.CommitID, 100*r.After.Rate.Rate, r.After.CommitID, r.Points())
+	}
+	tw.Flush()
+	os.Exit(1)
+}
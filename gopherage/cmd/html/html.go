@@ -17,18 +17,29 @@ limitations under the License.
 package html
 
 import (
+	"bytes"
+	"embed"
 	"fmt"
 	"html/template"
 	"io"
 	"os"
-	"path"
-	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/config"
+	"k8s.io/test-infra/gopherage/pkg/cov"
+	"k8s.io/test-infra/gopherage/pkg/cov/funccov"
+	"k8s.io/test-infra/gopherage/pkg/util"
 )
 
+//go:embed static/browser.html static/browser_bundle.es2015.js
+var static embed.FS
+
 type flags struct {
 	OutputFile string
+	SourceRoot string
+	Config     util.ConfigFlags
 }
 
 // MakeCommand returns a `diff` command.
@@ -39,18 +50,29 @@ func MakeCommand() *cobra.Command {
 		Short: "Emits an HTML file to browse coverage files.",
 		Long: `Produces a self-contained HTML file that enables browsing the provided
 coverage files by directory. The resulting file can be distributed alone to
-produce the same rendering (but does currently require gstatic.com to be
-accessible).
+produce the same rendering, and does not require network access.
 
 If multiple files are provided, they will all be
 shown in the generated HTML file, with the columns in the same order the files
 were listed. When there are multiples columns, each column will have an arrow
-indicating the change from the column immediately to its right.`,
+indicating the change from the column immediately to its right.
+
+If source-root is provided, the source of every file found in it is embedded
+too, and can be browsed with covered and uncovered blocks highlighted according
+to the first coverage file. If source-root contains a go.mod file, files in
+that module are looked up relative to it.
+
+If a config file (.gopherage.yaml by default) is found or source-root is
+provided, files and code excluded by the config file or by //coverage:ignore
+annotations in the source are left out. Annotations are read from source-root,
+or if it is not set, from the directory containing the config file.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			run(flags, cmd, args)
 		},
 	}
 	cmd.Flags().StringVarP(&flags.OutputFile, "output", "o", "-", "output file")
+	cmd.Flags().StringVar(&flags.SourceRoot, "source-root", "", "if set, directory containing the source files to embed")
+	flags.Config.AddFlags(cmd.Flags())
 	return cmd
 }
 
@@ -66,23 +88,12 @@ func run(flags *flags, cmd *cobra.Command, args []string) {
 		os.Exit(2)
 	}
 
-	var resourceDir string
-	if exe, err := os.Executable(); err == nil {
-		resourceDir = path.Join(path.Dir(exe), "cmd/html/static")
-	} else {
-		resourceDir = "gopherage/cmd/html/static"
-	}
-	if _, err := os.Stat(resourceDir); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Resource directory %q does not exist. Are you trying to use go run? You must build this program.\n", resourceDir)
-		os.Exit(1)
-	}
-
-	tpl, err := template.ParseFiles(filepath.Join(resourceDir, "browser.html"))
+	tpl, err := template.ParseFS(static, "static/browser.html")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Couldn't read the HTML template: %v.", err)
 		os.Exit(1)
 	}
-	script, err := os.ReadFile(filepath.Join(resourceDir, "browser_bundle.es2015.js"))
+	script, err := static.ReadFile("static/browser_bundle.es2015.js")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Couldn't read JavaScript: %v.", err)
 		os.Exit(1)
@@ -97,15 +108,15 @@ func run(flags *flags, cmd *cobra.Command, args []string) {
 		}
 	}
 
+	cfg, err := flags.Config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v.", err)
+		os.Exit(1)
+	}
+
 	var coverageFiles []coverageFile
 	for _, arg := range args {
-		var content []byte
-		var err error
-		if arg == "-" {
-			content, err = io.ReadAll(os.Stdin)
-		} else {
-			content, err = os.ReadFile(arg)
-		}
+		content, err := readCoverage(arg, cfg, flags.SourceRoot)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Couldn't read coverage file: %v.", err)
 			os.Exit(1)
@@ -113,6 +124,15 @@ func run(flags *flags, cmd *cobra.Command, args []string) {
 		coverageFiles = append(coverageFiles, coverageFile{Path: arg, Content: string(content)})
 	}
 
+	sources := map[string]string{}
+	if flags.SourceRoot != "" {
+		sources, err = readSources(coverageFiles, flags.SourceRoot)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Couldn't read sources: %v.", err)
+			os.Exit(1)
+		}
+	}
+
 	outputPath := flags.OutputFile
 	var output io.Writer
 	if outputPath == "-" {
@@ -130,8 +150,56 @@ func run(flags *flags, cmd *cobra.Command, args []string) {
 	err = tpl.Execute(output, struct {
 		Script   template.JS
 		Coverage []coverageFile
-	}{template.JS(script), coverageFiles})
+		Sources  map[string]string
+	}{template.JS(script), coverageFiles, sources})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Couldn't write output file: %v.", err)
 	}
 }
+
+// readCoverage loads a coverage file in any format gopherage understands, and
+// converts it to the text format understood by the browser after removing
+// everything that is ignored.
+func readCoverage(path string, cfg *config.Config, sourceRoot string) ([]byte, error) {
+	profiles, err := util.LoadProfile(path)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err = util.ApplyIgnores(profiles, cfg, sourceRoot)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err := cov.DumpProfile(profiles, &buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// readSources reads the source of every file referenced by the coverage files
+// from sourceRoot. Files that can't be found are skipped with a warning.
+func readSources(coverageFiles []coverageFile, sourceRoot string) (map[string]string, error) {
+	resolver, err := funccov.NewSourceResolver(sourceRoot)
+	if err != nil {
+		return nil, err
+	}
+	sources := map[string]string{}
+	for _, f := range coverageFiles {
+		profiles, err := cover.ParseProfilesFromReader(strings.NewReader(f.Content))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %w", f.Path, err)
+		}
+		for _, p := range profiles {
+			if _, ok := sources[p.FileName]; ok {
+				continue
+			}
+			content, err := os.ReadFile(resolver.Resolve(p.FileName))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Skipping source for %s: %v.\n", p.FileName, err)
+				continue
+			}
+			sources[p.FileName] = string(content)
+		}
+	}
+	return sources, nil
+}
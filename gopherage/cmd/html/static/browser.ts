@@ -15,9 +15,12 @@ limitations under the License.
 */
 
 import {Coverage, parseCoverage} from './parser';
+import {renderSource} from './source';
+import {Cell, Column, drawTable as drawCoverageTable, Row} from './table';
 import {enumerate, map} from './utils';
 
 declare const embeddedProfiles: {path: string, content: string}[];
+declare const embeddedSources: {[path: string]: string};
 
 let coverageFiles: {name: string, coverage: Coverage}[] = [];
 let gPrefix = '';
@@ -47,8 +50,19 @@ async function init(): Promise<void> {
   }
 
   coverageFiles = loadEmbeddedProfiles();
-  google.charts.load('current', {packages: ['table']});
-  google.charts.setOnLoadCallback(drawTable);
+  render();
+}
+
+function isSourceFile(path: string): boolean {
+  return Object.prototype.hasOwnProperty.call(embeddedSources, path);
+}
+
+function render(): void {
+  if (isSourceFile(gPrefix)) {
+    drawSource();
+  } else {
+    drawTable();
+  }
 }
 
 function updateBreadcrumb(): void {
@@ -56,10 +70,14 @@ function updateBreadcrumb(): void {
   const parent = document.getElementById('breadcrumbs')!;
   parent.innerHTML = '';
   let prefixSoFar = '';
-  for (const part of parts) {
+  for (const [i, part] of enumerate(parts)) {
     if (!part) {
       continue;
     }
+    if (i === parts.length - 1 && isSourceFile(gPrefix)) {
+      parent.appendChild(document.createTextNode(part));
+      break;
+    }
     prefixSoFar += `${part  }/`;
     const node = document.createElement('a');
     node.href = `#${prefixSoFar}`;
@@ -70,16 +88,15 @@ function updateBreadcrumb(): void {
 }
 
 function coveragesForPrefix(coverages: Coverage[], prefix: string):
-Iterable<{c: {v: number | string, f?: string}[]}> {
+Iterable<Row> {
   const m =
       mergeMaps(map(coverages, (c) => c.getCoverageForPrefix(prefix).children));
   const keys = Array.from(m.keys());
   keys.sort();
-  console.log(m);
   return map(
     keys,
     (k) => ({
-      c: [({v: k} as {v: number | string, f?: string})].concat(
+      c: [({v: k} as Cell)].concat(
         m.get(k)!.map((x, i) => {
           if (!x) {
             return {v: ''};
@@ -126,40 +143,32 @@ function mergeMaps<T, U>(maps: Iterable<Map<T, U>>): Map<T, U[]> {
 function drawTable(): void {
   const rows = Array.from(
     coveragesForPrefix(coverageFiles.map((x) => x.coverage), gPrefix));
-  const cols = coverageFiles.map(
-    (x, i) => ({id: `file-${i}`, label: x.name, type: 'number'}));
-  const dataTable = new google.visualization.DataTable({
-    cols: [
-      {id: 'child', label: 'File', type: 'string'},
-    ].concat(cols),
-    rows,
-  });
-
-  const colourFormatter = new google.visualization.ColorFormat();
-  colourFormatter.addGradientRange(0, 1.0001, '#FFFFFF', '#DD0000', '#00DD00');
-  for (let i = 1; i < cols.length + 1; ++i) {
-    colourFormatter.format(dataTable, i);
+  const cols: Column[] = [{label: 'File', type: 'string'}];
+  for (const x of coverageFiles) {
+    cols.push({label: x.name, type: 'number'});
   }
 
-  const table =
-      new google.visualization.Table(document.getElementById('table')!);
-  table.draw(dataTable, {allowHtml: true});
-
-  google.visualization.events.addListener(table, 'select', () => {
-    const child = rows[table.getSelection()[0].row!].c[0].v as string;
-    if (child.endsWith('/')) {
+  drawCoverageTable(document.getElementById('table')!, cols, rows, (row) => {
+    const child = rows[row].c[0].v as string;
+    if (child.endsWith('/') || isSourceFile(gPrefix + child)) {
       location.hash = gPrefix + child;
-    } else {
-      // TODO: this shouldn't be hardcoded.
-      // location.href = 'profiles/everything-diff.html#file' +
-      //     coverage.getFile(prefix + child).fileNumber;
     }
   });
   updateBreadcrumb();
 }
 
+function drawSource(): void {
+  // Source is always highlighted using the first profile.
+  const file = coverageFiles[0].coverage.getFile(gPrefix);
+  const container = document.getElementById('table')!;
+  container.innerHTML = '';
+  container.appendChild(
+    renderSource(embeddedSources[gPrefix], file ? file.blockList : []));
+  updateBreadcrumb();
+}
+
 document.addEventListener('DOMContentLoaded', () => init());
 window.addEventListener('hashchange', () => {
   gPrefix = location.hash.substring(1);
-  drawTable();
+  render();
 });
@@ -84,6 +84,9 @@
                 this.blocks.set(k, block);
             }
         }
+        get blockList() {
+            return Array.from(this.blocks.values());
+        }
         get totalStatements() {
             return reduce(this.blocks.values(), (acc, b) => acc + b.statements, 0);
         }
@@ -195,8 +198,8 @@
         const [filename, block] = line.split(':');
         const [positions, statements, hits] = block.split(' ');
         const [start, end] = positions.split(',');
-        const [startLine, startCol] = start.split('.').map(parseInt);
-        const [endLine, endCol] = end.split('.').map(parseInt);
+        const [startLine, startCol] = start.split('.').map(Number);
+        const [endLine, endCol] = end.split('.').map(Number);
         return {
             end: {
                 col: endCol,
@@ -212,6 +215,151 @@
         };
     }
 
+    /*
+    Copyright 2026 The Kubernetes Authors.
+
+    Licensed under the Apache License, Version 2.0 (the "License");
+    you may not use this file except in compliance with the License.
+    You may obtain a copy of the License at
+
+        http://www.apache.org/licenses/LICENSE-2.0
+
+    Unless required by applicable law or agreed to in writing, software
+    distributed under the License is distributed on an "AS IS" BASIS,
+    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+    See the License for the specific language governing permissions and
+    limitations under the License.
+    */
+    // segmentLine splits one line of source into runs of text that are covered,
+    // uncovered, or not part of any block. Lines are numbered from 1.
+    function segmentLine(text, line, blocks) {
+        const states = Array(text.length).fill('none');
+        for (const block of blocks) {
+            if (line < block.start.line || line > block.end.line) {
+                continue;
+            }
+            const from = line === block.start.line ? block.start.col - 1 : 0;
+            const to = line === block.end.line ? block.end.col - 1 : text.length;
+            for (let i = Math.max(0, from); i < Math.min(to, text.length); ++i) {
+                states[i] = block.hits > 0 ? 'covered' : 'uncovered';
+            }
+        }
+        const segments = [];
+        for (let i = 0; i < text.length; ++i) {
+            const last = segments[segments.length - 1];
+            if (last && last.state === states[i]) {
+                last.text += text[i];
+            }
+            else {
+                segments.push({ state: states[i], text: text[i] });
+            }
+        }
+        return segments;
+    }
+    function renderSource(source, blocks) {
+        const table = document.createElement('table');
+        table.className = 'source';
+        source.split('\n').forEach((text, i) => {
+            const row = table.insertRow();
+            const lineNumber = row.insertCell();
+            lineNumber.className = 'line-number';
+            lineNumber.textContent = String(i + 1);
+            const code = row.insertCell();
+            code.className = 'code';
+            for (const segment of segmentLine(text, i + 1, blocks)) {
+                const span = document.createElement('span');
+                span.className = segment.state;
+                span.textContent = segment.text;
+                code.appendChild(span);
+            }
+        });
+        return table;
+    }
+
+    /*
+    Copyright 2026 The Kubernetes Authors.
+
+    Licensed under the Apache License, Version 2.0 (the "License");
+    you may not use this file except in compliance with the License.
+    You may obtain a copy of the License at
+
+        http://www.apache.org/licenses/LICENSE-2.0
+
+    Unless required by applicable law or agreed to in writing, software
+    distributed under the License is distributed on an "AS IS" BASIS,
+    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+    See the License for the specific language governing permissions and
+    limitations under the License.
+    */
+    // A minimal sortable table, so that the coverage browser does not need to
+    // load a charting library from the network.
+    // gradientColour returns a colour from red at 0 to green at 1.
+    function gradientColour(value) {
+        const clamped = Math.min(1, Math.max(0, value));
+        const red = Math.round(0xDD * (1 - clamped));
+        const green = Math.round(0xDD * clamped);
+        const hex = (n) => `0${n.toString(16)}`.slice(-2);
+        return `#${hex(red)}${hex(green)}00`;
+    }
+    function compareCells(a, b) {
+        // Empty cells always sort last.
+        if (a.v === '' || b.v === '') {
+            return (a.v === '' ? 1 : 0) - (b.v === '' ? 1 : 0);
+        }
+        if (a.v < b.v) {
+            return -1;
+        }
+        else if (a.v > b.v) {
+            return 1;
+        }
+        return 0;
+    }
+    function drawTable(container, cols, rows, onSelect) {
+        let sortColumn = 0;
+        let ascending = true;
+        const render = () => {
+            const order = rows.map((_, i) => i);
+            order.sort((a, b) => {
+                const result = compareCells(rows[a].c[sortColumn], rows[b].c[sortColumn]);
+                return ascending ? result : -result;
+            });
+            const table = document.createElement('table');
+            table.className = 'coverage-table';
+            const header = table.createTHead().insertRow();
+            cols.forEach((col, i) => {
+                const th = document.createElement('th');
+                th.textContent = col.label + (i === sortColumn ? (ascending ? ' ▴' : ' ▾') : '');
+                th.addEventListener('click', () => {
+                    ascending = i === sortColumn ? !ascending : true;
+                    sortColumn = i;
+                    render();
+                });
+                header.appendChild(th);
+            });
+            const body = table.createTBody();
+            for (const i of order) {
+                const tr = body.insertRow();
+                tr.addEventListener('click', () => onSelect(i));
+                rows[i].c.forEach((cell, j) => {
+                    const td = tr.insertCell();
+                    if (cell.f !== undefined) {
+                        td.innerHTML = cell.f;
+                    }
+                    else {
+                        td.textContent = String(cell.v);
+                    }
+                    if (cols[j].type === 'number' && typeof cell.v === 'number') {
+                        td.className = 'number';
+                        td.style.backgroundColor = gradientColour(cell.v);
+                    }
+                });
+            }
+            container.innerHTML = '';
+            container.appendChild(table);
+        };
+        render();
+    }
+
     /*
     Copyright 2018 The Kubernetes Authors.
 
@@ -254,19 +402,33 @@
                 gPrefix = location.hash.substring(1);
             }
             coverageFiles = loadEmbeddedProfiles();
-            google.charts.load('current', { packages: ['table'] });
-            google.charts.setOnLoadCallback(drawTable);
+            render();
         });
     }
+    function isSourceFile(path) {
+        return Object.prototype.hasOwnProperty.call(embeddedSources, path);
+    }
+    function render() {
+        if (isSourceFile(gPrefix)) {
+            drawSource();
+        }
+        else {
+            drawTable$1();
+        }
+    }
     function updateBreadcrumb() {
         const parts = gPrefix.split('/');
         const parent = document.getElementById('breadcrumbs');
         parent.innerHTML = '';
         let prefixSoFar = '';
-        for (const part of parts) {
+        for (const [i, part] of enumerate(parts)) {
             if (!part) {
                 continue;
             }
+            if (i === parts.length - 1 && isSourceFile(gPrefix)) {
+                parent.appendChild(document.createTextNode(part));
+                break;
+            }
             prefixSoFar += part + '/';
             const node = document.createElement('a');
             node.href = `#${prefixSoFar}`;
@@ -279,7 +441,6 @@
         const m = mergeMaps(map(coverages, (c) => c.getCoverageForPrefix(prefix).children));
         const keys = Array.from(m.keys());
         keys.sort();
-        console.log(m);
         return map(keys, (k) => ({
             c: [{ v: k }].concat(m.get(k).map((x, i) => {
                 if (!x) {
@@ -322,34 +483,32 @@
         }
         return result;
     }
-    function drawTable() {
+    function drawTable$1() {
         const rows = Array.from(coveragesForPrefix(coverageFiles.map((x) => x.coverage), gPrefix));
-        const cols = coverageFiles.map((x, i) => ({ id: `file-${i}`, label: x.name, type: 'number' }));
-        const dataTable = new google.visualization.DataTable({
-            cols: [
-                { id: 'child', label: 'File', type: 'string' },
-            ].concat(cols),
-            rows,
-        });
-        const colourFormatter = new google.visualization.ColorFormat();
-        colourFormatter.addGradientRange(0, 1.0001, '#FFFFFF', '#DD0000', '#00DD00');
-        for (let i = 1; i < cols.length + 1; ++i) {
-            colourFormatter.format(dataTable, i);
-        }
-        const table = new google.visualization.Table(document.getElementById('table'));
-        table.draw(dataTable, { allowHtml: true });
-        google.visualization.events.addListener(table, 'select', () => {
-            const child = rows[table.getSelection()[0].row].c[0].v;
-            if (child.endsWith('/')) {
+        const cols = [{ label: 'File', type: 'string' }];
+        for (const x of coverageFiles) {
+            cols.push({ label: x.name, type: 'number' });
+        }
+        drawTable(document.getElementById('table'), cols, rows, (row) => {
+            const child = rows[row].c[0].v;
+            if (child.endsWith('/') || isSourceFile(gPrefix + child)) {
                 location.hash = gPrefix + child;
             }
         });
         updateBreadcrumb();
     }
+    function drawSource() {
+        // Source is always highlighted using the first profile.
+        const file = coverageFiles[0].coverage.getFile(gPrefix);
+        const container = document.getElementById('table');
+        container.innerHTML = '';
+        container.appendChild(renderSource(embeddedSources[gPrefix], file ? file.blockList : []));
+        updateBreadcrumb();
+    }
     document.addEventListener('DOMContentLoaded', () => init());
     window.addEventListener('hashchange', () => {
         gPrefix = location.hash.substring(1);
-        drawTable();
+        render();
     });
 
 }());
@@ -43,6 +43,10 @@ export class FileCoverage {
     }
   }
 
+  get blockList(): Block[] {
+    return Array.from(this.blocks.values());
+  }
+
   get totalStatements(): number {
     return reduce(this.blocks.values(), (acc, b) => acc + b.statements, 0);
   }
@@ -173,8 +177,8 @@ function parseLine(line: string): Block&{filename: string} {
   const [filename, block] = line.split(':');
   const [positions, statements, hits] = block.split(' ');
   const [start, end] = positions.split(',');
-  const [startLine, startCol] = start.split('.').map(parseInt);
-  const [endLine, endCol] = end.split('.').map(parseInt);
+  const [startLine, startCol] = start.split('.').map(Number);
+  const [endLine, endCol] = end.split('.').map(Number);
   return {
     end: {
       col: endCol,
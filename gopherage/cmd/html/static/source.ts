@@ -0,0 +1,72 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import {Block} from './parser';
+
+export type SegmentState = 'none' | 'covered' | 'uncovered';
+
+export interface Segment {
+  text: string;
+  state: SegmentState;
+}
+
+// segmentLine splits one line of source into runs of text that are covered,
+// uncovered, or not part of any block. Lines are numbered from 1.
+export function segmentLine(
+  text: string, line: number, blocks: Block[]): Segment[] {
+  const states: SegmentState[] = Array(text.length).fill('none');
+  for (const block of blocks) {
+    if (line < block.start.line || line > block.end.line) {
+      continue;
+    }
+    const from = line === block.start.line ? block.start.col - 1 : 0;
+    const to = line === block.end.line ? block.end.col - 1 : text.length;
+    for (let i = Math.max(0, from); i < Math.min(to, text.length); ++i) {
+      states[i] = block.hits > 0 ? 'covered' : 'uncovered';
+    }
+  }
+
+  const segments: Segment[] = [];
+  for (let i = 0; i < text.length; ++i) {
+    const last = segments[segments.length - 1];
+    if (last && last.state === states[i]) {
+      last.text += text[i];
+    } else {
+      segments.push({state: states[i], text: text[i]});
+    }
+  }
+  return segments;
+}
+
+export function renderSource(source: string, blocks: Block[]): HTMLElement {
+  const table = document.createElement('table');
+  table.className = 'source';
+  source.split('\n').forEach((text, i) => {
+    const row = table.insertRow();
+    const lineNumber = row.insertCell();
+    lineNumber.className = 'line-number';
+    lineNumber.textContent = String(i + 1);
+    const code = row.insertCell();
+    code.className = 'code';
+    for (const segment of segmentLine(text, i + 1, blocks)) {
+      const span = document.createElement('span');
+      span.className = segment.state;
+      span.textContent = segment.text;
+      code.appendChild(span);
+    }
+  });
+  return table;
+}
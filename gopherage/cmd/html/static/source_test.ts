@@ -0,0 +1,42 @@
+import "jasmine";
+import {segmentLine} from './source';
+
+const block = (startLine: number, startCol: number, endLine: number, endCol: number, hits: number) => ({
+  end: {col: endCol, line: endLine},
+  hits,
+  start: {col: startCol, line: startLine},
+  statements: 1,
+});
+
+describe('segmentLine', () => {
+  it('should leave lines outside any block unhighlighted', () => {
+    expect(segmentLine('package foo', 1, [block(3, 1, 5, 2, 1)])).toEqual([
+      {state: 'none', text: 'package foo'},
+    ]);
+  });
+
+  it('should split a line at block boundaries', () => {
+    expect(segmentLine('func f() { return }', 1, [block(1, 10, 1, 20, 0)])).toEqual([
+      {state: 'none', text: 'func f() '},
+      {state: 'uncovered', text: '{ return }'},
+    ]);
+  });
+
+  it('should highlight the whole of lines inside a multi-line block', () => {
+    expect(segmentLine('\tx++', 4, [block(3, 5, 6, 2, 2)])).toEqual([
+      {state: 'covered', text: '\tx++'},
+    ]);
+  });
+
+  it('should end a block at its end column', () => {
+    expect(segmentLine('\t}; y()', 6, [block(3, 5, 6, 3, 2), block(6, 4, 6, 8, 0)])).toEqual([
+      {state: 'covered', text: '\t}'},
+      {state: 'none', text: ';'},
+      {state: 'uncovered', text: ' y()'},
+    ]);
+  });
+
+  it('should produce nothing for an empty line', () => {
+    expect(segmentLine('', 1, [block(1, 1, 2, 1, 1)])).toEqual([]);
+  });
+});
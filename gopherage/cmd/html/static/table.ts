@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// A minimal sortable table, so that the coverage browser does not need to
+// load a charting library from the network.
+
+export interface Cell {
+  v: number | string;
+  f?: string;
+}
+
+export interface Row {
+  c: Cell[];
+}
+
+export interface Column {
+  label: string;
+  type: 'string' | 'number';
+}
+
+// gradientColour returns a colour from red at 0 to green at 1.
+export function gradientColour(value: number): string {
+  const clamped = Math.min(1, Math.max(0, value));
+  const red = Math.round(0xDD * (1 - clamped));
+  const green = Math.round(0xDD * clamped);
+  const hex = (n: number) => `0${n.toString(16)}`.slice(-2);
+  return `#${hex(red)}${hex(green)}00`;
+}
+
+function compareCells(a: Cell, b: Cell): number {
+  // Empty cells always sort last.
+  if (a.v === '' || b.v === '') {
+    return (a.v === '' ? 1 : 0) - (b.v === '' ? 1 : 0);
+  }
+  if (a.v < b.v) {
+    return -1;
+  } else if (a.v > b.v) {
+    return 1;
+  }
+  return 0;
+}
+
+export function drawTable(
+  container: HTMLElement, cols: Column[], rows: Row[],
+  onSelect: (row: number) => void): void {
+  let sortColumn = 0;
+  let ascending = true;
+
+  const render = () => {
+    const order = rows.map((_, i) => i);
+    order.sort((a, b) => {
+      const result = compareCells(rows[a].c[sortColumn], rows[b].c[sortColumn]);
+      return ascending ? result : -result;
+    });
+
+    const table = document.createElement('table');
+    table.className = 'coverage-table';
+    const header = table.createTHead().insertRow();
+    cols.forEach((col, i) => {
+      const th = document.createElement('th');
+      th.textContent = col.label + (i === sortColumn ? (ascending ? ' ▴' : ' ▾') : '');
+      th.addEventListener('click', () => {
+        ascending = i === sortColumn ? !ascending : true;
+        sortColumn = i;
+        render();
+      });
+      header.appendChild(th);
+    });
+
+    const body = table.createTBody();
+    for (const i of order) {
+      const tr = body.insertRow();
+      tr.addEventListener('click', () => onSelect(i));
+      rows[i].c.forEach((cell, j) => {
+        const td = tr.insertCell();
+        if (cell.f !== undefined) {
+          td.innerHTML = cell.f;
+        } else {
+          td.textContent = String(cell.v);
+        }
+        if (cols[j].type === 'number' && typeof cell.v === 'number') {
+          td.className = 'number';
+          td.style.backgroundColor = gradientColour(cell.v);
+        }
+      });
+    }
+
+    container.innerHTML = '';
+    container.appendChild(table);
+  };
+  render();
+}
@@ -22,13 +22,23 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"k8s.io/test-infra/gopherage/pkg/config"
 	"k8s.io/test-infra/gopherage/pkg/cov/junit"
+	"k8s.io/test-infra/gopherage/pkg/cov/patch"
 	"k8s.io/test-infra/gopherage/pkg/util"
 )
 
 type flags struct {
-	outputFile string
-	threshold  float32
+	outputFile            string
+	threshold             float32
+	functionThreshold     float32
+	sourceRoot            string
+	exportedFunctionsOnly bool
+	changes               util.ChangeFlags
+	config                util.ConfigFlags
+	pathThresholds        []string
+	baseline              string
+	ratchetTolerance      float32
 }
 
 // MakeCommand returns a `junit` command.
@@ -39,13 +49,41 @@ func MakeCommand() *cobra.Command {
 		Short: "Summarize coverage profile and produce the result in junit xml format.",
 		Long: `Summarize coverage profile and produce the result in junit xml format.
 Summary done at per-file and per-package level. Any coverage below coverage-threshold will be marked
-with a <failure> tag in the xml produced.`,
+with a <failure> tag in the xml produced.
+
+If function-threshold is set, the Go source files referenced by the profile are read from source-root
+and a summary is also produced for each function, marked as a failure if its coverage is below
+function-threshold.
+
+If a config file (.gopherage.yaml by default) is found or --source-root is set, files and code
+excluded by the config file or by //coverage:ignore annotations in the source are left out, as are
+generated files unless the config file includes them.
+The config file can also set a different threshold for the files under a directory, as can
+--path-threshold, which takes precedence. The threshold with the longest matching path applies.
+
+If --baseline is given, junit runs in ratchet mode: the overall coverage and that of each package
+only fail if they fall more than ratchet-tolerance below their coverage in the baseline profile, so
+that the gate can be adopted by code that does not yet meet a threshold. Packages missing from the
+baseline are held to their threshold, and individual files never fail. Since the baseline was
+recorded from other source, only files excluded as a whole are left out of it, not code annotated
+with //coverage:ignore.
+
+If --patch or --git-base is given, only the lines added or modified by the patch (or since the git
+base revision) are considered, so that the thresholds apply to the coverage of new code.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			run(flags, cmd, args)
 		},
 	}
 	cmd.Flags().StringVarP(&flags.outputFile, "output", "o", "-", "output file")
 	cmd.Flags().Float32VarP(&flags.threshold, "threshold", "t", .8, "code coverage threshold")
+	cmd.Flags().Float32Var(&flags.functionThreshold, "function-threshold", 0, "per-function code coverage threshold; if unset, functions are not summarized")
+	cmd.Flags().StringVar(&flags.sourceRoot, "source-root", "", "directory containing the source files referenced by the profile; defaults to the directory containing the config file, or else the current directory")
+	cmd.Flags().BoolVar(&flags.exportedFunctionsOnly, "exported-functions-only", false, "only apply function-threshold to exported functions and methods")
+	flags.changes.AddFlags(cmd.Flags())
+	flags.config.AddFlags(cmd.Flags())
+	cmd.Flags().StringArrayVar(&flags.pathThresholds, "path-threshold", nil, "threshold for the files under a path, as path=threshold; can be used repeatedly")
+	cmd.Flags().StringVar(&flags.baseline, "baseline", "", "if set, baseline profile to ratchet package coverage against")
+	cmd.Flags().Float32Var(&flags.ratchetTolerance, "ratchet-tolerance", 0, "how far below its baseline coverage a package may fall in ratchet mode, e.g. 0.01 for one percentage point")
 	return cmd
 }
 
@@ -56,11 +94,27 @@ func run(flags *flags, cmd *cobra.Command, args []string) {
 		os.Exit(2)
 	}
 
-	if flags.threshold < 0 || flags.threshold > 1 {
+	if flags.threshold < 0 || flags.threshold > 1 || flags.functionThreshold < 0 || flags.functionThreshold > 1 {
 		fmt.Fprintln(os.Stderr, "coverage threshold must be a float number between 0 to 1, inclusively")
 		os.Exit(1)
 	}
 
+	if flags.ratchetTolerance < 0 || flags.ratchetTolerance > 1 {
+		fmt.Fprintln(os.Stderr, "ratchet tolerance must be a float number between 0 to 1, inclusively")
+		os.Exit(1)
+	}
+
+	if flags.baseline != "" && flags.changes.Enabled() {
+		fmt.Fprintln(os.Stderr, "--baseline compares whole packages, so can't be used with --patch or --git-base")
+		os.Exit(2)
+	}
+
+	cfg, err := flags.config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v.", err)
+		os.Exit(1)
+	}
+
 	profilePath := args[0]
 
 	profiles, err := util.LoadProfile(profilePath)
@@ -69,8 +123,67 @@ func run(flags *flags, cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	text, err := junit.ProfileToTestsuiteXML(profiles, flags.threshold)
+	profiles, err = util.ApplyIgnores(profiles, cfg, flags.sourceRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to apply ignore rules: %v.", err)
+		os.Exit(1)
+	}
+
+	if flags.changes.Enabled() {
+		changes, err := flags.changes.LoadChanges()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load changes: %v.", err)
+			os.Exit(1)
+		}
+		profiles = patch.ChangedLines(profiles, changes)
+	}
 
+	if len(flags.pathThresholds) > 0 {
+		if cfg == nil {
+			if cfg, err = config.New(util.SourceRoot(flags.sourceRoot, nil)); err != nil {
+				fmt.Fprintf(os.Stderr, "%v.", err)
+				os.Exit(1)
+			}
+		}
+		for _, pt := range flags.pathThresholds {
+			t, err := config.ParseThreshold(pt)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v.", err)
+				os.Exit(1)
+			}
+			cfg.Thresholds = append(cfg.Thresholds, t)
+		}
+	}
+
+	opts := junit.Options{
+		Threshold: flags.threshold,
+		ThresholdFor: func(name string) float32 {
+			return cfg.ThresholdFor(name, flags.threshold)
+		},
+	}
+	if cmd.Flags().Changed("function-threshold") {
+		opts.Functions, err = util.LoadFunctions(profiles, util.SourceRoot(flags.sourceRoot, cfg), flags.exportedFunctionsOnly)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to summarize functions: %v.", err)
+			os.Exit(1)
+		}
+		opts.FunctionThreshold = flags.functionThreshold
+	}
+	if flags.baseline != "" {
+		baseline, err := util.LoadProfile(flags.baseline)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to parse baseline profile file: %v.", err)
+			os.Exit(1)
+		}
+		// The baseline was recorded from other source, so only rules for whole files apply.
+		opts.Baseline, err = util.ApplyFileIgnores(baseline, cfg, flags.sourceRoot)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to apply ignore rules to baseline: %v.", err)
+			os.Exit(1)
+		}
+		opts.Tolerance = flags.ratchetTolerance
+	}
+	text, err := junit.ProfileToTestsuiteXMLWithOptions(profiles, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to produce xml from profiles: %v.", err)
 		os.Exit(1)
@@ -28,6 +28,9 @@ import (
 
 type flags struct {
 	OutputFile string
+	OnConflict string
+	SourceRoot string
+	Config     util.ConfigFlags
 }
 
 // MakeCommand returns a `merge` command.
@@ -41,12 +44,29 @@ merge requires that the files are 'coherent', meaning that if they both contain
 same paths, then the contents of those source files were identical for the binary that generated
 each file.
 
-Merging a single file is a no-op, but is supported for convenience when shell scripting.`,
+If the files are not coherent, --on-conflict decides what happens to each file whose coverage blocks
+differ:
+
+  error   fail the merge (the default)
+  union   split the blocks of both files into line ranges at every block boundary and combine the
+          counts of each range; column information for that file is lost
+  latest  keep only the blocks from the file given last
+
+Every file that had to be reconciled is reported on stderr.
+
+If a config file (.gopherage.yaml by default) is found or --source-root is set, files and code
+excluded by the config file or by //coverage:ignore annotations in the source are left out of the
+merged file, as are generated files unless the config file includes them.
+
+Otherwise merging a single file is a no-op, but is supported for convenience when shell scripting.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			run(flags, cmd, args)
 		},
 	}
 	cmd.Flags().StringVarP(&flags.OutputFile, "output", "o", "-", "output file")
+	cmd.Flags().StringVar(&flags.OnConflict, "on-conflict", string(cov.ConflictError), "how to handle files that differ between profiles: one of error, union or latest")
+	cmd.Flags().StringVar(&flags.SourceRoot, "source-root", "", "directory containing the source files referenced by the profiles, whose annotations are applied; defaults to the directory containing the config file, if any")
+	flags.Config.AddFlags(cmd.Flags())
 	return cmd
 }
 
@@ -57,6 +77,12 @@ func run(flags *flags, cmd *cobra.Command, args []string) {
 		os.Exit(2)
 	}
 
+	cfg, err := flags.Config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
 	profiles := make([][]*cover.Profile, 0, len(args))
 	for _, path := range args {
 		profile, err := util.LoadProfile(path)
@@ -67,11 +93,20 @@ func run(flags *flags, cmd *cobra.Command, args []string) {
 		profiles = append(profiles, profile)
 	}
 
-	merged, err := cov.MergeMultipleProfiles(profiles)
+	merged, conflicts, err := cov.MergeMultipleProfilesWithPolicy(profiles, cov.ConflictPolicy(flags.OnConflict))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to merge files: %v", err)
 		os.Exit(1)
 	}
+	for _, c := range conflicts {
+		fmt.Fprintf(os.Stderr, "reconciled %s (%s): %s\n", c.FileName, flags.OnConflict, c.Reason)
+	}
+
+	merged, err = util.ApplyIgnores(merged, cfg, flags.SourceRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to apply ignore rules: %v", err)
+		os.Exit(1)
+	}
 
 	if err := util.DumpProfile(flags.OutputFile, merged); err != nil {
 		fmt.Fprintln(os.Stderr, err)
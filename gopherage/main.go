@@ -21,8 +21,11 @@ import (
 
 	"github.com/spf13/cobra"
 	"k8s.io/test-infra/gopherage/cmd/aggregate"
+	"k8s.io/test-infra/gopherage/cmd/convert"
 	"k8s.io/test-infra/gopherage/cmd/diff"
 	"k8s.io/test-infra/gopherage/cmd/filter"
+	"k8s.io/test-infra/gopherage/cmd/function"
+	"k8s.io/test-infra/gopherage/cmd/history"
 	"k8s.io/test-infra/gopherage/cmd/html"
 	"k8s.io/test-infra/gopherage/cmd/junit"
 	"k8s.io/test-infra/gopherage/cmd/merge"
@@ -32,12 +35,20 @@ import (
 var rootCommand = &cobra.Command{
 	Use:   "gopherage",
 	Short: "gopherage is a tool for manipulating Go coverage files.",
+	Long: `gopherage is a tool for manipulating Go coverage files.
+
+Wherever a coverage file is expected, a directory of binary coverage data (as
+written to GOCOVERDIR by binaries built with "go build -cover") may be given
+instead. The counters of every process that wrote to the directory are merged.`,
 }
 
 func run() error {
 	rootCommand.AddCommand(aggregate.MakeCommand())
+	rootCommand.AddCommand(convert.MakeCommand())
 	rootCommand.AddCommand(diff.MakeCommand())
 	rootCommand.AddCommand(filter.MakeCommand())
+	rootCommand.AddCommand(function.MakeCommand())
+	rootCommand.AddCommand(history.MakeCommand())
 	rootCommand.AddCommand(html.MakeCommand())
 	rootCommand.AddCommand(junit.MakeCommand())
 	rootCommand.AddCommand(merge.MakeCommand())
@@ -0,0 +1,175 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config loads the .gopherage.yaml file that declares what is
+// excluded from coverage and which thresholds apply, so that every command
+// treats a repository the same way.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/test-infra/gopherage/pkg/cov/funccov"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultFile is the name of the config file, at the root of a repository.
+const DefaultFile = ".gopherage.yaml"
+
+// Generated code handling.
+const (
+	// GeneratedExclude drops files with a "Code generated ... DO NOT EDIT." header.
+	GeneratedExclude = "exclude"
+	// GeneratedInclude treats generated files like any other.
+	GeneratedInclude = "include"
+)
+
+// Config is the contents of a .gopherage.yaml file.
+type Config struct {
+	// Exclude holds regular expressions; files whose names in the profile
+	// match any of them are excluded.
+	Exclude []string `json:"exclude,omitempty"`
+	// Generated is either "exclude" (the default) or "include".
+	Generated string `json:"generated,omitempty"`
+	// Thresholds override the coverage threshold for files and directories.
+	Thresholds []Threshold `json:"thresholds,omitempty"`
+
+	// Root is the directory containing the config file. Source files are
+	// looked up relative to it, and paths in Thresholds are relative to the
+	// module rooted there, if any.
+	Root string `json:"-"`
+
+	modulePath string
+}
+
+// Threshold is the coverage threshold of everything under a path.
+type Threshold struct {
+	// Path is a directory or file, either as named in the profile, or relative to the module root.
+	Path      string  `json:"path"`
+	Threshold float32 `json:"threshold"`
+}
+
+// Load reads and validates the config file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{}
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if err := c.setRoot(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// New returns an empty config for the repository at root, as if it had been
+// loaded from a config file there.
+func New(root string) (*Config, error) {
+	c := &Config{}
+	if err := c.setRoot(root); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Config) setRoot(root string) error {
+	resolver, err := funccov.NewSourceResolver(root)
+	if err != nil {
+		return fmt.Errorf("failed to read module of %s: %w", root, err)
+	}
+	c.Root = root
+	c.modulePath = resolver.ModulePath
+	return nil
+}
+
+// ParseThreshold parses a threshold of the form "path=threshold".
+func ParseThreshold(s string) (Threshold, error) {
+	path, value, ok := strings.Cut(s, "=")
+	if !ok || path == "" {
+		return Threshold{}, fmt.Errorf("threshold %q is not of the form path=threshold", s)
+	}
+	threshold, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		return Threshold{}, fmt.Errorf("bad threshold in %q: %w", s, err)
+	}
+	t := Threshold{Path: path, Threshold: float32(threshold)}
+	if t.Threshold < 0 || t.Threshold > 1 {
+		return Threshold{}, fmt.Errorf("threshold for %s must be between 0 and 1, inclusive", t.Path)
+	}
+	return t, nil
+}
+
+func (c *Config) validate() error {
+	for _, e := range c.Exclude {
+		if _, err := regexp.Compile(e); err != nil {
+			return fmt.Errorf("bad exclude pattern %q: %w", e, err)
+		}
+	}
+	switch c.Generated {
+	case "", GeneratedExclude, GeneratedInclude:
+	default:
+		return fmt.Errorf("generated must be %q or %q, not %q", GeneratedExclude, GeneratedInclude, c.Generated)
+	}
+	for _, t := range c.Thresholds {
+		if t.Path == "" {
+			return fmt.Errorf("threshold %v has no path", t.Threshold)
+		}
+		if t.Threshold < 0 || t.Threshold > 1 {
+			return fmt.Errorf("threshold for %s must be between 0 and 1, inclusive", t.Path)
+		}
+	}
+	return nil
+}
+
+// ExcludeGenerated returns true if generated files should be excluded.
+// It is safe to call on a nil Config.
+func (c *Config) ExcludeGenerated() bool {
+	return c == nil || c.Generated != GeneratedInclude
+}
+
+// ThresholdFor returns the threshold for the file or directory name, as it
+// appears in a profile. The threshold with the longest path containing name
+// applies, or the last one listed if several have the same path; if there is
+// none, fallback is returned.
+// It is safe to call on a nil Config.
+func (c *Config) ThresholdFor(name string, fallback float32) float32 {
+	if c == nil {
+		return fallback
+	}
+	result, longest := fallback, -1
+	for _, t := range c.Thresholds {
+		p := strings.TrimSuffix(t.Path, "/")
+		if (under(name, p) || c.modulePath != "" && under(name, c.modulePath+"/"+p)) && len(p) >= longest {
+			result, longest = t.Threshold, len(p)
+		}
+	}
+	return result
+}
+
+func under(name, path string) bool {
+	return name == path || strings.HasPrefix(name, path+"/")
+}
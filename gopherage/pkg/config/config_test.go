@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/test-infra/gopherage/pkg/config"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/repo\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+	path := filepath.Join(dir, config.DefaultFile)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+exclude:
+- _fake\.go$
+generated: include
+thresholds:
+- path: pkg
+  threshold: 0.5
+- path: example.com/repo/pkg/strict/
+  threshold: 0.95
+`)
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Root != filepath.Dir(path) {
+		t.Errorf("expected root %s, got %s", filepath.Dir(path), cfg.Root)
+	}
+	if cfg.ExcludeGenerated() {
+		t.Error("expected generated files to be included")
+	}
+
+	tests := []struct {
+		name     string
+		expected float32
+	}{
+		{name: "example.com/repo/pkg/a/a.go", expected: 0.5},
+		{name: "example.com/repo/pkg/strict", expected: 0.95},
+		{name: "example.com/repo/pkg/strict/s.go", expected: 0.95},
+		{name: "example.com/repo/pkg/strictly/s.go", expected: 0.5},
+		{name: "example.com/repo/cmd/main.go", expected: 0.8},
+		{name: "pkg/local.go", expected: 0.5},
+	}
+	for _, tc := range tests {
+		if threshold := cfg.ThresholdFor(tc.name, 0.8); threshold != tc.expected {
+			t.Errorf("expected threshold %v for %s, got %v", tc.expected, tc.name, threshold)
+		}
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "unknown field", content: "excludes: [a]\n"},
+		{name: "bad pattern", content: "exclude: ['(']\n"},
+		{name: "bad generated", content: "generated: maybe\n"},
+		{name: "threshold out of range", content: "thresholds: [{path: pkg, threshold: 80}]\n"},
+		{name: "threshold without path", content: "thresholds: [{threshold: 0.8}]\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := config.Load(writeConfig(t, tc.content)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestNilConfig(t *testing.T) {
+	var cfg *config.Config
+	if !cfg.ExcludeGenerated() {
+		t.Error("expected generated files to be excluded by default")
+	}
+	if threshold := cfg.ThresholdFor("a.go", 0.7); threshold != 0.7 {
+		t.Errorf("expected the fallback threshold, got %v", threshold)
+	}
+}
+
+func TestParseThreshold(t *testing.T) {
+	threshold, err := config.ParseThreshold("pkg/foo=0.9")
+	if err != nil {
+		t.Fatalf("ParseThreshold failed: %v", err)
+	}
+	if expected := (config.Threshold{Path: "pkg/foo", Threshold: 0.9}); threshold != expected {
+		t.Fatalf("expected %+v, got %+v", expected, threshold)
+	}
+
+	for _, s := range []string{"pkg/foo", "=0.9", "pkg/foo=high", "pkg/foo=90"} {
+		if _, err := config.ParseThreshold(s); err == nil {
+			t.Errorf("expected parsing %q to fail", s)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	dir := filepath.Dir(writeConfig(t, ""))
+	cfg, err := config.New(dir)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	cfg.Thresholds = append(cfg.Thresholds, config.Threshold{Path: "pkg", Threshold: 0.6}, config.Threshold{Path: "pkg", Threshold: 0.7})
+	if threshold := cfg.ThresholdFor("example.com/repo/pkg/a.go", 0.8); threshold != 0.7 {
+		t.Errorf("expected the last threshold for a path to win, got %v", threshold)
+	}
+}
@@ -24,16 +24,24 @@ import (
 // coverage profile that counts the number of profiles that hit a block at least
 // once.
 func AggregateProfiles(profiles [][]*cover.Profile) ([]*cover.Profile, error) {
+	result, _, err := AggregateProfilesWithPolicy(profiles, ConflictError)
+	return result, err
+}
+
+// AggregateProfilesWithPolicy behaves like AggregateProfiles, but files whose blocks differ
+// between the profiles are handled according to policy, as in MergeMultipleProfilesWithPolicy.
+// It returns every conflict encountered, in the order they were reconciled.
+func AggregateProfilesWithPolicy(profiles [][]*cover.Profile, policy ConflictPolicy) ([]*cover.Profile, []Conflict, error) {
 	setProfiles := make([][]*cover.Profile, 0, len(profiles))
 	for _, p := range profiles {
 		c := countToBoolean(p)
 		setProfiles = append(setProfiles, c)
 	}
-	aggregateProfiles, err := MergeMultipleProfiles(setProfiles)
+	aggregateProfiles, conflicts, err := MergeMultipleProfilesWithPolicy(setProfiles, policy)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return aggregateProfiles, nil
+	return aggregateProfiles, conflicts, nil
 }
 
 // countToBoolean converts a profile containing hit counts to instead contain
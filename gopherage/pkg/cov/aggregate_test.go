@@ -120,3 +120,41 @@ func TestAggregateProfilesOverlapping(t *testing.T) {
 		t.Fatal("aggregate profile incorrect")
 	}
 }
+
+func TestAggregateProfilesWithPolicy(t *testing.T) {
+	a, b := conflictingProfiles()
+
+	if _, err := cov.AggregateProfiles([][]*cover.Profile{a, b}); err == nil {
+		t.Fatal("expected aggregating conflicting profiles to fail")
+	}
+
+	aggregate, conflicts, err := cov.AggregateProfilesWithPolicy([][]*cover.Profile{a, b}, cov.ConflictUnion)
+	if err != nil {
+		t.Fatalf("AggregateProfilesWithPolicy failed: %v", err)
+	}
+
+	expected := []*cover.Profile{
+		{
+			FileName: "a.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 1, NumStmt: 0, Count: 1},
+				{StartLine: 2, StartCol: 1, EndLine: 6, EndCol: 1, NumStmt: 4, Count: 2},
+				{StartLine: 7, StartCol: 1, EndLine: 13, EndCol: 1, NumStmt: 3, Count: 2},
+			},
+		},
+		{
+			FileName: "b.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 1, NumStmt: 1, Count: 2},
+			},
+		},
+	}
+	if !reflect.DeepEqual(aggregate, expected) {
+		t.Fatalf("bad aggregate result.\n\nexpected: %+v\nactual: %+v", expected, aggregate)
+	}
+	if len(conflicts) != 1 || conflicts[0].FileName != "a.go" {
+		t.Fatalf("expected a single conflict for a.go, got %+v", conflicts)
+	}
+}
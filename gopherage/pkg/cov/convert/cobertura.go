@@ -0,0 +1,112 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package convert
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+	"time"
+
+	"golang.org/x/tools/cover"
+)
+
+const coberturaDoctype = `<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">`
+
+type coberturaCoverage struct {
+	XMLName         xml.Name           `xml:"coverage"`
+	LineRate        float32            `xml:"line-rate,attr"`
+	BranchRate      float32            `xml:"branch-rate,attr"`
+	LinesCovered    int                `xml:"lines-covered,attr"`
+	LinesValid      int                `xml:"lines-valid,attr"`
+	BranchesCovered int                `xml:"branches-covered,attr"`
+	BranchesValid   int                `xml:"branches-valid,attr"`
+	Complexity      float32            `xml:"complexity,attr"`
+	Version         string             `xml:"version,attr"`
+	Timestamp       int64              `xml:"timestamp,attr"`
+	Packages        []coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Name       string           `xml:"name,attr"`
+	LineRate   float32          `xml:"line-rate,attr"`
+	BranchRate float32          `xml:"branch-rate,attr"`
+	Complexity float32          `xml:"complexity,attr"`
+	Classes    []coberturaClass `xml:"classes>class"`
+}
+
+// coberturaClass holds a single source file. Go has no classes, and methods
+// can be spread over several files, so every file is reported as one class.
+type coberturaClass struct {
+	Name       string          `xml:"name,attr"`
+	Filename   string          `xml:"filename,attr"`
+	LineRate   float32         `xml:"line-rate,attr"`
+	BranchRate float32         `xml:"branch-rate,attr"`
+	Complexity float32         `xml:"complexity,attr"`
+	Methods    struct{}        `xml:"methods"`
+	Lines      []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
+
+// WriteCobertura writes profiles to writer as a Cobertura XML report, with the
+// given generation timestamp. Go profiles carry no branch information, so all
+// branch rates are zero.
+func WriteCobertura(profiles []*cover.Profile, timestamp time.Time, writer io.Writer) error {
+	summary := Summarize(profiles)
+	report := coberturaCoverage{
+		LineRate:     summary.Lines.Rate,
+		LinesCovered: summary.Lines.Covered,
+		LinesValid:   summary.Lines.Total,
+		Version:      "gopherage",
+		Timestamp:    timestamp.UnixMilli(),
+	}
+	for _, pkg := range summary.Packages {
+		p := coberturaPackage{Name: pkg.Name, LineRate: pkg.Lines.Rate}
+		for _, file := range pkg.Files {
+			c := coberturaClass{Name: file.Name, Filename: file.Name, LineRate: file.Lines.Rate}
+			for _, line := range sortedLines(file.lineCounts) {
+				c.Lines = append(c.Lines, coberturaLine{Number: line, Hits: file.lineCounts[line]})
+			}
+			p.Classes = append(p.Classes, c)
+		}
+		report.Packages = append(report.Packages, p)
+	}
+
+	if _, err := io.WriteString(writer, xml.Header+coberturaDoctype+"\n"); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
+
+func sortedLines(lineCounts map[int]int) []int {
+	lines := make([]int, 0, len(lineCounts))
+	for line := range lineCounts {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+	return lines
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package convert_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/cov"
+	"k8s.io/test-infra/gopherage/pkg/cov/convert"
+)
+
+var profiles = []*cover.Profile{
+	{
+		FileName: "example.com/a/a.go",
+		Mode:     "count",
+		Blocks: []cover.ProfileBlock{
+			{StartLine: 1, StartCol: 14, EndLine: 2, EndCol: 13, NumStmt: 3, Count: 2},
+			{StartLine: 3, StartCol: 4, EndLine: 5, EndCol: 1, NumStmt: 1, Count: 0},
+		},
+	},
+	{
+		FileName: "example.com/b/b.go",
+		Mode:     "count",
+		Blocks: []cover.ProfileBlock{
+			{StartLine: 4, StartCol: 2, EndLine: 4, EndCol: 20, NumStmt: 2, Count: 1},
+		},
+	},
+}
+
+func TestSummarize(t *testing.T) {
+	summary := convert.Summarize(profiles)
+
+	expected := convert.Summary{
+		Statements: convert.Rate{Covered: 5, Total: 6, Rate: 5.0 / 6},
+		Lines:      convert.Rate{Covered: 3, Total: 5, Rate: 3.0 / 5},
+		Packages: []convert.PackageSummary{
+			{
+				Name:       "example.com/a",
+				Statements: convert.Rate{Covered: 3, Total: 4, Rate: 3.0 / 4},
+				Lines:      convert.Rate{Covered: 2, Total: 4, Rate: 2.0 / 4},
+			},
+			{
+				Name:       "example.com/b",
+				Statements: convert.Rate{Covered: 2, Total: 2, Rate: 1},
+				Lines:      convert.Rate{Covered: 1, Total: 1, Rate: 1},
+			},
+		},
+	}
+
+	if len(summary.Packages) != 2 || len(summary.Packages[0].Files) != 1 || len(summary.Packages[1].Files) != 1 {
+		t.Fatalf("expected one file in each of two packages, got %+v", summary.Packages)
+	}
+	if name := summary.Packages[0].Files[0].Name; name != "example.com/a/a.go" {
+		t.Errorf("expected file example.com/a/a.go, got %s", name)
+	}
+	for i := range summary.Packages {
+		summary.Packages[i].Files = nil
+	}
+	if !reflect.DeepEqual(*summary, expected) {
+		t.Fatalf("bad result.\n\nexpected: %+v\nactual: %+v", expected, *summary)
+	}
+}
+
+func TestWriteLCOV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := convert.WriteLCOV(profiles, &buf); err != nil {
+		t.Fatalf("WriteLCOV failed: %v", err)
+	}
+
+	expected := `TN:
+SF:example.com/a/a.go
+DA:1,2
+DA:2,2
+DA:3,0
+DA:4,0
+LF:4
+LH:2
+end_of_record
+TN:
+SF:example.com/b/b.go
+DA:4,1
+LF:1
+LH:1
+end_of_record
+`
+	if buf.String() != expected {
+		t.Fatalf("bad result.\n\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+}
+
+func TestParseLCOV(t *testing.T) {
+	input := `TN:unit
+SF:/src/lib.c
+FN:3,main
+FNDA:1,main
+DA:3,1
+DA:4,0,Ng1lGmrxOo+ZdCoylIvaXQ
+BRDA:4,0,0,-
+LF:2
+LH:1
+end_of_record
+TN:integration
+SF:/src/lib.c
+DA:4,2
+end_of_record
+SF:/src/a.c
+DA:10,5
+end_of_record
+`
+	result, err := convert.ParseLCOV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseLCOV failed: %v", err)
+	}
+
+	expected := []*cover.Profile{
+		{
+			FileName: "/src/a.c",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 10, StartCol: 1, EndLine: 11, EndCol: 1, NumStmt: 1, Count: 5},
+			},
+		},
+		{
+			FileName: "/src/lib.c",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 3, StartCol: 1, EndLine: 4, EndCol: 1, NumStmt: 1, Count: 1},
+				{StartLine: 4, StartCol: 1, EndLine: 5, EndCol: 1, NumStmt: 1, Count: 2},
+			},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("bad result.\n\nexpected: %+v\nactual: %+v", expected, result)
+	}
+}
+
+func TestParseLCOVErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "DA outside record", input: "TN:\nDA:1,1\n"},
+		{name: "missing count", input: "SF:a.c\nDA:1\n"},
+		{name: "bad line number", input: "SF:a.c\nDA:x,1\n"},
+		{name: "negative count", input: "SF:a.c\nDA:1,-1\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := convert.ParseLCOV(strings.NewReader(tc.input)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestParseLCOVMergesWithGo(t *testing.T) {
+	lcov, err := convert.ParseLCOV(strings.NewReader("SF:/src/lib.c\nDA:3,1\nend_of_record\n"))
+	if err != nil {
+		t.Fatalf("ParseLCOV failed: %v", err)
+	}
+	merged, err := cov.MergeProfiles(profiles, lcov)
+	if err != nil {
+		t.Fatalf("MergeProfiles failed: %v", err)
+	}
+	var names []string
+	for _, p := range merged {
+		names = append(names, p.FileName)
+	}
+	expected := []string{"/src/lib.c", "example.com/a/a.go", "example.com/b/b.go"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("bad result.\n\nexpected: %v\nactual: %v", expected, names)
+	}
+}
+
+func TestLCOVRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := convert.WriteLCOV(profiles, &buf); err != nil {
+		t.Fatalf("WriteLCOV failed: %v", err)
+	}
+	if !convert.IsLCOV(buf.Bytes()) {
+		t.Fatal("IsLCOV did not recognise LCOV output")
+	}
+	parsed, err := convert.ParseLCOV(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ParseLCOV failed: %v", err)
+	}
+	for i := range profiles {
+		expected := cov.LineCounts(profiles[i])
+		if actual := cov.LineCounts(parsed[i]); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("line counts of %s changed.\n\nexpected: %v\nactual: %v", profiles[i].FileName, expected, actual)
+		}
+	}
+}
+
+func TestIsLCOV(t *testing.T) {
+	if convert.IsLCOV([]byte("mode: set\na.go:1.1,2.1 1 1\n")) {
+		t.Error("Go profile recognised as LCOV")
+	}
+	if !convert.IsLCOV([]byte("\nSF:a.c\nend_of_record\n")) {
+		t.Error("LCOV without a TN record not recognised")
+	}
+}
+
+func TestWriteCobertura(t *testing.T) {
+	var buf bytes.Buffer
+	if err := convert.WriteCobertura(profiles[1:], time.UnixMilli(1234), &buf); err != nil {
+		t.Fatalf("WriteCobertura failed: %v", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">
+<coverage line-rate="1" branch-rate="0" lines-covered="1" lines-valid="1" branches-covered="0" branches-valid="0" complexity="0" version="gopherage" timestamp="1234">
+  <packages>
+    <package name="example.com/b" line-rate="1" branch-rate="0" complexity="0">
+      <classes>
+        <class name="example.com/b/b.go" filename="example.com/b/b.go" line-rate="1" branch-rate="0" complexity="0">
+          <methods></methods>
+          <lines>
+            <line number="4" hits="1"></line>
+          </lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>
+`
+	if buf.String() != expected {
+		t.Fatalf("bad result.\n\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package convert
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/cov"
+)
+
+// WriteLCOV writes profiles to writer as an LCOV tracefile, with one DA record
+// for every line that is part of a coverage block.
+func WriteLCOV(profiles []*cover.Profile, writer io.Writer) error {
+	w := bufio.NewWriter(writer)
+	for _, profile := range profiles {
+		lineCounts := cov.LineCounts(profile)
+		fmt.Fprintf(w, "TN:\nSF:%s\n", profile.FileName)
+		hit := 0
+		for _, line := range sortedLines(lineCounts) {
+			count := lineCounts[line]
+			if count > 0 {
+				hit++
+			}
+			fmt.Fprintf(w, "DA:%d,%d\n", line, count)
+		}
+		fmt.Fprintf(w, "LF:%d\nLH:%d\nend_of_record\n", len(lineCounts), hit)
+	}
+	return w.Flush()
+}
+
+// IsLCOV returns true if data looks like an LCOV tracefile rather than a Go
+// coverage profile.
+func IsLCOV(data []byte) bool {
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		return bytes.HasPrefix(line, []byte("TN:")) || bytes.HasPrefix(line, []byte("SF:"))
+	}
+	return false
+}
+
+// ParseLCOV reads an LCOV tracefile and returns it as Go coverage profiles in
+// "count" mode, sorted by file name, so that it can be merged with profiles
+// of Go code. Every line with a DA record becomes a block covering that whole
+// line with a single statement. Records for the same file are summed.
+// Function and branch records are ignored.
+func ParseLCOV(reader io.Reader) ([]*cover.Profile, error) {
+	files := map[string]map[int]int{}
+	var current map[int]int
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			name := strings.TrimPrefix(line, "SF:")
+			if _, ok := files[name]; !ok {
+				files[name] = map[int]int{}
+			}
+			current = files[name]
+		case strings.HasPrefix(line, "DA:"):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: DA record outside of a source file record", lineNumber)
+			}
+			// DA:<line number>,<execution count>[,<checksum>]
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("line %d: malformed DA record %q", lineNumber, line)
+			}
+			number, err := strconv.Atoi(fields[0])
+			if err != nil || number < 1 {
+				return nil, fmt.Errorf("line %d: bad line number in %q", lineNumber, line)
+			}
+			count, err := strconv.Atoi(fields[1])
+			if err != nil || count < 0 {
+				return nil, fmt.Errorf("line %d: bad execution count in %q", lineNumber, line)
+			}
+			current[number] += count
+		case line == "end_of_record":
+			current = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read LCOV data: %w", err)
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	profiles := make([]*cover.Profile, 0, len(names))
+	for _, name := range names {
+		profile := &cover.Profile{FileName: name, Mode: "count", Blocks: []cover.ProfileBlock{}}
+		for _, line := range sortedLines(files[name]) {
+			profile.Blocks = append(profile.Blocks, cover.ProfileBlock{
+				StartLine: line,
+				StartCol:  1,
+				EndLine:   line + 1,
+				EndCol:    1,
+				NumStmt:   1,
+				Count:     files[name][line],
+			})
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
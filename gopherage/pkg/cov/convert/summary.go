@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package convert converts Go coverage profiles to and from the formats
+// understood by other coverage tools.
+package convert
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"sort"
+
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/cov"
+	"k8s.io/test-infra/gopherage/pkg/cov/junit/calculation"
+)
+
+// Rate counts how many of some kind of item (statements or lines) are covered.
+type Rate struct {
+	Covered int     `json:"covered"`
+	Total   int     `json:"total"`
+	Rate    float32 `json:"rate"`
+}
+
+func newRate(covered, total int) Rate {
+	c := calculation.Coverage{NumCoveredStmts: covered, NumAllStmts: total}
+	return Rate{Covered: covered, Total: total, Rate: c.Ratio()}
+}
+
+// FileSummary is the coverage of a single source file.
+type FileSummary struct {
+	Name       string `json:"name"`
+	Statements Rate   `json:"statements"`
+	Lines      Rate   `json:"lines"`
+
+	// lineCounts are the hit counts of each line in the file.
+	lineCounts map[int]int
+}
+
+// PackageSummary is the coverage of all the files in a directory.
+type PackageSummary struct {
+	Name       string        `json:"name"`
+	Statements Rate          `json:"statements"`
+	Lines      Rate          `json:"lines"`
+	Files      []FileSummary `json:"files"`
+}
+
+// Summary is the coverage of a whole profile, broken down by package.
+type Summary struct {
+	Statements Rate             `json:"statements"`
+	Lines      Rate             `json:"lines"`
+	Packages   []PackageSummary `json:"packages"`
+}
+
+// Summarize computes the statement and line coverage of profiles, in total
+// and for each package (directory) and file. Packages are sorted by name.
+func Summarize(profiles []*cover.Profile) *Summary {
+	covList := calculation.ProduceCovList(profiles)
+	lineCounts := make(map[string]map[int]int, len(profiles))
+	for _, profile := range profiles {
+		lineCounts[profile.FileName] = cov.LineCounts(profile)
+	}
+
+	dirs := covList.ListDirectories()
+	sort.Strings(dirs)
+
+	summary := &Summary{Packages: []PackageSummary{}}
+	var coveredLines, allLines int
+	for _, dir := range dirs {
+		pkg := PackageSummary{Name: dir}
+		var pkgCoveredStmts, pkgAllStmts, pkgCoveredLines, pkgAllLines int
+		for _, c := range covList.Group {
+			if path.Dir(c.Name) != dir {
+				continue
+			}
+			file := FileSummary{
+				Name:       c.Name,
+				Statements: newRate(c.NumCoveredStmts, c.NumAllStmts),
+				lineCounts: lineCounts[c.Name],
+			}
+			var covered int
+			for _, count := range file.lineCounts {
+				if count > 0 {
+					covered++
+				}
+			}
+			file.Lines = newRate(covered, len(file.lineCounts))
+			pkg.Files = append(pkg.Files, file)
+
+			pkgCoveredStmts += c.NumCoveredStmts
+			pkgAllStmts += c.NumAllStmts
+			pkgCoveredLines += covered
+			pkgAllLines += len(file.lineCounts)
+		}
+		pkg.Statements = newRate(pkgCoveredStmts, pkgAllStmts)
+		pkg.Lines = newRate(pkgCoveredLines, pkgAllLines)
+		summary.Packages = append(summary.Packages, pkg)
+		coveredLines += pkgCoveredLines
+		allLines += pkgAllLines
+	}
+
+	total := covList.Ratio()
+	summary.Statements = Rate{Covered: covList.NumCoveredStmts, Total: covList.NumAllStmts, Rate: total}
+	summary.Lines = newRate(coveredLines, allLines)
+	return summary
+}
+
+// WriteJSON writes the summary of profiles to writer as JSON.
+func WriteJSON(profiles []*cover.Profile, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(Summarize(profiles))
+}
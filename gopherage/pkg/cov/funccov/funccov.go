@@ -0,0 +1,176 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package funccov calculates coverage per function by matching profile blocks
+// against the function declarations in the profiled source.
+package funccov
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+
+	"golang.org/x/tools/cover"
+)
+
+// Coverage stores test coverage summary data for one function
+type Coverage struct {
+	FileName        string `json:"file"`
+	FuncName        string `json:"function"`
+	Line            int    `json:"line"`
+	Exported        bool   `json:"exported"`
+	NumCoveredStmts int    `json:"covered_statements"`
+	NumAllStmts     int    `json:"statements"`
+}
+
+// Name returns the name of the function qualified by its file
+func (c *Coverage) Name() string {
+	return c.FileName + ":" + c.FuncName
+}
+
+// Ratio returns the percentage of statements that are covered
+func (c *Coverage) Ratio() float32 {
+	if c.NumAllStmts == 0 {
+		return 1
+	}
+	return float32(c.NumCoveredStmts) / float32(c.NumAllStmts)
+}
+
+// extent is the span of a function declaration in its source file.
+type extent struct {
+	name      string
+	exported  bool
+	startLine int
+	startCol  int
+	endLine   int
+	endCol    int
+}
+
+// Summarize parses the source of every file referenced by profiles and returns
+// the coverage of each function declared in them, ordered by file name and then
+// by position in the file.
+func Summarize(profiles []*cover.Profile, resolver *SourceResolver) ([]Coverage, error) {
+	var result []Coverage
+	for _, profile := range profiles {
+		path := resolver.Resolve(profile.FileName)
+		extents, err := findFuncs(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find functions in %s: %w", profile.FileName, err)
+		}
+		for _, e := range extents {
+			c := Coverage{
+				FileName: profile.FileName,
+				FuncName: e.name,
+				Line:     e.startLine,
+				Exported: e.exported,
+			}
+			for _, b := range profile.Blocks {
+				if !e.contains(b) {
+					continue
+				}
+				c.NumAllStmts += b.NumStmt
+				if b.Count > 0 {
+					c.NumCoveredStmts += b.NumStmt
+				}
+			}
+			result = append(result, c)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].FileName < result[j].FileName })
+	return result, nil
+}
+
+// Total sums the coverage of all the given functions.
+func Total(funcs []Coverage) Coverage {
+	total := Coverage{FuncName: "total"}
+	for _, f := range funcs {
+		total.NumAllStmts += f.NumAllStmts
+		total.NumCoveredStmts += f.NumCoveredStmts
+	}
+	return total
+}
+
+func (e extent) contains(b cover.ProfileBlock) bool {
+	if b.StartLine > e.endLine || (b.StartLine == e.endLine && b.StartCol >= e.endCol) {
+		return false
+	}
+	if b.EndLine < e.startLine || (b.EndLine == e.startLine && b.EndCol <= e.startCol) {
+		return false
+	}
+	return true
+}
+
+func findFuncs(path string) ([]extent, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	var extents []extent
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		// Functions without bodies are implemented in assembly, and have no coverage.
+		if !ok || fn.Body == nil {
+			continue
+		}
+		start := fset.Position(fn.Pos())
+		end := fset.Position(fn.End())
+		name, exported := funcName(fn)
+		extents = append(extents, extent{
+			name:      name,
+			exported:  exported,
+			startLine: start.Line,
+			startCol:  start.Column,
+			endLine:   end.Line,
+			endCol:    end.Column,
+		})
+	}
+	return extents, nil
+}
+
+// funcName returns the name of fn as it would be written to call it,
+// e.g. "(*Foo).Bar" for a method on a pointer receiver, and whether it is
+// reachable from outside its package.
+func funcName(fn *ast.FuncDecl) (string, bool) {
+	exported := fn.Name.IsExported()
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name, exported
+	}
+	recv := fn.Recv.List[0].Type
+	pointer := false
+	if star, ok := recv.(*ast.StarExpr); ok {
+		pointer = true
+		recv = star.X
+	}
+	// Strip any type parameters from generic receivers.
+	switch r := recv.(type) {
+	case *ast.IndexExpr:
+		recv = r.X
+	case *ast.IndexListExpr:
+		recv = r.X
+	}
+	typeName := "?"
+	if ident, ok := recv.(*ast.Ident); ok {
+		typeName = ident.Name
+		exported = exported && ident.IsExported()
+	}
+	if pointer {
+		return fmt.Sprintf("(*%s).%s", typeName, fn.Name.Name), exported
+	}
+	return fmt.Sprintf("%s.%s", typeName, fn.Name.Name), exported
+}
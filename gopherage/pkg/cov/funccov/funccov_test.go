@@ -0,0 +1,144 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package funccov_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/cov/funccov"
+)
+
+const source = `package foo
+
+func Exported(n int) int {
+	if n > 0 {
+		return n
+	}
+	return -n
+}
+
+type thing struct{}
+
+func (t *thing) Method() {
+	println("hi")
+}
+
+func (t thing) Untested() {
+	println("bye")
+}
+`
+
+func writeModule(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/mod\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "foo"), 0755); err != nil {
+		t.Fatalf("failed to create package directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "foo", "foo.go"), []byte(source), 0644); err != nil {
+		t.Fatalf("failed to write source: %v", err)
+	}
+	return dir
+}
+
+var profiles = []*cover.Profile{
+	{
+		FileName: "example.com/mod/foo/foo.go",
+		Mode:     "count",
+		Blocks: []cover.ProfileBlock{
+			{StartLine: 3, StartCol: 26, EndLine: 4, EndCol: 11, NumStmt: 1, Count: 2},
+			{StartLine: 4, StartCol: 11, EndLine: 6, EndCol: 3, NumStmt: 1, Count: 0},
+			{StartLine: 7, StartCol: 2, EndLine: 7, EndCol: 11, NumStmt: 1, Count: 2},
+			{StartLine: 12, StartCol: 26, EndLine: 14, EndCol: 2, NumStmt: 1, Count: 1},
+			{StartLine: 16, StartCol: 27, EndLine: 18, EndCol: 2, NumStmt: 1, Count: 0},
+		},
+	},
+}
+
+func TestSummarize(t *testing.T) {
+	resolver, err := funccov.NewSourceResolver(writeModule(t))
+	if err != nil {
+		t.Fatalf("failed to create resolver: %v", err)
+	}
+	if resolver.ModulePath != "example.com/mod" {
+		t.Errorf("expected module path example.com/mod, got %q", resolver.ModulePath)
+	}
+
+	funcs, err := funccov.Summarize(profiles, resolver)
+	if err != nil {
+		t.Fatalf("Summarize failed: %v", err)
+	}
+
+	expected := []funccov.Coverage{
+		{FileName: "example.com/mod/foo/foo.go", FuncName: "Exported", Line: 3, Exported: true, NumCoveredStmts: 2, NumAllStmts: 3},
+		{FileName: "example.com/mod/foo/foo.go", FuncName: "(*thing).Method", Line: 12, NumCoveredStmts: 1, NumAllStmts: 1},
+		{FileName: "example.com/mod/foo/foo.go", FuncName: "thing.Untested", Line: 16, NumCoveredStmts: 0, NumAllStmts: 1},
+	}
+	if !reflect.DeepEqual(funcs, expected) {
+		t.Errorf("bad result.\nexpected: %+v\nactual:   %+v", expected, funcs)
+	}
+}
+
+func TestSummarizeMissingSource(t *testing.T) {
+	resolver := &funccov.SourceResolver{Root: t.TempDir()}
+	if _, err := funccov.Summarize(profiles, resolver); err == nil {
+		t.Error("expected an error when the source file is missing")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	resolver := &funccov.SourceResolver{Root: "/src", ModulePath: "example.com/mod"}
+	tests := []struct {
+		fileName string
+		expected string
+	}{
+		{fileName: "example.com/mod/foo/foo.go", expected: "/src/foo/foo.go"},
+		{fileName: "foo/foo.go", expected: "/src/foo/foo.go"},
+		{fileName: "/abs/foo.go", expected: "/abs/foo.go"},
+		{fileName: "example.com/module/foo.go", expected: "/src/example.com/module/foo.go"},
+	}
+	for _, tc := range tests {
+		if actual := resolver.Resolve(tc.fileName); actual != filepath.FromSlash(tc.expected) {
+			t.Errorf("Resolve(%q): expected %q, got %q", tc.fileName, tc.expected, actual)
+		}
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	funcs := []funccov.Coverage{
+		{FileName: "a.go", FuncName: "Foo", Line: 3, NumCoveredStmts: 1, NumAllStmts: 4},
+		{FileName: "a.go", FuncName: "(*T).LongerName", Line: 10, NumCoveredStmts: 2, NumAllStmts: 2},
+	}
+	expected := "a.go:3:\t\tFoo\t\t25.0%\n" +
+		"a.go:10:\t(*T).LongerName\t100.0%\n" +
+		"total:\t\t(statements)\t50.0%\n"
+
+	var buffer bytes.Buffer
+	if err := funccov.WriteText(funcs, &buffer); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	if buffer.String() != expected {
+		t.Errorf("bad result.\n\nexpected:\n%q\nactual:\n%q\n", expected, buffer.String())
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package funccov
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// WriteText writes funcs to writer in the same format as `go tool cover -func`.
+func WriteText(funcs []Coverage, writer io.Writer) error {
+	tw := tabwriter.NewWriter(writer, 1, 8, 1, '\t', 0)
+	for _, f := range funcs {
+		if _, err := fmt.Fprintf(tw, "%s:%d:\t%s\t%.1f%%\n", f.FileName, f.Line, f.FuncName, 100*f.Ratio()); err != nil {
+			return err
+		}
+	}
+	total := Total(funcs)
+	if _, err := fmt.Fprintf(tw, "total:\t(statements)\t%.1f%%\n", 100*total.Ratio()); err != nil {
+		return err
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes funcs to writer as a JSON array.
+func WriteJSON(funcs []Coverage, writer io.Writer) error {
+	if funcs == nil {
+		funcs = []Coverage{}
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(funcs)
+}
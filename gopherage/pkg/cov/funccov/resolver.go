@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package funccov
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SourceResolver maps the file names recorded in coverage profiles, which are
+// usually import paths, to source files on disk.
+type SourceResolver struct {
+	// Root is the directory that source files are looked up in.
+	Root string
+	// ModulePath is the import path of the module rooted at Root. Profile file
+	// names in this module are resolved relative to Root.
+	ModulePath string
+}
+
+// NewSourceResolver returns a SourceResolver for the source tree at root. If
+// root contains a go.mod file, the module path is read from it.
+func NewSourceResolver(root string) (*SourceResolver, error) {
+	r := &SourceResolver{Root: root}
+	f, err := os.Open(filepath.Join(root, "go.mod"))
+	if os.IsNotExist(err) {
+		return r, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "module" {
+			r.ModulePath = strings.Trim(fields[1], `"`)
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	return r, nil
+}
+
+// Resolve returns the path on disk of the given profile file name.
+func (r *SourceResolver) Resolve(fileName string) string {
+	if filepath.IsAbs(fileName) {
+		return fileName
+	}
+	if r.ModulePath != "" && strings.HasPrefix(fileName, r.ModulePath+"/") {
+		return filepath.Join(r.Root, filepath.FromSlash(strings.TrimPrefix(fileName, r.ModulePath+"/")))
+	}
+	return filepath.Join(r.Root, filepath.FromSlash(fileName))
+}
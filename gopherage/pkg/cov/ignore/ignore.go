@@ -0,0 +1,215 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ignore removes code from coverage profiles that is excluded by a
+// config file or by annotations in the source.
+package ignore
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/config"
+	"k8s.io/test-infra/gopherage/pkg/cov"
+	"k8s.io/test-infra/gopherage/pkg/cov/funccov"
+)
+
+// Marker is the comment that excludes code from coverage. It applies to the
+// function declaration or statement that starts on the line after the comment
+// (or after the comment group containing it), or on the same line if the
+// comment follows code. Before the package clause, it excludes the whole file.
+// Only whole coverage blocks are excluded, so it is most useful on functions
+// and on compound statements such as if, for and switch.
+const Marker = "//coverage:ignore"
+
+type position struct {
+	line, col int
+}
+
+func (p position) before(o position) bool {
+	return p.line < o.line || p.line == o.line && p.col < o.col
+}
+
+// region is a range of source, from start to end exclusive.
+type region struct {
+	start, end position
+}
+
+func (r region) contains(b cover.ProfileBlock) bool {
+	return !position{b.StartLine, b.StartCol}.before(r.start) && !r.end.before(position{b.EndLine, b.EndCol})
+}
+
+// Rules is what a source file says should be excluded from its coverage.
+type Rules struct {
+	// Generated is true if the file has a "Code generated ... DO NOT EDIT." header.
+	Generated bool
+	// WholeFile is true if the file is annotated to be ignored entirely.
+	WholeFile bool
+
+	regions []region
+}
+
+// ParseSource reads the rules in a Go source file. src may be nil, in which
+// case the file is read from filename.
+func ParseSource(filename string, src []byte) (*Rules, error) {
+	if src == nil {
+		var err error
+		if src, err = os.ReadFile(filename); err != nil {
+			return nil, err
+		}
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	rules := &Rules{Generated: ast.IsGenerated(file)}
+
+	// Lines on which an annotated node starts.
+	targets := map[int]bool{}
+	tf := fset.File(file.Pos())
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			if !isMarker(c.Text) {
+				continue
+			}
+			if c.Pos() < file.Package {
+				rules.WholeFile = true
+				return rules, nil
+			}
+			targets[fset.Position(c.Pos()).Line] = true
+			// A marker that follows code only applies to that code.
+			if ownLine(tf, src, group.Pos()) {
+				targets[fset.Position(group.End()).Line+1] = true
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return rules, nil
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.FuncDecl, ast.Stmt:
+		default:
+			return true
+		}
+		start, end := fset.Position(n.Pos()), fset.Position(n.End())
+		if !targets[start.Line] {
+			return true
+		}
+		rules.regions = append(rules.regions, region{
+			start: position{start.Line, start.Column},
+			end:   position{end.Line, end.Column},
+		})
+		// Anything inside is already covered by this region.
+		return false
+	})
+	return rules, nil
+}
+
+// ownLine returns true if there is nothing but whitespace before pos on its line.
+func ownLine(tf *token.File, src []byte, pos token.Pos) bool {
+	start := tf.Offset(tf.LineStart(tf.Line(pos)))
+	return strings.TrimSpace(string(src[start:tf.Offset(pos)])) == ""
+}
+
+func isMarker(text string) bool {
+	rest, ok := strings.CutPrefix(text, Marker)
+	return ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
+
+// Filter returns the blocks of profile that are not excluded by r, or nil if
+// the whole file is excluded.
+func (r *Rules) Filter(profile *cover.Profile) *cover.Profile {
+	if r.WholeFile {
+		return nil
+	}
+	if len(r.regions) == 0 {
+		return profile
+	}
+	p := &cover.Profile{FileName: profile.FileName, Mode: profile.Mode}
+	for _, b := range profile.Blocks {
+		ignored := false
+		for _, reg := range r.regions {
+			if reg.contains(b) {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			p.Blocks = append(p.Blocks, b)
+		}
+	}
+	return p
+}
+
+// Apply removes from profiles the files excluded by cfg, which may be nil,
+// and the code excluded by annotations in the source files, which are looked
+// up with resolver. Files whose source can't be found or parsed, such as
+// those imported from LCOV, are only subject to cfg. Files left with no
+// blocks by the annotations are dropped.
+func Apply(profiles []*cover.Profile, cfg *config.Config, resolver *funccov.SourceResolver) ([]*cover.Profile, error) {
+	return apply(profiles, cfg, resolver, true)
+}
+
+// ApplyFiles is like Apply, but only removes whole files: those excluded by
+// cfg, generated files and files annotated to be ignored entirely. It suits
+// profiles recorded from another revision of the source than the one resolver
+// finds, on which annotations of single lines would land on the wrong blocks.
+func ApplyFiles(profiles []*cover.Profile, cfg *config.Config, resolver *funccov.SourceResolver) ([]*cover.Profile, error) {
+	return apply(profiles, cfg, resolver, false)
+}
+
+func apply(profiles []*cover.Profile, cfg *config.Config, resolver *funccov.SourceResolver, lines bool) ([]*cover.Profile, error) {
+	if cfg != nil && len(cfg.Exclude) > 0 {
+		var err error
+		if profiles, err = cov.FilterProfilePaths(profiles, cfg.Exclude, false); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]*cover.Profile, 0, len(profiles))
+	for _, profile := range profiles {
+		src, err := os.ReadFile(resolver.Resolve(profile.FileName))
+		if err != nil {
+			result = append(result, profile)
+			continue
+		}
+		rules, err := ParseSource(profile.FileName, src)
+		if err != nil {
+			result = append(result, profile)
+			continue
+		}
+		if rules.Generated && cfg.ExcludeGenerated() {
+			continue
+		}
+		if !lines {
+			if !rules.WholeFile {
+				result = append(result, profile)
+			}
+			continue
+		}
+		if p := rules.Filter(profile); p != nil && (len(p.Blocks) > 0 || len(profile.Blocks) == 0) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
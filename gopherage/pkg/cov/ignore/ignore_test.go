@@ -0,0 +1,240 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ignore_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/config"
+	"k8s.io/test-infra/gopherage/pkg/cov/funccov"
+	"k8s.io/test-infra/gopherage/pkg/cov/ignore"
+)
+
+const annotated = `package a
+
+// Ignored is not worth testing.
+//coverage:ignore
+func Ignored() {
+	println()
+}
+
+func Partly(x int) {
+	if x > 0 { //coverage:ignore because it can't happen
+		panic(x)
+	}
+	//coverage:ignore
+	for {
+		break
+	}
+	println()
+}
+
+//coverage:ignored is not the marker
+func Kept() {
+	println()
+}
+`
+
+func TestFilter(t *testing.T) {
+	rules, err := ignore.ParseSource("a.go", []byte(annotated))
+	if err != nil {
+		t.Fatalf("ParseSource failed: %v", err)
+	}
+	profile := &cover.Profile{
+		FileName: "a.go",
+		Mode:     "count",
+		Blocks: []cover.ProfileBlock{
+			{StartLine: 5, StartCol: 16, EndLine: 7, EndCol: 2, NumStmt: 1, Count: 0},
+			{StartLine: 9, StartCol: 20, EndLine: 10, EndCol: 11, NumStmt: 1, Count: 1},
+			{StartLine: 10, StartCol: 11, EndLine: 12, EndCol: 3, NumStmt: 1, Count: 0},
+			{StartLine: 14, StartCol: 8, EndLine: 16, EndCol: 3, NumStmt: 1, Count: 0},
+			{StartLine: 17, StartCol: 2, EndLine: 17, EndCol: 11, NumStmt: 1, Count: 1},
+			{StartLine: 21, StartCol: 13, EndLine: 23, EndCol: 2, NumStmt: 1, Count: 0},
+		},
+	}
+
+	expected := &cover.Profile{
+		FileName: "a.go",
+		Mode:     "count",
+		Blocks: []cover.ProfileBlock{
+			{StartLine: 9, StartCol: 20, EndLine: 10, EndCol: 11, NumStmt: 1, Count: 1},
+			{StartLine: 17, StartCol: 2, EndLine: 17, EndCol: 11, NumStmt: 1, Count: 1},
+			{StartLine: 21, StartCol: 13, EndLine: 23, EndCol: 2, NumStmt: 1, Count: 0},
+		},
+	}
+	if result := rules.Filter(profile); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("bad result.\n\nexpected: %+v\nactual: %+v", expected, result)
+	}
+}
+
+func TestFilterTrailingMarker(t *testing.T) {
+	src := `package a
+
+func Counted(x int) int {
+	x++ //coverage:ignore
+	if x > 1 {
+		x--
+	}
+	return x
+}
+`
+	rules, err := ignore.ParseSource("a.go", []byte(src))
+	if err != nil {
+		t.Fatalf("ParseSource failed: %v", err)
+	}
+	profile := &cover.Profile{
+		FileName: "a.go",
+		Mode:     "count",
+		Blocks: []cover.ProfileBlock{
+			{StartLine: 4, StartCol: 2, EndLine: 4, EndCol: 5, NumStmt: 1, Count: 1},
+			{StartLine: 5, StartCol: 2, EndLine: 5, EndCol: 11, NumStmt: 1, Count: 1},
+			{StartLine: 5, StartCol: 11, EndLine: 7, EndCol: 3, NumStmt: 1, Count: 0},
+			{StartLine: 8, StartCol: 2, EndLine: 8, EndCol: 10, NumStmt: 1, Count: 1},
+		},
+	}
+
+	expected := &cover.Profile{
+		FileName: "a.go",
+		Mode:     "count",
+		Blocks:   profile.Blocks[1:],
+	}
+	if result := rules.Filter(profile); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("bad result.\n\nexpected: %+v\nactual: %+v", expected, result)
+	}
+}
+
+func TestParseSourceFileRules(t *testing.T) {
+	rules, err := ignore.ParseSource("gen.go", []byte("// Code generated by hand. DO NOT EDIT.\n\npackage a\n"))
+	if err != nil {
+		t.Fatalf("ParseSource failed: %v", err)
+	}
+	if !rules.Generated || rules.WholeFile {
+		t.Errorf("expected a generated file, got %+v", rules)
+	}
+
+	rules, err = ignore.ParseSource("skip.go", []byte("//coverage:ignore\n\npackage a\n"))
+	if err != nil {
+		t.Fatalf("ParseSource failed: %v", err)
+	}
+	if rules.Generated || !rules.WholeFile {
+		t.Errorf("expected a wholly ignored file, got %+v", rules)
+	}
+}
+
+func TestApply(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":      "module example.com/repo\n",
+		"a/a.go":      annotated,
+		"a/gen.go":    "// Code generated by hand. DO NOT EDIT.\n\npackage a\n\nfunc G() {\n}\n",
+		"a/a_fake.go": "package a\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	resolver, err := funccov.NewSourceResolver(root)
+	if err != nil {
+		t.Fatalf("NewSourceResolver failed: %v", err)
+	}
+
+	block := cover.ProfileBlock{StartLine: 5, StartCol: 16, EndLine: 7, EndCol: 2, NumStmt: 1}
+	profiles := []*cover.Profile{
+		{FileName: "example.com/repo/a/a.go", Mode: "set", Blocks: []cover.ProfileBlock{block}},
+		{FileName: "example.com/repo/a/a_fake.go", Mode: "set", Blocks: []cover.ProfileBlock{block}},
+		{FileName: "example.com/repo/a/gen.go", Mode: "set", Blocks: []cover.ProfileBlock{block}},
+		{FileName: "example.com/repo/a/missing.go", Mode: "set", Blocks: []cover.ProfileBlock{block}},
+	}
+
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		expected []string
+	}{
+		{
+			name:     "no config",
+			expected: []string{"example.com/repo/a/a_fake.go", "example.com/repo/a/missing.go"},
+		},
+		{
+			name:     "config",
+			cfg:      &config.Config{Exclude: []string{`_fake\.go$`}, Generated: config.GeneratedInclude},
+			expected: []string{"example.com/repo/a/gen.go", "example.com/repo/a/missing.go"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ignore.Apply(profiles, tc.cfg, resolver)
+			if err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			var names []string
+			for _, p := range result {
+				names = append(names, p.FileName)
+			}
+			if !reflect.DeepEqual(names, tc.expected) {
+				t.Fatalf("bad result.\n\nexpected: %v\nactual: %v", tc.expected, names)
+			}
+		})
+	}
+}
+
+func TestApplyFiles(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":    "module example.com/repo\n",
+		"a/a.go":    annotated,
+		"a/skip.go": "//coverage:ignore\n\npackage a\n",
+		"a/gen.go":  "// Code generated by hand. DO NOT EDIT.\n\npackage a\n\nfunc G() {\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	resolver, err := funccov.NewSourceResolver(root)
+	if err != nil {
+		t.Fatalf("NewSourceResolver failed: %v", err)
+	}
+
+	// The block of Ignored in a.go, which is annotated, is kept along with the file.
+	block := cover.ProfileBlock{StartLine: 5, StartCol: 16, EndLine: 7, EndCol: 2, NumStmt: 1}
+	profiles := []*cover.Profile{
+		{FileName: "example.com/repo/a/a.go", Mode: "set", Blocks: []cover.ProfileBlock{block}},
+		{FileName: "example.com/repo/a/gen.go", Mode: "set", Blocks: []cover.ProfileBlock{block}},
+		{FileName: "example.com/repo/a/skip.go", Mode: "set", Blocks: []cover.ProfileBlock{block}},
+	}
+	result, err := ignore.ApplyFiles(profiles, nil, resolver)
+	if err != nil {
+		t.Fatalf("ApplyFiles failed: %v", err)
+	}
+	if expected := profiles[:1]; !reflect.DeepEqual(result, expected) {
+		t.Fatalf("bad result.\n\nexpected: %+v\nactual: %+v", expected, result)
+	}
+}
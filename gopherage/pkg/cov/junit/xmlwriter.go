@@ -24,6 +24,7 @@ import (
 
 	"golang.org/x/tools/cover"
 
+	"k8s.io/test-infra/gopherage/pkg/cov/funccov"
 	"k8s.io/test-infra/gopherage/pkg/cov/junit/calculation"
 )
 
@@ -54,6 +55,23 @@ type Testsuite struct {
 }
 
 func (ts *Testsuite) addTestCase(coverageTargetName string, ratio, threshold float32) {
+	failure := ""
+	if ratio < threshold {
+		failure = fmt.Sprintf("code coverage of %.0f%% is below threshold of %.0f%%", 100*ratio, 100*threshold)
+	}
+	ts.addTestCaseWithFailure(coverageTargetName, ratio, failure)
+}
+
+// addRatchetTestCase adds a test case that fails if ratio is more than tolerance below baseline.
+func (ts *Testsuite) addRatchetTestCase(coverageTargetName string, ratio, baseline, tolerance float32) {
+	failure := ""
+	if ratio < baseline-tolerance {
+		failure = fmt.Sprintf("code coverage of %.1f%% is below baseline of %.1f%% by more than %.1f points", 100*ratio, 100*baseline, 100*tolerance)
+	}
+	ts.addTestCaseWithFailure(coverageTargetName, ratio, failure)
+}
+
+func (ts *Testsuite) addTestCaseWithFailure(coverageTargetName string, ratio float32, failure string) {
 	testCase := TestCase{
 		ClassName: "go_coverage",
 		Name:      coverageTargetName,
@@ -66,26 +84,74 @@ func (ts *Testsuite) addTestCase(coverageTargetName string, ratio, threshold flo
 				},
 			},
 		},
-	}
-	if ratio < threshold {
-		testCase.Failure = fmt.Sprintf("code coverage of %.0f%% is below threshold of %.0f%%", 100*ratio, 100*threshold)
+		Failure: failure,
 	}
 	ts.Testcases = append(ts.Testcases, testCase)
 }
 
+// Options configures the junit xml produced from a profile.
+type Options struct {
+	// Threshold is the coverage below which a test case fails.
+	Threshold float32
+	// ThresholdFor, if set, returns the threshold for a file or directory
+	// instead of Threshold. It is not used for the overall coverage.
+	ThresholdFor func(name string) float32
+	// Functions, if set, adds a test case for each function, which fails if
+	// its coverage is below FunctionThreshold.
+	Functions         []funccov.Coverage
+	FunctionThreshold float32
+	// Baseline, if set, enables ratchet mode: the overall coverage and that of
+	// each directory only fail if they are more than Tolerance below their
+	// coverage in Baseline. Directories missing from Baseline are held to
+	// their threshold, and files never fail.
+	Baseline  []*cover.Profile
+	Tolerance float32
+}
+
+func (o Options) thresholdFor(name string) float32 {
+	if o.ThresholdFor != nil {
+		return o.ThresholdFor(name)
+	}
+	return o.Threshold
+}
+
 // toTestsuite populates Testsuite struct with data from CoverageList and actual file
 // directories from OS
-func toTestsuite(covList *calculation.CoverageList, coverageThreshold float32) Testsuite {
+func toTestsuite(covList *calculation.CoverageList, opts Options) Testsuite {
+	ts := Testsuite{}
+	ts.addTestCase("OVERALL", covList.Ratio(), opts.Threshold)
+
+	for _, cov := range covList.Group {
+		ts.addTestCase(cov.Name, cov.Ratio(), opts.thresholdFor(cov.Name))
+	}
+
+	for _, dir := range covList.ListDirectories() {
+		dirCov := covList.Subset(dir)
+		ts.addTestCase(dir, dirCov.Ratio(), opts.thresholdFor(dir))
+	}
+	return ts
+}
+
+// toRatchetTestsuite is like toTestsuite, but compares coverage against baseList.
+func toRatchetTestsuite(covList, baseList *calculation.CoverageList, opts Options) Testsuite {
 	ts := Testsuite{}
-	ts.addTestCase("OVERALL", covList.Ratio(), coverageThreshold)
+	ts.addRatchetTestCase("OVERALL", covList.Ratio(), baseList.Ratio(), opts.Tolerance)
 
 	for _, cov := range covList.Group {
-		ts.addTestCase(cov.Name, cov.Ratio(), coverageThreshold)
+		ts.addTestCaseWithFailure(cov.Name, cov.Ratio(), "")
 	}
 
+	baseDirs := map[string]bool{}
+	for _, dir := range baseList.ListDirectories() {
+		baseDirs[dir] = true
+	}
 	for _, dir := range covList.ListDirectories() {
 		dirCov := covList.Subset(dir)
-		ts.addTestCase(dir, dirCov.Ratio(), coverageThreshold)
+		if baseDirs[dir] {
+			ts.addRatchetTestCase(dir, dirCov.Ratio(), baseList.Subset(dir).Ratio(), opts.Tolerance)
+		} else {
+			ts.addTestCase(dir, dirCov.Ratio(), opts.thresholdFor(dir))
+		}
 	}
 	return ts
 }
@@ -93,8 +159,45 @@ func toTestsuite(covList *calculation.CoverageList, coverageThreshold float32) T
 // ProfileToTestsuiteXML uses coverage profile to produce junit xml
 // which serves as the input for test coverage testgrid
 func ProfileToTestsuiteXML(profiles []*cover.Profile, coverageThreshold float32) ([]byte, error) {
+	return ProfileToTestsuiteXMLWithOptions(profiles, Options{Threshold: coverageThreshold})
+}
+
+// ProfileToTestsuiteXMLWithFunctions behaves like ProfileToTestsuiteXML, but
+// additionally produces a test case for each function, which fails if the
+// coverage of that function is below functionThreshold.
+func ProfileToTestsuiteXMLWithFunctions(profiles []*cover.Profile, funcs []funccov.Coverage, coverageThreshold, functionThreshold float32) ([]byte, error) {
+	return ProfileToTestsuiteXMLWithOptions(profiles, Options{
+		Threshold:         coverageThreshold,
+		Functions:         funcs,
+		FunctionThreshold: functionThreshold,
+	})
+}
+
+// ProfileToTestsuiteXMLWithOptions produces junit xml from profiles as configured by opts.
+func ProfileToTestsuiteXMLWithOptions(profiles []*cover.Profile, opts Options) ([]byte, error) {
 	covList := calculation.ProduceCovList(profiles)
 
-	ts := toTestsuite(covList, coverageThreshold)
+	var ts Testsuite
+	if opts.Baseline != nil {
+		ts = toRatchetTestsuite(covList, calculation.ProduceCovList(opts.Baseline), opts)
+	} else {
+		ts = toTestsuite(covList, opts)
+	}
+	ts.addFunctions(opts.Functions, opts.FunctionThreshold)
 	return xml.MarshalIndent(ts, "", "    ")
 }
+
+// FunctionsToTestsuiteXML produces junit xml with one test case per function
+func FunctionsToTestsuiteXML(funcs []funccov.Coverage, functionThreshold float32) ([]byte, error) {
+	ts := Testsuite{}
+	total := funccov.Total(funcs)
+	ts.addTestCase("OVERALL", total.Ratio(), functionThreshold)
+	ts.addFunctions(funcs, functionThreshold)
+	return xml.MarshalIndent(ts, "", "    ")
+}
+
+func (ts *Testsuite) addFunctions(funcs []funccov.Coverage, threshold float32) {
+	for _, f := range funcs {
+		ts.addTestCase(f.Name(), f.Ratio(), threshold)
+	}
+}
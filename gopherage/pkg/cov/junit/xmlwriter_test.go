@@ -0,0 +1,111 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package junit_test
+
+import (
+	"encoding/xml"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/cov/junit"
+)
+
+// profile returns a profile for fileName with the given number of covered and uncovered statements.
+func profile(fileName string, covered, uncovered int) *cover.Profile {
+	return &cover.Profile{
+		FileName: fileName,
+		Mode:     "set",
+		Blocks: []cover.ProfileBlock{
+			{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 1, NumStmt: covered, Count: 1},
+			{StartLine: 3, StartCol: 1, EndLine: 4, EndCol: 1, NumStmt: uncovered, Count: 0},
+		},
+	}
+}
+
+// failures returns the names of the failing test cases in the junit xml text.
+func failures(t *testing.T, text []byte) []string {
+	t.Helper()
+	var ts junit.Testsuite
+	if err := xml.Unmarshal(text, &ts); err != nil {
+		t.Fatalf("failed to parse junit xml: %v", err)
+	}
+	var names []string
+	for _, tc := range ts.Testcases {
+		if tc.Failure != "" {
+			names = append(names, tc.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestProfileToTestsuiteXMLWithThresholdFor(t *testing.T) {
+	profiles := []*cover.Profile{
+		profile("cmd/main.go", 6, 4),
+		profile("pkg/lib.go", 8, 2),
+	}
+	opts := junit.Options{
+		Threshold: .5,
+		ThresholdFor: func(name string) float32 {
+			if strings.HasPrefix(name, "pkg") {
+				return .9
+			}
+			return .5
+		},
+	}
+
+	text, err := junit.ProfileToTestsuiteXMLWithOptions(profiles, opts)
+	if err != nil {
+		t.Fatalf("ProfileToTestsuiteXMLWithOptions failed: %v", err)
+	}
+	expected := []string{"pkg", "pkg/lib.go"}
+	if result := failures(t, text); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("bad result.\n\nexpected: %v\nactual: %v", expected, result)
+	}
+}
+
+func TestProfileToTestsuiteXMLRatchet(t *testing.T) {
+	baseline := []*cover.Profile{
+		profile("a/a.go", 5, 5),
+		profile("b/b.go", 9, 1),
+	}
+	profiles := []*cover.Profile{
+		profile("a/a.go", 4, 6),
+		profile("b/b.go", 7, 3),
+		profile("c/c.go", 1, 9),
+		profile("d/d.go", 9, 1),
+	}
+	opts := junit.Options{
+		Threshold: .8,
+		Baseline:  baseline,
+		Tolerance: .15,
+	}
+
+	text, err := junit.ProfileToTestsuiteXMLWithOptions(profiles, opts)
+	if err != nil {
+		t.Fatalf("ProfileToTestsuiteXMLWithOptions failed: %v", err)
+	}
+	// a dropped by 10 points and b by 20, with a tolerance of 15. c is new, so is held to the
+	// threshold, as is d, which meets it. OVERALL dropped from 70% to 52.5%.
+	expected := []string{"OVERALL", "b", "c"}
+	if result := failures(t, text); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("bad result.\n\nexpected: %v\nactual: %v", expected, result)
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cov
+
+import (
+	"golang.org/x/tools/cover"
+)
+
+// LineCounts returns the hit count of every source line in profile that is part
+// of at least one block. If a line is part of several blocks, the highest count
+// is used.
+// A block that ends at the first column of a line does not include that line.
+func LineCounts(profile *cover.Profile) map[int]int {
+	lines := map[int]int{}
+	for _, block := range profile.Blocks {
+		end := block.EndLine
+		if block.EndCol <= 1 && end > block.StartLine {
+			end--
+		}
+		for line := block.StartLine; line <= end; line++ {
+			if count, ok := lines[line]; !ok || block.Count > count {
+				lines[line] = block.Count
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cov_test
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/cov"
+)
+
+func TestLineCounts(t *testing.T) {
+	profile := &cover.Profile{
+		FileName: "a.go",
+		Mode:     "count",
+		Blocks: []cover.ProfileBlock{
+			{StartLine: 1, StartCol: 14, EndLine: 3, EndCol: 13, NumStmt: 2, Count: 3},
+			{StartLine: 3, StartCol: 14, EndLine: 5, EndCol: 1, NumStmt: 1, Count: 0},
+			{StartLine: 7, StartCol: 4, EndLine: 7, EndCol: 20, NumStmt: 1, Count: 0},
+		},
+	}
+
+	expected := map[int]int{1: 3, 2: 3, 3: 3, 4: 0, 7: 0}
+	if result := cov.LineCounts(profile); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("bad result.\n\nexpected: %v\nactual: %v", expected, result)
+	}
+}
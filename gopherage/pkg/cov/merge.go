@@ -23,6 +23,27 @@ import (
 	"sort"
 )
 
+// ConflictPolicy determines how MergeProfilesWithPolicy handles a file whose coverage blocks differ
+// between the profiles being merged, as happens when the profiles were built from slightly different
+// source.
+type ConflictPolicy string
+
+const (
+	// ConflictError fails the merge.
+	ConflictError ConflictPolicy = "error"
+	// ConflictUnion splits the blocks of both profiles into line ranges at every block boundary,
+	// and combines the counts of each range. See UnionBlocks.
+	ConflictUnion ConflictPolicy = "union"
+	// ConflictLatest discards the blocks of the earlier profile in favour of the later one.
+	ConflictLatest ConflictPolicy = "latest"
+)
+
+// Conflict describes a file that was reconciled while merging.
+type Conflict struct {
+	FileName string
+	Reason   string
+}
+
 // MergeProfiles merges two coverage profiles.
 // The profiles are expected to be similar - that is, from multiple invocations of a
 // single binary, or multiple binaries using the same codebase.
@@ -32,7 +53,22 @@ import (
 // and lines in files in the order those lines appear. These are standard constraints for
 // Go coverage profiles. The resulting profile will also obey these constraints.
 func MergeProfiles(a []*cover.Profile, b []*cover.Profile) ([]*cover.Profile, error) {
+	result, _, err := MergeProfilesWithPolicy(a, b, ConflictError)
+	return result, err
+}
+
+// MergeProfilesWithPolicy behaves like MergeProfiles, but files whose blocks differ between a and b
+// are handled according to policy instead of failing the merge. Every file that had to be
+// reconciled is returned as a Conflict. Files recorded in different modes are never reconciled.
+func MergeProfilesWithPolicy(a []*cover.Profile, b []*cover.Profile, policy ConflictPolicy) ([]*cover.Profile, []Conflict, error) {
+	switch policy {
+	case ConflictError, ConflictUnion, ConflictLatest:
+	default:
+		return nil, nil, fmt.Errorf("unknown conflict policy %q", policy)
+	}
+
 	var result []*cover.Profile
+	var conflicts []Conflict
 	files := make(map[string]*cover.Profile, len(a))
 	for _, profile := range a {
 		np := deepCopyProfile(*profile)
@@ -46,7 +82,16 @@ func MergeProfiles(a []*cover.Profile, b []*cover.Profile) ([]*cover.Profile, er
 		dest, ok := files[profile.FileName]
 		if ok {
 			if err := ensureProfilesMatch(profile, dest); err != nil {
-				return nil, fmt.Errorf("error merging %s: %w", profile.FileName, err)
+				if policy == ConflictError || profile.Mode != dest.Mode {
+					return nil, nil, fmt.Errorf("error merging %s: %w", profile.FileName, err)
+				}
+				conflicts = append(conflicts, Conflict{FileName: profile.FileName, Reason: err.Error()})
+				if policy == ConflictUnion {
+					dest.Blocks = UnionBlocks(dest.Blocks, profile.Blocks)
+				} else {
+					dest.Blocks = deepCopyProfile(*profile).Blocks
+				}
+				continue
 			}
 			for i, block := range profile.Blocks {
 				db := &dest.Blocks[i]
@@ -64,22 +109,33 @@ func MergeProfiles(a []*cover.Profile, b []*cover.Profile) ([]*cover.Profile, er
 	if needsSort {
 		sort.Slice(result, func(i, j int) bool { return result[i].FileName < result[j].FileName })
 	}
-	return result, nil
+	return result, conflicts, nil
 }
 
 // MergeMultipleProfiles merges more than two profiles together.
 // MergeMultipleProfiles is equivalent to calling MergeProfiles on pairs of profiles
 // until only one profile remains.
 func MergeMultipleProfiles(profiles [][]*cover.Profile) ([]*cover.Profile, error) {
+	result, _, err := MergeMultipleProfilesWithPolicy(profiles, ConflictError)
+	return result, err
+}
+
+// MergeMultipleProfilesWithPolicy merges more than two profiles together, handling conflicts
+// according to policy. Profiles later in the list are considered more recent.
+// It returns every conflict encountered, in the order they were reconciled.
+func MergeMultipleProfilesWithPolicy(profiles [][]*cover.Profile, policy ConflictPolicy) ([]*cover.Profile, []Conflict, error) {
 	if len(profiles) < 1 {
-		return nil, errors.New("can't merge zero profiles")
+		return nil, nil, errors.New("can't merge zero profiles")
 	}
 	result := profiles[0]
+	var conflicts []Conflict
 	for _, profile := range profiles[1:] {
 		var err error
-		if result, err = MergeProfiles(result, profile); err != nil {
-			return nil, err
+		var c []Conflict
+		if result, c, err = MergeProfilesWithPolicy(result, profile, policy); err != nil {
+			return nil, nil, err
 		}
+		conflicts = append(conflicts, c...)
 	}
-	return result, nil
+	return result, conflicts, nil
 }
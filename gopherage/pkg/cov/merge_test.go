@@ -265,3 +265,134 @@ func TestMergeProfilesConflictingFileContents(t *testing.T) {
 		t.Fatalf("expected merging conflicting profiles to fail: %+v", result[0])
 	}
 }
+
+func conflictingProfiles() ([]*cover.Profile, []*cover.Profile) {
+	a := []*cover.Profile{
+		{
+			FileName: "a.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 1, StartCol: 14, EndLine: 5, EndCol: 13, NumStmt: 4, Count: 3},
+				{StartLine: 7, StartCol: 4, EndLine: 12, EndCol: 4, NumStmt: 3, Count: 2},
+			},
+		},
+		{
+			FileName: "b.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 1, NumStmt: 1, Count: 1},
+			},
+		},
+	}
+	b := []*cover.Profile{
+		{
+			FileName: "a.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 2, StartCol: 14, EndLine: 5, EndCol: 13, NumStmt: 4, Count: 7},
+				{StartLine: 7, StartCol: 4, EndLine: 12, EndCol: 4, NumStmt: 3, Count: 2},
+			},
+		},
+		{
+			FileName: "b.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 1, NumStmt: 1, Count: 4},
+			},
+		},
+	}
+	return a, b
+}
+
+func TestMergeProfilesWithPolicyUnion(t *testing.T) {
+	a, b := conflictingProfiles()
+
+	result, conflicts, err := cov.MergeProfilesWithPolicy(a, b, cov.ConflictUnion)
+	if err != nil {
+		t.Fatalf("error merging profiles: %v", err)
+	}
+
+	expected := []*cover.Profile{
+		{
+			FileName: "a.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 1, NumStmt: 0, Count: 3},
+				{StartLine: 2, StartCol: 1, EndLine: 6, EndCol: 1, NumStmt: 4, Count: 10},
+				{StartLine: 7, StartCol: 1, EndLine: 13, EndCol: 1, NumStmt: 3, Count: 4},
+			},
+		},
+		{
+			FileName: "b.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 1, NumStmt: 1, Count: 5},
+			},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("bad merge result.\n\nexpected: %+v\nactual: %+v", expected, result)
+	}
+	if len(conflicts) != 1 || conflicts[0].FileName != "a.go" {
+		t.Fatalf("expected a single conflict for a.go, got %+v", conflicts)
+	}
+}
+
+func TestMergeProfilesWithPolicyLatest(t *testing.T) {
+	a, b := conflictingProfiles()
+
+	result, conflicts, err := cov.MergeProfilesWithPolicy(a, b, cov.ConflictLatest)
+	if err != nil {
+		t.Fatalf("error merging profiles: %v", err)
+	}
+
+	if !reflect.DeepEqual(result[0].Blocks, b[0].Blocks) {
+		t.Fatalf("expected blocks of a.go to be replaced.\n\nexpected: %+v\nactual: %+v", b[0].Blocks, result[0].Blocks)
+	}
+	if result[1].Blocks[0].Count != 5 {
+		t.Fatalf("expected counts of b.go to be summed, got %d", result[1].Blocks[0].Count)
+	}
+	if len(conflicts) != 1 || conflicts[0].FileName != "a.go" {
+		t.Fatalf("expected a single conflict for a.go, got %+v", conflicts)
+	}
+}
+
+func TestMergeProfilesWithPolicyModeMismatch(t *testing.T) {
+	a, b := conflictingProfiles()
+	b[0].Mode = "set"
+
+	if _, _, err := cov.MergeProfilesWithPolicy(a, b, cov.ConflictUnion); err == nil {
+		t.Fatal("expected merging profiles with different modes to fail")
+	}
+}
+
+func TestMergeMultipleProfilesWithPolicy(t *testing.T) {
+	a, b := conflictingProfiles()
+	c, _ := conflictingProfiles()
+
+	_, conflicts, err := cov.MergeMultipleProfilesWithPolicy([][]*cover.Profile{a, b, c}, cov.ConflictUnion)
+	if err != nil {
+		t.Fatalf("error merging profiles: %v", err)
+	}
+	if len(conflicts) != 2 {
+		t.Fatalf("expected a conflict from each merge, got %+v", conflicts)
+	}
+}
+
+func TestUnionBlocksSharesStatements(t *testing.T) {
+	a := []cover.ProfileBlock{
+		{StartLine: 1, StartCol: 5, EndLine: 10, EndCol: 1, NumStmt: 9, Count: 1},
+	}
+	b := []cover.ProfileBlock{
+		{StartLine: 4, StartCol: 2, EndLine: 6, EndCol: 8, NumStmt: 1, Count: 0},
+	}
+
+	expected := []cover.ProfileBlock{
+		{StartLine: 1, StartCol: 1, EndLine: 4, EndCol: 1, NumStmt: 3, Count: 1},
+		{StartLine: 4, StartCol: 1, EndLine: 7, EndCol: 1, NumStmt: 3, Count: 1},
+		{StartLine: 7, StartCol: 1, EndLine: 10, EndCol: 1, NumStmt: 3, Count: 1},
+	}
+	if result := cov.UnionBlocks(a, b); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("bad union.\n\nexpected: %+v\nactual: %+v", expected, result)
+	}
+}
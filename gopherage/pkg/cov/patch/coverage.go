@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/cov"
+	"k8s.io/test-infra/gopherage/pkg/cov/junit/calculation"
+)
+
+// ChangedLines restricts profiles to the lines in changes. Every changed line
+// that is part of a coverage block becomes a block of its own, with a single
+// statement, so the statement coverage of the result is the proportion of
+// changed lines that were covered. Files with no such lines are dropped.
+//
+// Profiles name files by import path, while patches name them relative to the
+// repository root, so a profile matches the longest changed path that is a
+// suffix of its file name.
+func ChangedLines(profiles []*cover.Profile, changes Changes) []*cover.Profile {
+	var result []*cover.Profile
+	for _, profile := range profiles {
+		lines, ok := changes[changes.Match(profile.FileName)]
+		if !ok {
+			continue
+		}
+		lineCounts := cov.LineCounts(profile)
+		p := &cover.Profile{FileName: profile.FileName, Mode: profile.Mode}
+		for _, line := range lines {
+			count, ok := lineCounts[line]
+			if !ok {
+				continue
+			}
+			p.Blocks = append(p.Blocks, cover.ProfileBlock{
+				StartLine: line,
+				StartCol:  1,
+				EndLine:   line + 1,
+				EndCol:    1,
+				NumStmt:   1,
+				Count:     count,
+			})
+		}
+		if len(p.Blocks) > 0 {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// Match returns the path in changes that the profile file name refers to, or an
+// empty string if the file was not changed.
+func (changes Changes) Match(fileName string) string {
+	match := ""
+	for path := range changes {
+		if (fileName == path || strings.HasSuffix(fileName, "/"+path)) && len(path) > len(match) {
+			match = path
+		}
+	}
+	return match
+}
+
+// WriteReport writes the coverage of changed lines, as returned by
+// ChangedLines, to writer as a table with a row for each file and the total.
+func WriteReport(changed []*cover.Profile, writer io.Writer) error {
+	tw := tabwriter.NewWriter(writer, 1, 8, 1, '\t', 0)
+	if _, err := fmt.Fprintln(tw, "File\tCovered lines\tChanged lines\tCoverage"); err != nil {
+		return err
+	}
+	covList := calculation.ProduceCovList(changed)
+	for _, c := range covList.Group {
+		if _, err := fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f%%\n", c.Name, c.NumCoveredStmts, c.NumAllStmts, 100*c.Ratio()); err != nil {
+			return err
+		}
+	}
+	ratio := covList.Ratio()
+	if _, err := fmt.Fprintf(tw, "total\t%d\t%d\t%.1f%%\n", covList.NumCoveredStmts, covList.NumAllStmts, 100*ratio); err != nil {
+		return err
+	}
+	return tw.Flush()
+}
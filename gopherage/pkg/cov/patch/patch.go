@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package patch restricts coverage profiles to the lines changed by a patch,
+// so that presubmits can report coverage of new code only.
+package patch
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Changes maps the path of every file touched by a patch (as it is named after
+// the patch is applied) to the numbers of the lines the patch added or
+// modified, in ascending order.
+type Changes map[string][]int
+
+// ParseUnifiedDiff reads a unified diff, as produced by `diff -u` or
+// `git diff`, and returns the lines it adds or modifies.
+// Deleted files are ignored, as are files whose changes only remove lines.
+func ParseUnifiedDiff(reader io.Reader) (Changes, error) {
+	changes := Changes{}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 1024*1024)
+	var file string
+	// oldLeft and newLeft are the number of lines remaining in the current hunk.
+	var oldLeft, newLeft, newLine int
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				if file != "" {
+					changes[file] = append(changes[file], newLine)
+				}
+				newLine++
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				oldLeft--
+			case strings.HasPrefix(line, `\`):
+				// "\ No newline at end of file"
+			default:
+				// Context lines start with a space, but some tools strip
+				// trailing whitespace from empty ones.
+				newLine++
+				oldLeft--
+				newLeft--
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = diffPath(strings.TrimPrefix(line, "+++ "))
+		case strings.HasPrefix(line, "@@ "):
+			var err error
+			oldLeft, newLine, newLeft, err = parseHunkHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read diff: %w", err)
+	}
+	return changes, nil
+}
+
+// diffPath extracts the file path from the name in a "+++" line, or returns an
+// empty string if the file was deleted.
+func diffPath(name string) string {
+	// Non-git diffs may follow the name with a tab and a timestamp.
+	if i := strings.IndexByte(name, '\t'); i >= 0 {
+		name = name[:i]
+	}
+	if name == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(name, "b/")
+}
+
+// parseHunkHeader parses a header of the form "@@ -l,s +l,s @@", where the
+// sizes may be omitted if they are one.
+func parseHunkHeader(header string) (oldSize, newStart, newSize int, err error) {
+	fields := strings.Fields(header)
+	if len(fields) < 4 || !strings.HasPrefix(fields[3], "@@") {
+		return 0, 0, 0, fmt.Errorf("malformed hunk header %q", header)
+	}
+	_, oldSize, err = parseRange(fields[1], "-")
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("malformed hunk header %q: %w", header, err)
+	}
+	newStart, newSize, err = parseRange(fields[2], "+")
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("malformed hunk header %q: %w", header, err)
+	}
+	return oldSize, newStart, newSize, nil
+}
+
+func parseRange(r, prefix string) (start, size int, err error) {
+	if !strings.HasPrefix(r, prefix) {
+		return 0, 0, fmt.Errorf("range %q does not start with %q", r, prefix)
+	}
+	r = strings.TrimPrefix(r, prefix)
+	size = 1
+	if i := strings.IndexByte(r, ','); i >= 0 {
+		if size, err = strconv.Atoi(r[i+1:]); err != nil {
+			return 0, 0, err
+		}
+		r = r[:i]
+	}
+	if start, err = strconv.Atoi(r); err != nil {
+		return 0, 0, err
+	}
+	return start, size, nil
+}
+
+// GitDiff returns the changes between base and head in the git repository at
+// dir. As in a pull request, changes are relative to the merge base of base and
+// head, so that changes made to base in the meantime are not included.
+func GitDiff(dir, base, head string) (Changes, error) {
+	cmd := exec.Command("git", "-C", dir, "diff", "--no-color", "--no-ext-diff", "--unified=0", base+"..."+head)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s...%s failed: %w: %s", base, head, err, strings.TrimSpace(stderr.String()))
+	}
+	return ParseUnifiedDiff(bytes.NewReader(out))
+}
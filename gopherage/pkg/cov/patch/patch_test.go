@@ -0,0 +1,215 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/cov/patch"
+)
+
+const gitDiff = `diff --git a/pkg/a.go b/pkg/a.go
+index 1111111..2222222 100644
+--- a/pkg/a.go
++++ b/pkg/a.go
+@@ -2,4 +2,5 @@ package a
+ func A() {
+-	x := 1
++	x := 2
++	++x
+ 	return
+ }
+@@ -20 +21,0 @@ func B() {
+-	y()
+diff --git a/pkg/old.go b/pkg/old.go
+deleted file mode 100644
+--- a/pkg/old.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package pkg
+-
+diff --git a/pkg/new.go b/pkg/new.go
+new file mode 100644
+--- /dev/null
++++ b/pkg/new.go
+@@ -0,0 +1,2 @@
++package pkg
++--- not a header
+\ No newline at end of file
+`
+
+func TestParseUnifiedDiff(t *testing.T) {
+	changes, err := patch.ParseUnifiedDiff(strings.NewReader(gitDiff))
+	if err != nil {
+		t.Fatalf("ParseUnifiedDiff failed: %v", err)
+	}
+
+	expected := patch.Changes{
+		"pkg/a.go":   {3, 4},
+		"pkg/new.go": {1, 2},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("bad result.\n\nexpected: %v\nactual: %v", expected, changes)
+	}
+}
+
+func TestParseUnifiedDiffPlain(t *testing.T) {
+	diff := "--- a.go\t2026-01-01 00:00:00\n+++ a.go\t2026-01-02 00:00:00\n@@ -1 +1 @@\n-a\n+b\n"
+	changes, err := patch.ParseUnifiedDiff(strings.NewReader(diff))
+	if err != nil {
+		t.Fatalf("ParseUnifiedDiff failed: %v", err)
+	}
+
+	expected := patch.Changes{"a.go": {1}}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("bad result.\n\nexpected: %v\nactual: %v", expected, changes)
+	}
+}
+
+func TestParseUnifiedDiffBadHunk(t *testing.T) {
+	if _, err := patch.ParseUnifiedDiff(strings.NewReader("+++ b/a.go\n@@ -x +1 @@\n")); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestChangedLines(t *testing.T) {
+	profiles := []*cover.Profile{
+		{
+			FileName: "example.com/repo/pkg/a.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 2, StartCol: 10, EndLine: 3, EndCol: 10, NumStmt: 1, Count: 2},
+				{StartLine: 4, StartCol: 2, EndLine: 6, EndCol: 1, NumStmt: 2, Count: 0},
+			},
+		},
+		{
+			FileName: "example.com/repo/pkg/b.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 1, StartCol: 1, EndLine: 9, EndCol: 1, NumStmt: 5, Count: 1},
+			},
+		},
+		{
+			FileName: "example.com/repo/pkg/c.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 10, StartCol: 1, EndLine: 12, EndCol: 1, NumStmt: 1, Count: 1},
+			},
+		},
+	}
+	changes := patch.Changes{
+		"pkg/a.go": {1, 3, 4},
+		"pkg/c.go": {1},
+		"README":   {1},
+	}
+
+	expected := []*cover.Profile{
+		{
+			FileName: "example.com/repo/pkg/a.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 3, StartCol: 1, EndLine: 4, EndCol: 1, NumStmt: 1, Count: 2},
+				{StartLine: 4, StartCol: 1, EndLine: 5, EndCol: 1, NumStmt: 1, Count: 0},
+			},
+		},
+	}
+	if result := patch.ChangedLines(profiles, changes); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("bad result.\n\nexpected: %+v\nactual: %+v", expected, result)
+	}
+}
+
+func TestChangesMatch(t *testing.T) {
+	changes := patch.Changes{"a.go": {1}, "pkg/a.go": {1}, "b.go": {1}}
+	cases := map[string]string{
+		"a.go":                         "a.go",
+		"k8s.io/test-infra/pkg/a.go":   "pkg/a.go",
+		"k8s.io/test-infra/other/a.go": "a.go",
+		"k8s.io/test-infra/pkg/ab.go":  "",
+		"k8s.io/test-infra/sub.go":     "",
+	}
+	for fileName, expected := range cases {
+		if result := changes.Match(fileName); result != expected {
+			t.Errorf("Match(%q): expected %q, got %q", fileName, expected, result)
+		}
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	changed := []*cover.Profile{
+		{
+			FileName: "a.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 3, StartCol: 1, EndLine: 4, EndCol: 1, NumStmt: 1, Count: 2},
+				{StartLine: 4, StartCol: 1, EndLine: 5, EndCol: 1, NumStmt: 1, Count: 0},
+			},
+		},
+	}
+	var buf bytes.Buffer
+	if err := patch.WriteReport(changed, &buf); err != nil {
+		t.Fatalf("WriteReport failed: %v", err)
+	}
+
+	expected := "File\tCovered lines\tChanged lines\tCoverage\n" +
+		"a.go\t1\t\t2\t\t50.0%\n" +
+		"total\t1\t\t2\t\t50.0%\n"
+	if buf.String() != expected {
+		t.Fatalf("bad result.\n\nexpected:\n%q\nactual:\n%q", expected, buf.String())
+	}
+}
+
+func TestGitDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(content string) {
+		if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write a.go: %v", err)
+		}
+	}
+
+	git("init", "-q")
+	write("package a\n\nfunc A() {\n}\n")
+	git("add", "a.go")
+	git("commit", "-q", "-m", "base")
+	git("tag", "base")
+	write("package a\n\nfunc A() {\n\tprintln()\n}\n")
+	git("commit", "-q", "-a", "-m", "head")
+
+	changes, err := patch.GitDiff(dir, "base", "HEAD")
+	if err != nil {
+		t.Fatalf("GitDiff failed: %v", err)
+	}
+	expected := patch.Changes{"a.go": {4}}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("bad result.\n\nexpected: %v\nactual: %v", expected, changes)
+	}
+}
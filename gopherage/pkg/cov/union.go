@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cov
+
+import (
+	"sort"
+
+	"golang.org/x/tools/cover"
+)
+
+// lineSpan is the range of lines, inclusive, that a block covers.
+type lineSpan struct {
+	block      cover.ProfileBlock
+	start, end int
+}
+
+func spansOf(blocks []cover.ProfileBlock) []lineSpan {
+	spans := make([]lineSpan, 0, len(blocks))
+	for _, b := range blocks {
+		end := b.EndLine
+		// As in LineCounts, a block ending at the first column does not include that line.
+		if b.EndCol <= 1 && end > b.StartLine {
+			end--
+		}
+		spans = append(spans, lineSpan{block: b, start: b.StartLine, end: end})
+	}
+	return spans
+}
+
+// UnionBlocks combines two sets of blocks for the same file that do not line up, as when they were
+// recorded from different versions of the source.
+//
+// The lines covered by any block are split into ranges at every line where a block of either set
+// starts or ends. Each range becomes a block with the sum of the counts of the two sets on those
+// lines. The statements of each original block are shared out between the ranges it spans, in
+// proportion to their length, and each range takes the larger share of the two sets.
+// Column information is lost: every resulting block starts at the first column of its first line
+// and ends at the first column of the line after its last.
+func UnionBlocks(a, b []cover.ProfileBlock) []cover.ProfileBlock {
+	sides := [][]lineSpan{spansOf(a), spansOf(b)}
+
+	cutSet := map[int]bool{}
+	for _, spans := range sides {
+		for _, s := range spans {
+			cutSet[s.start] = true
+			cutSet[s.end+1] = true
+		}
+	}
+	cuts := make([]int, 0, len(cutSet))
+	for c := range cutSet {
+		cuts = append(cuts, c)
+	}
+	sort.Ints(cuts)
+
+	var result []cover.ProfileBlock
+	for i := 0; i+1 < len(cuts); i++ {
+		start, end := cuts[i], cuts[i+1]-1
+		covered := false
+		count, stmts := 0, 0
+		for _, spans := range sides {
+			sideCount, sideStmts := 0, 0
+			for _, s := range spans {
+				if s.end < start || s.start > end {
+					continue
+				}
+				covered = true
+				if s.block.Count > sideCount {
+					sideCount = s.block.Count
+				}
+				sideStmts += statementShare(s, start, end)
+			}
+			count += sideCount
+			if sideStmts > stmts {
+				stmts = sideStmts
+			}
+		}
+		if !covered {
+			continue
+		}
+		result = append(result, cover.ProfileBlock{
+			StartLine: start,
+			StartCol:  1,
+			EndLine:   end + 1,
+			EndCol:    1,
+			NumStmt:   stmts,
+			Count:     count,
+		})
+	}
+	return result
+}
+
+// statementShare returns how many of the statements of s are attributed to the lines start to end,
+// which must lie within s. The shares of adjacent ranges always add up to the statements of s.
+func statementShare(s lineSpan, start, end int) int {
+	lines := s.end - s.start + 1
+	upTo := func(line int) int {
+		return s.block.NumStmt * (line - s.start) / lines
+	}
+	return upTo(end+1) - upTo(start)
+}
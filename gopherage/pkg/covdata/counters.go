@@ -0,0 +1,197 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package covdata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var counterFileMagic = [4]byte{0x00, 'c', 'w', 'm'}
+
+const counterFileVersion = 1
+
+const (
+	flavorRaw     = 1
+	flavorULEB128 = 2
+)
+
+// counterFileHeader is the header of a covcounters.* file.
+type counterFileHeader struct {
+	Magic     [4]byte
+	Version   uint32
+	MetaHash  [16]byte
+	Flavor    uint8
+	BigEndian bool
+	_         [6]byte
+}
+
+// segmentHeader precedes each segment of counters in a counter file.
+type segmentHeader struct {
+	FuncEntries uint64
+	StrTabLen   uint32
+	ArgsLen     uint32
+}
+
+// counterFileFooter follows each segment; the last one in the file records
+// the total number of segments.
+type counterFileFooter struct {
+	Magic       [4]byte
+	_           [4]byte
+	NumSegments uint32
+	_           [4]byte
+}
+
+// funcCounters holds the counters of a single executed function. pkg and fn
+// index into metaFile.packages.
+type funcCounters struct {
+	pkg      uint32
+	fn       uint32
+	counters []uint32
+}
+
+// counterFile is the decoded form of a covcounters.* file, which holds the
+// counters emitted by a single process.
+type counterFile struct {
+	metaHash [16]byte
+	funcs    []funcCounters
+}
+
+type counterReader struct {
+	data      []byte
+	offset    int
+	flavor    uint8
+	bigEndian bool
+}
+
+var errTruncated = errors.New("unexpected end of counter data")
+
+func (r *counterReader) readUint32() (uint32, error) {
+	if r.flavor == flavorULEB128 {
+		v, n := binary.Uvarint(r.data[r.offset:])
+		if n <= 0 {
+			return 0, errTruncated
+		}
+		r.offset += n
+		return uint32(v), nil
+	}
+	if r.offset+4 > len(r.data) {
+		return 0, errTruncated
+	}
+	b := r.data[r.offset : r.offset+4]
+	r.offset += 4
+	if r.bigEndian {
+		return binary.BigEndian.Uint32(b), nil
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func readCounterFile(path string) (*counterFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var header counterFileHeader
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("failed to read counter header: %w", err)
+	}
+	if header.Magic != counterFileMagic {
+		return nil, fmt.Errorf("%s is not a coverage counter file", path)
+	}
+	if header.Version != counterFileVersion {
+		return nil, fmt.Errorf("unsupported counter file version %d", header.Version)
+	}
+	if header.Flavor != flavorRaw && header.Flavor != flavorULEB128 {
+		return nil, fmt.Errorf("unsupported counter flavor %d", header.Flavor)
+	}
+
+	var footer counterFileFooter
+	footerSize := binary.Size(footer)
+	if len(data) < binary.Size(header)+footerSize {
+		return nil, fmt.Errorf("counter file is too short (%d bytes)", len(data))
+	}
+	if err := binary.Read(bytes.NewReader(data[len(data)-footerSize:]), binary.LittleEndian, &footer); err != nil {
+		return nil, fmt.Errorf("failed to read counter footer: %w", err)
+	}
+	if footer.Magic != counterFileMagic {
+		return nil, fmt.Errorf("%s has a corrupt footer", path)
+	}
+
+	cf := &counterFile{metaHash: header.MetaHash}
+	r := &counterReader{data: data, offset: binary.Size(header), flavor: header.Flavor, bigEndian: header.BigEndian}
+	for s := uint32(0); s < footer.NumSegments; s++ {
+		var segment segmentHeader
+		segmentSize := binary.Size(segment)
+		if r.offset+segmentSize > len(data) {
+			return nil, fmt.Errorf("segment #%d: %w", s, errTruncated)
+		}
+		if err := binary.Read(bytes.NewReader(data[r.offset:]), binary.LittleEndian, &segment); err != nil {
+			return nil, fmt.Errorf("failed to read segment #%d header: %w", s, err)
+		}
+		// The string table and arguments only record how the process was
+		// invoked, so we skip them. Counters start on a four-byte boundary.
+		r.offset += segmentSize + int(segment.StrTabLen) + int(segment.ArgsLen)
+		if rem := r.offset % 4; rem != 0 {
+			r.offset += 4 - rem
+		}
+		if r.offset > len(data) {
+			return nil, fmt.Errorf("segment #%d: %w", s, errTruncated)
+		}
+		for i := uint64(0); i < segment.FuncEntries; i++ {
+			fc, err := r.readFunc()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read function #%d of segment #%d: %w", i, s, err)
+			}
+			cf.funcs = append(cf.funcs, fc)
+		}
+		r.offset += footerSize
+	}
+	return cf, nil
+}
+
+func (r *counterReader) readFunc() (funcCounters, error) {
+	// Regions belonging to functions that never ran may be zero-filled, so
+	// skip ahead to the next non-zero counter count.
+	var n uint32
+	for n == 0 {
+		var err error
+		if n, err = r.readUint32(); err != nil {
+			return funcCounters{}, err
+		}
+	}
+	pkg, err := r.readUint32()
+	if err != nil {
+		return funcCounters{}, err
+	}
+	fn, err := r.readUint32()
+	if err != nil {
+		return funcCounters{}, err
+	}
+	if int(n) > len(r.data)-r.offset {
+		return funcCounters{}, errTruncated
+	}
+	fc := funcCounters{pkg: pkg, fn: fn, counters: make([]uint32, n)}
+	for i := range fc.counters {
+		if fc.counters[i], err = r.readUint32(); err != nil {
+			return funcCounters{}, err
+		}
+	}
+	return fc, nil
+}
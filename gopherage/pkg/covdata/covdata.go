@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package covdata decodes the binary coverage directories written by binaries
+// built with `go build -cover` (that is, the contents of GOCOVERDIR).
+package covdata
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+const (
+	metaFilePrefix    = "covmeta."
+	counterFilePrefix = "covcounters."
+)
+
+// IsCoverageDir returns true if path is a directory containing at least one
+// coverage meta-data file.
+func IsCoverageDir(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	matches, err := filepath.Glob(filepath.Join(path, metaFilePrefix+"*"))
+	return err == nil && len(matches) > 0
+}
+
+type blockKey struct {
+	file string
+	unit
+}
+
+// ParseDir decodes every meta-data and counter file in dir into a single
+// coverage profile. Counters from all processes that wrote to dir are merged,
+// so the result is equivalent to that of `go tool covdata textfmt -i=dir`.
+func ParseDir(dir string) ([]*cover.Profile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	metas := map[[16]byte]*metaFile{}
+	var counterFiles []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch name := entry.Name(); {
+		case strings.HasPrefix(name, metaFilePrefix):
+			m, err := readMetaFile(filepath.Join(dir, name))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", name, err)
+			}
+			metas[m.hash] = m
+		case strings.HasPrefix(name, counterFilePrefix):
+			counterFiles = append(counterFiles, name)
+		}
+	}
+	if len(metas) == 0 {
+		return nil, fmt.Errorf("no coverage meta-data files found in %s", dir)
+	}
+
+	mode := ""
+	counts := map[blockKey]int{}
+	for _, m := range metas {
+		if mode == "" {
+			mode = m.mode
+		} else if mode != m.mode {
+			return nil, fmt.Errorf("coverage directory %s mixes %q and %q modes", dir, mode, m.mode)
+		}
+		// Every unit is reported, even if no process ever executed it.
+		for _, funcs := range m.packages {
+			for _, f := range funcs {
+				for _, u := range f.units {
+					counts[blockKey{file: f.file, unit: u}] += 0
+				}
+			}
+		}
+	}
+
+	for _, name := range counterFiles {
+		cf, err := readCounterFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		m, ok := metas[cf.metaHash]
+		if !ok {
+			return nil, fmt.Errorf("counter file %s has no matching meta-data file", name)
+		}
+		for _, fc := range cf.funcs {
+			if int(fc.pkg) >= len(m.packages) || int(fc.fn) >= len(m.packages[fc.pkg]) {
+				return nil, fmt.Errorf("counter file %s refers to unknown function %d in package %d", name, fc.fn, fc.pkg)
+			}
+			f := m.packages[fc.pkg][fc.fn]
+			for i, u := range f.units {
+				var c uint32
+				switch {
+				case m.perFunc && len(fc.counters) > 0:
+					c = fc.counters[0]
+				case i < len(fc.counters):
+					c = fc.counters[i]
+				}
+				key := blockKey{file: f.file, unit: u}
+				if mode == "set" {
+					if c > 0 {
+						counts[key] = 1
+					}
+				} else {
+					counts[key] += int(c)
+				}
+			}
+		}
+	}
+
+	files := map[string]*cover.Profile{}
+	var profiles []*cover.Profile
+	for key, count := range counts {
+		p, ok := files[key.file]
+		if !ok {
+			p = &cover.Profile{FileName: key.file, Mode: mode}
+			files[key.file] = p
+			profiles = append(profiles, p)
+		}
+		p.Blocks = append(p.Blocks, cover.ProfileBlock{
+			StartLine: int(key.StartLine),
+			StartCol:  int(key.StartCol),
+			EndLine:   int(key.EndLine),
+			EndCol:    int(key.EndCol),
+			NumStmt:   int(key.NumStmt),
+			Count:     count,
+		})
+	}
+	for _, p := range profiles {
+		sort.Slice(p.Blocks, func(i, j int) bool {
+			a, b := p.Blocks[i], p.Blocks[j]
+			if a.StartLine != b.StartLine {
+				return a.StartLine < b.StartLine
+			}
+			if a.StartCol != b.StartCol {
+				return a.StartCol < b.StartCol
+			}
+			if a.EndLine != b.EndLine {
+				return a.EndLine < b.EndLine
+			}
+			return a.EndCol < b.EndCol
+		})
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].FileName < profiles[j].FileName })
+	return profiles, nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package covdata_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/cov"
+	"k8s.io/test-infra/gopherage/pkg/covdata"
+)
+
+// The testdata directories were produced by running a binary built with
+// `go build -cover` twice, and the matching .txt files by running
+// `go tool covdata textfmt` over them.
+
+func TestParseDirMatchesTextFormat(t *testing.T) {
+	for _, mode := range []string{"count", "set"} {
+		t.Run(mode, func(t *testing.T) {
+			expected, err := cover.ParseProfiles(filepath.Join("testdata", mode+".txt"))
+			if err != nil {
+				t.Fatalf("failed to parse expected profile: %v", err)
+			}
+			actual, err := covdata.ParseDir(filepath.Join("testdata", mode))
+			if err != nil {
+				t.Fatalf("ParseDir failed: %v", err)
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("bad result.\n\nexpected:\n%s\nactual:\n%s\n", dump(t, expected), dump(t, actual))
+			}
+		})
+	}
+}
+
+func TestParseDirMerges(t *testing.T) {
+	a, err := covdata.ParseDir(filepath.Join("testdata", "count"))
+	if err != nil {
+		t.Fatalf("ParseDir failed: %v", err)
+	}
+	merged, err := cov.MergeMultipleProfiles([][]*cover.Profile{a, a})
+	if err != nil {
+		t.Fatalf("MergeMultipleProfiles failed: %v", err)
+	}
+	for i, p := range merged {
+		for j, b := range p.Blocks {
+			if b.Count != 2*a[i].Blocks[j].Count {
+				t.Errorf("block #%d of %s: expected count %d, got %d", j, p.FileName, 2*a[i].Blocks[j].Count, b.Count)
+			}
+		}
+	}
+}
+
+func TestParseDirMissingMetaData(t *testing.T) {
+	dir := t.TempDir()
+	matches, err := filepath.Glob(filepath.Join("testdata", "count", "covcounters.*"))
+	if err != nil || len(matches) == 0 {
+		t.Fatalf("failed to find counter files: %v", err)
+	}
+	copyFile(t, matches[0], filepath.Join(dir, filepath.Base(matches[0])))
+	if _, err := covdata.ParseDir(dir); err == nil {
+		t.Error("expected an error for a directory without meta-data files")
+	}
+}
+
+func TestParseDirTruncatedCounters(t *testing.T) {
+	dir := t.TempDir()
+	files, err := filepath.Glob(filepath.Join("testdata", "count", "*"))
+	if err != nil {
+		t.Fatalf("failed to list testdata: %v", err)
+	}
+	for _, f := range files {
+		copyFile(t, f, filepath.Join(dir, filepath.Base(f)))
+	}
+	counters, _ := filepath.Glob(filepath.Join(dir, "covcounters.*"))
+	if err := os.Truncate(counters[0], 40); err != nil {
+		t.Fatalf("failed to truncate counter file: %v", err)
+	}
+	if _, err := covdata.ParseDir(dir); err == nil {
+		t.Error("expected an error for a truncated counter file")
+	}
+}
+
+func TestIsCoverageDir(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{path: filepath.Join("testdata", "count"), expected: true},
+		{path: filepath.Join("testdata", "set"), expected: true},
+		{path: filepath.Join("testdata", "count.txt"), expected: false},
+		{path: "testdata", expected: false},
+		{path: filepath.Join("testdata", "nonexistent"), expected: false},
+	}
+	for _, tc := range tests {
+		if actual := covdata.IsCoverageDir(tc.path); actual != tc.expected {
+			t.Errorf("IsCoverageDir(%q): expected %v, got %v", tc.path, tc.expected, actual)
+		}
+	}
+}
+
+func copyFile(t *testing.T, from, to string) {
+	t.Helper()
+	content, err := os.ReadFile(from)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", from, err)
+	}
+	if err := os.WriteFile(to, content, 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", to, err)
+	}
+}
+
+func dump(t *testing.T, profiles []*cover.Profile) string {
+	t.Helper()
+	var buffer bytes.Buffer
+	if err := cov.DumpProfile(profiles, &buffer); err != nil {
+		return err.Error()
+	}
+	return buffer.String()
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package covdata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+// The layout of the structures below mirrors internal/coverage in the Go
+// standard library, which we cannot import.
+
+var metaFileMagic = [4]byte{0x00, 'c', 'v', 'm'}
+
+const metaFileVersion = 1
+
+// metaFileHeader is the header of a covmeta.* file.
+type metaFileHeader struct {
+	Magic        [4]byte
+	Version      uint32
+	TotalLength  uint64
+	Entries      uint64
+	MetaFileHash [16]byte
+	StrTabOffset uint32
+	StrTabLength uint32
+	CounterMode  uint8
+	Granularity  uint8
+	_            [6]byte
+}
+
+// packageHeader is the header of each per-package blob in a meta-data file.
+type packageHeader struct {
+	Length     uint32
+	PkgName    uint32
+	PkgPath    uint32
+	ModulePath uint32
+	MetaHash   [16]byte
+	_          [4]byte
+	NumStrings uint32
+	NumFuncs   uint32
+}
+
+const (
+	packageHeaderSize = 44
+
+	modeSet    = 1
+	modeCount  = 2
+	modeAtomic = 3
+
+	granularityPerBlock = 1
+	granularityPerFunc  = 2
+)
+
+// unit is a single coverable block of source, equivalent to a cover.ProfileBlock
+// without the count.
+type unit struct {
+	StartLine uint32
+	StartCol  uint32
+	EndLine   uint32
+	EndCol    uint32
+	NumStmt   uint32
+}
+
+type function struct {
+	file  string
+	units []unit
+}
+
+// metaFile is the decoded form of a covmeta.* file, which describes every
+// coverable unit of a single binary.
+type metaFile struct {
+	hash    [16]byte
+	mode    string
+	perFunc bool
+	// packages is indexed by package index and then by function index, which
+	// is how counter files refer to functions.
+	packages [][]function
+}
+
+func modeName(mode uint8) (string, error) {
+	switch mode {
+	case modeSet:
+		return "set", nil
+	case modeCount:
+		return "count", nil
+	case modeAtomic:
+		return "atomic", nil
+	default:
+		return "", fmt.Errorf("unsupported counter mode %d", mode)
+	}
+}
+
+func readMetaFile(path string) (*metaFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var header metaFileHeader
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("failed to read meta-data header: %w", err)
+	}
+	if header.Magic != metaFileMagic {
+		return nil, fmt.Errorf("%s is not a coverage meta-data file", path)
+	}
+	if header.Version != metaFileVersion {
+		return nil, fmt.Errorf("unsupported meta-data file version %d", header.Version)
+	}
+	if header.TotalLength != uint64(len(data)) {
+		return nil, fmt.Errorf("meta-data file is %d bytes, but header claims %d", len(data), header.TotalLength)
+	}
+	mode, err := modeName(header.CounterMode)
+	if err != nil {
+		return nil, err
+	}
+	if header.Granularity != granularityPerBlock && header.Granularity != granularityPerFunc {
+		return nil, fmt.Errorf("unsupported counter granularity %d", header.Granularity)
+	}
+
+	m := &metaFile{
+		hash:     header.MetaFileHash,
+		mode:     mode,
+		perFunc:  header.Granularity == granularityPerFunc,
+		packages: make([][]function, 0, header.Entries),
+	}
+	// The header is followed by a table of package offsets, then a table of
+	// package lengths, both relative to the start of the file.
+	tableStart := uint64(binary.Size(header))
+	lengthStart := tableStart + 8*header.Entries
+	if lengthStart+8*header.Entries > uint64(len(data)) {
+		return nil, fmt.Errorf("meta-data file claims an impossible %d packages", header.Entries)
+	}
+	for i := uint64(0); i < header.Entries; i++ {
+		offset := binary.LittleEndian.Uint64(data[tableStart+8*i:])
+		length := binary.LittleEndian.Uint64(data[lengthStart+8*i:])
+		if offset+length > uint64(len(data)) {
+			return nil, fmt.Errorf("package #%d extends past the end of the file", i)
+		}
+		funcs, err := readPackage(data[offset : offset+length])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read package #%d: %w", i, err)
+		}
+		m.packages = append(m.packages, funcs)
+	}
+	return m, nil
+}
+
+func readPackage(blob []byte) ([]function, error) {
+	r := bytes.NewReader(blob)
+	var header packageHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if uint64(packageHeaderSize)+4*uint64(header.NumFuncs) > uint64(len(blob)) {
+		return nil, fmt.Errorf("package claims an impossible %d functions", header.NumFuncs)
+	}
+	funcOffsets := make([]uint32, header.NumFuncs)
+	if err := binary.Read(r, binary.LittleEndian, funcOffsets); err != nil {
+		return nil, err
+	}
+	strings, err := readStringTable(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read string table: %w", err)
+	}
+	lookup := func(i uint64) (string, error) {
+		if i >= uint64(len(strings)) {
+			return "", fmt.Errorf("string index %d out of range", i)
+		}
+		return strings[i], nil
+	}
+
+	funcs := make([]function, 0, header.NumFuncs)
+	for i, offset := range funcOffsets {
+		if _, err := r.Seek(int64(offset), io.SeekStart); err != nil {
+			return nil, err
+		}
+		// Each function is a list of ULEB128 values: the number of units,
+		// the function name, the file name, five values per unit, and
+		// a trailing flag indicating whether it is a function literal.
+		numUnits, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read function #%d: %w", i, err)
+		}
+		if _, err := binary.ReadUvarint(r); err != nil {
+			return nil, fmt.Errorf("failed to read function #%d: %w", i, err)
+		}
+		fileIndex, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read function #%d: %w", i, err)
+		}
+		file, err := lookup(fileIndex)
+		if err != nil {
+			return nil, fmt.Errorf("bad file name for function #%d: %w", i, err)
+		}
+		if numUnits > uint64(len(blob)) {
+			return nil, fmt.Errorf("function #%d claims an impossible %d units", i, numUnits)
+		}
+		f := function{file: file, units: make([]unit, 0, numUnits)}
+		for j := uint64(0); j < numUnits; j++ {
+			var values [5]uint32
+			for k := range values {
+				v, err := binary.ReadUvarint(r)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read unit #%d of function #%d: %w", j, i, err)
+				}
+				values[k] = uint32(v)
+			}
+			f.units = append(f.units, unit{
+				StartLine: values[0],
+				StartCol:  values[1],
+				EndLine:   values[2],
+				EndCol:    values[3],
+				NumStmt:   values[4],
+			})
+		}
+		funcs = append(funcs, f)
+	}
+	return funcs, nil
+}
+
+// readStringTable reads a string table, which is a ULEB128 count followed by
+// that many ULEB128 length-prefixed strings.
+func readStringTable(r *bytes.Reader) ([]string, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(r.Len()) {
+		return nil, fmt.Errorf("string table claims an impossible %d entries", n)
+	}
+	strings := make([]string, 0, n)
+	for i := uint64(0); i < n; i++ {
+		l, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		if l > uint64(r.Len()) {
+			return nil, fmt.Errorf("string #%d is longer than the remaining data", i)
+		}
+		b := make([]byte, l)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		strings = append(strings, string(b))
+	}
+	return strings, nil
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package history keeps a record of coverage summaries over a series of
+// commits, so that trends and regressions can be tracked without an external
+// dashboard.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/test-infra/gopherage/pkg/cov/convert"
+)
+
+// Entry is the coverage summary of a single commit.
+type Entry struct {
+	CommitID string          `json:"commit_id"`
+	Ref      string          `json:"ref,omitempty"`
+	Time     time.Time       `json:"time"`
+	Summary  convert.Summary `json:"summary"`
+}
+
+// Store is an append-only file of entries, one JSON object per line.
+type Store struct {
+	Path string
+}
+
+// Append adds entry to the end of the store, creating it if necessary.
+func (s *Store) Append(entry Entry) error {
+	if entry.CommitID == "" {
+		return errors.New("entry has no commit ID")
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode entry: %w", err)
+	}
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", s.Path, err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write to %s: %w", s.Path, err)
+	}
+	return f.Close()
+}
+
+// Load returns the entries in the store, oldest first. If a commit was
+// recorded more than once, only its last entry is kept, in the position it was
+// last recorded. A store that does not exist yet is empty.
+func (s *Store) Load() ([]Entry, error) {
+	f, err := os.Open(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", s.Path, err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64*1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", s.Path, lineNumber, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", s.Path, err)
+	}
+	return dedupe(entries), nil
+}
+
+func dedupe(entries []Entry) []Entry {
+	last := make(map[string]int, len(entries))
+	for i, e := range entries {
+		last[e.CommitID] = i
+	}
+	result := make([]Entry, 0, len(last))
+	for i, e := range entries {
+		if last[e.CommitID] == i {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Point is the coverage of something at one commit.
+type Point struct {
+	CommitID string
+	Ref      string
+	Time     time.Time
+	Rate     convert.Rate
+}
+
+// Trend returns the statement coverage of name in each of the last n entries,
+// oldest first, skipping entries in which it does not appear. If n is not
+// positive, all entries are considered.
+//
+// An empty name refers to the overall coverage. Otherwise name is looked up
+// first among packages and then among files, and matches either the full name
+// or a suffix of it following a slash, so that "pkg/foo" matches
+// "example.com/repo/pkg/foo".
+func Trend(entries []Entry, name string, n int) []Point {
+	if n > 0 && len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	var points []Point
+	for _, e := range entries {
+		rate, ok := Lookup(e.Summary, name)
+		if !ok {
+			continue
+		}
+		points = append(points, Point{CommitID: e.CommitID, Ref: e.Ref, Time: e.Time, Rate: rate})
+	}
+	return points
+}
+
+// Lookup returns the statement coverage of name in summary, as described by Trend.
+func Lookup(summary convert.Summary, name string) (convert.Rate, bool) {
+	if name == "" {
+		return summary.Statements, true
+	}
+	for _, pkg := range summary.Packages {
+		if matches(pkg.Name, name) {
+			return pkg.Statements, true
+		}
+	}
+	for _, pkg := range summary.Packages {
+		for _, file := range pkg.Files {
+			if matches(file.Name, name) {
+				return file.Statements, true
+			}
+		}
+	}
+	return convert.Rate{}, false
+}
+
+func matches(fullName, name string) bool {
+	return fullName == name || strings.HasSuffix(fullName, "/"+name)
+}
+
+// Regression is a drop in statement coverage between two commits.
+type Regression struct {
+	Name   string
+	Before Point
+	After  Point
+}
+
+// Points returns the size of the drop, in percentage points.
+func (r Regression) Points() float32 {
+	return 100 * (r.Before.Rate.Rate - r.After.Rate.Rate)
+}
+
+// FindRegressions compares the last entry with the one before it, and returns
+// every package, and the overall coverage (with an empty name), whose
+// statement coverage dropped by more than threshold percentage points.
+// If files is true, files are compared as well.
+func FindRegressions(entries []Entry, threshold float32, files bool) []Regression {
+	if len(entries) < 2 {
+		return nil
+	}
+	before, after := entries[len(entries)-2], entries[len(entries)-1]
+
+	names := []string{""}
+	for _, pkg := range after.Summary.Packages {
+		names = append(names, pkg.Name)
+		if files {
+			for _, file := range pkg.Files {
+				names = append(names, file.Name)
+			}
+		}
+	}
+
+	var regressions []Regression
+	for _, name := range names {
+		beforeRate, ok := lookupExact(before.Summary, name)
+		if !ok {
+			continue
+		}
+		afterRate, _ := lookupExact(after.Summary, name)
+		r := Regression{
+			Name:   name,
+			Before: Point{CommitID: before.CommitID, Ref: before.Ref, Time: before.Time, Rate: beforeRate},
+			After:  Point{CommitID: after.CommitID, Ref: after.Ref, Time: after.Time, Rate: afterRate},
+		}
+		if r.Points() > threshold {
+			regressions = append(regressions, r)
+		}
+	}
+	return regressions
+}
+
+func lookupExact(summary convert.Summary, name string) (convert.Rate, bool) {
+	if name == "" {
+		return summary.Statements, true
+	}
+	for _, pkg := range summary.Packages {
+		if pkg.Name == name {
+			return pkg.Statements, true
+		}
+		for _, file := range pkg.Files {
+			if file.Name == name {
+				return file.Statements, true
+			}
+		}
+	}
+	return convert.Rate{}, false
+}
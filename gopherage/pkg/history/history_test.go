@@ -0,0 +1,146 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/test-infra/gopherage/pkg/cov/convert"
+	"k8s.io/test-infra/gopherage/pkg/history"
+)
+
+func rate(covered, total int) convert.Rate {
+	return convert.Rate{Covered: covered, Total: total, Rate: float32(covered) / float32(total)}
+}
+
+func entry(commit string, foo, bar convert.Rate) history.Entry {
+	return history.Entry{
+		CommitID: commit,
+		Ref:      "main",
+		Time:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Summary: convert.Summary{
+			Statements: rate(foo.Covered+bar.Covered, foo.Total+bar.Total),
+			Packages: []convert.PackageSummary{
+				{
+					Name:       "example.com/repo/pkg/foo",
+					Statements: foo,
+					Files:      []convert.FileSummary{{Name: "example.com/repo/pkg/foo/foo.go", Statements: foo}},
+				},
+				{
+					Name:       "example.com/repo/pkg/bar",
+					Statements: bar,
+					Files:      []convert.FileSummary{{Name: "example.com/repo/pkg/bar/bar.go", Statements: bar}},
+				},
+			},
+		},
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := &history.Store{Path: filepath.Join(t.TempDir(), "history.jsonl")}
+
+	entries, err := store.Load()
+	if err != nil {
+		t.Fatalf("loading a missing store failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected a missing store to be empty, got %+v", entries)
+	}
+
+	a := entry("a", rate(1, 2), rate(3, 4))
+	b := entry("b", rate(2, 2), rate(3, 4))
+	a2 := entry("a", rate(0, 2), rate(3, 4))
+	for _, e := range []history.Entry{a, b, a2} {
+		if err := store.Append(e); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	entries, err = store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	expected := []history.Entry{b, a2}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Fatalf("bad result.\n\nexpected: %+v\nactual: %+v", expected, entries)
+	}
+}
+
+func TestAppendRequiresCommit(t *testing.T) {
+	store := &history.Store{Path: filepath.Join(t.TempDir(), "history.jsonl")}
+	if err := store.Append(history.Entry{}); err == nil {
+		t.Fatal("expected appending an entry without a commit to fail")
+	}
+}
+
+func TestTrend(t *testing.T) {
+	entries := []history.Entry{
+		entry("a", rate(1, 4), rate(1, 1)),
+		entry("b", rate(2, 4), rate(1, 1)),
+		entry("c", rate(3, 4), rate(1, 1)),
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		last     int
+		expected []convert.Rate
+	}{
+		{name: "overall", path: "", last: 0, expected: []convert.Rate{rate(2, 5), rate(3, 5), rate(4, 5)}},
+		{name: "package suffix", path: "pkg/foo", last: 2, expected: []convert.Rate{rate(2, 4), rate(3, 4)}},
+		{name: "file", path: "bar/bar.go", last: 1, expected: []convert.Rate{rate(1, 1)}},
+		{name: "unknown", path: "pkg/baz", last: 0, expected: nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var result []convert.Rate
+			for _, p := range history.Trend(entries, tc.path, tc.last) {
+				result = append(result, p.Rate)
+			}
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("bad result.\n\nexpected: %+v\nactual: %+v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestFindRegressions(t *testing.T) {
+	entries := []history.Entry{
+		entry("a", rate(80, 100), rate(50, 100)),
+		entry("b", rate(78, 100), rate(50, 100)),
+		entry("c", rate(70, 100), rate(51, 100)),
+	}
+
+	var names []string
+	for _, r := range history.FindRegressions(entries, 2, true) {
+		names = append(names, r.Name)
+	}
+	expected := []string{"", "example.com/repo/pkg/foo", "example.com/repo/pkg/foo/foo.go"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("bad result.\n\nexpected: %v\nactual: %v", expected, names)
+	}
+
+	if r := history.FindRegressions(entries, 10, false); len(r) != 0 {
+		t.Fatalf("expected no regressions above 10 points, got %+v", r)
+	}
+	if r := history.FindRegressions(entries[:1], 0, false); len(r) != 0 {
+		t.Fatalf("expected no regressions with a single entry, got %+v", r)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+	"k8s.io/test-infra/gopherage/pkg/cov/patch"
+)
+
+// ChangeFlags select the lines changed by a pull request, either from a
+// unified diff file or by asking git for the changes between two revisions.
+type ChangeFlags struct {
+	Patch   string
+	GitBase string
+	GitHead string
+	GitDir  string
+}
+
+// AddFlags adds the flags for c to fs.
+func (c *ChangeFlags) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&c.Patch, "patch", "", "unified diff file; if set, only lines added or modified by it are considered")
+	fs.StringVar(&c.GitBase, "git-base", "", "git revision to diff against; if set, only lines added or modified since it are considered")
+	fs.StringVar(&c.GitHead, "git-head", "HEAD", "git revision to diff, used with git-base")
+	fs.StringVar(&c.GitDir, "git-dir", ".", "git repository to diff, used with git-base")
+}
+
+// Enabled returns true if either a patch or a git base revision was given.
+func (c *ChangeFlags) Enabled() bool {
+	return c.Patch != "" || c.GitBase != ""
+}
+
+// LoadChanges returns the changed lines selected by the flags.
+func (c *ChangeFlags) LoadChanges() (patch.Changes, error) {
+	switch {
+	case c.Patch != "" && c.GitBase != "":
+		return nil, errors.New("at most one of --patch and --git-base may be given")
+	case c.Patch != "":
+		f, err := os.Open(c.Patch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open patch: %w", err)
+		}
+		defer f.Close()
+		return patch.ParseUnifiedDiff(f)
+	case c.GitBase != "":
+		return patch.GitDiff(c.GitDir, c.GitBase, c.GitHead)
+	}
+	return nil, errors.New("neither --patch nor --git-base was given")
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/config"
+	"k8s.io/test-infra/gopherage/pkg/cov/funccov"
+	"k8s.io/test-infra/gopherage/pkg/cov/ignore"
+)
+
+// ConfigFlags locate the config file that decides what is excluded from
+// coverage and which thresholds apply.
+type ConfigFlags struct {
+	Path string
+
+	flags *pflag.FlagSet
+}
+
+// AddFlags adds the flags for c to fs.
+func (c *ConfigFlags) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&c.Path, "config", config.DefaultFile, "gopherage config file; it is only required to exist if set explicitly")
+	c.flags = fs
+}
+
+// Load returns the config selected by the flags, or nil if the flag was left
+// at its default and there is no such file.
+func (c *ConfigFlags) Load() (*config.Config, error) {
+	cfg, err := config.Load(c.Path)
+	if errors.Is(err, fs.ErrNotExist) && (c.flags == nil || !c.flags.Changed("config")) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return cfg, nil
+}
+
+// SourceRoot returns sourceRoot if it is set. Otherwise it returns the
+// directory containing the config file if there is one (cfg may be nil), or
+// else the current directory.
+func SourceRoot(sourceRoot string, cfg *config.Config) string {
+	if sourceRoot != "" {
+		return sourceRoot
+	}
+	if cfg != nil {
+		return cfg.Root
+	}
+	return "."
+}
+
+// ApplyIgnores removes everything excluded by cfg (which may be nil) and by
+// annotations in the source from profiles. Source files are looked up in
+// SourceRoot(sourceRoot, cfg). If there is neither a config nor a sourceRoot,
+// profiles are returned unchanged, so that the result does not depend on
+// whatever source happens to be in the current directory.
+func ApplyIgnores(profiles []*cover.Profile, cfg *config.Config, sourceRoot string) ([]*cover.Profile, error) {
+	if cfg == nil && sourceRoot == "" {
+		return profiles, nil
+	}
+	resolver, err := sourceResolver(cfg, sourceRoot)
+	if err != nil {
+		return nil, err
+	}
+	return ignore.Apply(profiles, cfg, resolver)
+}
+
+// ApplyFileIgnores is like ApplyIgnores, but only removes whole files, for
+// profiles such as baselines that were recorded from another revision of the
+// source than the one in the source root.
+func ApplyFileIgnores(profiles []*cover.Profile, cfg *config.Config, sourceRoot string) ([]*cover.Profile, error) {
+	if cfg == nil && sourceRoot == "" {
+		return profiles, nil
+	}
+	resolver, err := sourceResolver(cfg, sourceRoot)
+	if err != nil {
+		return nil, err
+	}
+	return ignore.ApplyFiles(profiles, cfg, resolver)
+}
+
+func sourceResolver(cfg *config.Config, sourceRoot string) (*funccov.SourceResolver, error) {
+	sourceRoot = SourceRoot(sourceRoot, cfg)
+	resolver, err := funccov.NewSourceResolver(sourceRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source root %s: %w", sourceRoot, err)
+	}
+	return resolver, nil
+}
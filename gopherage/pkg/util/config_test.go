@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/util"
+)
+
+func TestApplyIgnores(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":   "module example.com/repo\n",
+		"a/gen.go": "// Code generated by hand. DO NOT EDIT.\n\npackage a\n\nfunc G() {\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	profiles := []*cover.Profile{{
+		FileName: "example.com/repo/a/gen.go",
+		Mode:     "set",
+		Blocks:   []cover.ProfileBlock{{StartLine: 5, StartCol: 10, EndLine: 6, EndCol: 2, NumStmt: 1}},
+	}}
+
+	// Without a config or a source root, the source in the current directory is not consulted.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	result, err := util.ApplyIgnores(profiles, nil, "")
+	if err != nil {
+		t.Fatalf("ApplyIgnores failed: %v", err)
+	}
+	if !reflect.DeepEqual(result, profiles) {
+		t.Errorf("expected the profiles to be unchanged without a config or source root, got %+v", result)
+	}
+
+	result, err = util.ApplyIgnores(profiles, nil, root)
+	if err != nil {
+		t.Fatalf("ApplyIgnores failed: %v", err)
+	}
+	if len(result) != 0 {
+		t.Errorf("expected the generated file to be dropped with a source root, got %+v", result)
+	}
+}
@@ -17,12 +17,16 @@ limitations under the License.
 package util
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
 
 	"golang.org/x/tools/cover"
 	"k8s.io/test-infra/gopherage/pkg/cov"
+	"k8s.io/test-infra/gopherage/pkg/cov/convert"
+	"k8s.io/test-infra/gopherage/pkg/cov/funccov"
+	"k8s.io/test-infra/gopherage/pkg/covdata"
 )
 
 // DumpProfile dumps the profile to the given file destination.
@@ -48,22 +52,50 @@ func DumpProfile(destination string, profile []*cover.Profile) error {
 
 // LoadProfile loads a profile from the given filename.
 // If the filename is "-", it instead reads from stdin.
+// If the filename is a directory of binary coverage data (as written to
+// GOCOVERDIR by binaries built with `go build -cover`), the counters of every
+// process in it are merged into a single profile.
+// LCOV tracefiles are also accepted, and converted to Go profiles.
 func LoadProfile(origin string) ([]*cover.Profile, error) {
-	filename := origin
+	if covdata.IsCoverageDir(origin) {
+		return covdata.ParseDir(origin)
+	}
+	var data []byte
+	var err error
 	if origin == "-" {
-		// Annoyingly, ParseProfiles only accepts a filename, so we have to write the bytes to disk
-		// so it can read them back.
-		// We could probably also just give it /dev/stdin, but that'll break on Windows.
-		tf, err := os.CreateTemp("", "")
-		if err != nil {
-			return nil, fmt.Errorf("failed to create temp file: %w", err)
-		}
-		defer tf.Close()
-		defer os.Remove(tf.Name())
-		if _, err := io.Copy(tf, os.Stdin); err != nil {
-			return nil, fmt.Errorf("failed to copy stdin to temp file: %w", err)
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(origin)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", origin, err)
+	}
+	if convert.IsLCOV(data) {
+		return convert.ParseLCOV(bytes.NewReader(data))
+	}
+	return cover.ParseProfilesFromReader(bytes.NewReader(data))
+}
+
+// LoadFunctions summarizes the coverage of every function in profile, reading
+// the source files it refers to from sourceRoot. If exportedOnly is true,
+// unexported functions and methods are omitted.
+func LoadFunctions(profile []*cover.Profile, sourceRoot string, exportedOnly bool) ([]funccov.Coverage, error) {
+	resolver, err := funccov.NewSourceResolver(sourceRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source root %s: %w", sourceRoot, err)
+	}
+	funcs, err := funccov.Summarize(profile, resolver)
+	if err != nil {
+		return nil, err
+	}
+	if !exportedOnly {
+		return funcs, nil
+	}
+	exported := make([]funccov.Coverage, 0, len(funcs))
+	for _, f := range funcs {
+		if f.Exported {
+			exported = append(exported, f)
 		}
-		filename = tf.Name()
 	}
-	return cover.ParseProfiles(filename)
+	return exported, nil
 }
@@ -23,11 +23,11 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
 
 	"k8s.io/test-infra/kubetest/e2e"
+	"k8s.io/test-infra/kubetest/inventory"
 	"k8s.io/test-infra/kubetest/process"
 	"k8s.io/test-infra/kubetest/util"
 )
@@ -74,6 +74,19 @@ func run(deploy deployer, o options) error {
 		return fmt.Errorf("failed handling --dump-pre-test-logs path: %w", err)
 	}
 
+	var (
+		resources      inventory.Inventory
+		allowedLeakage inventory.Allowlist
+	)
+	if o.checkLeaks {
+		if resources, err = newInventory(o); err != nil {
+			return err
+		}
+		if allowedLeakage, err = inventory.LoadAllowlist(o.leakAllowlist); err != nil {
+			return fmt.Errorf("failed handling --leaked-resources-allowlist: %w", err)
+		}
+	}
+
 	if o.up {
 		if err := control.XMLWrap(&suite, "TearDown Previous", deploy.Down); err != nil {
 			return fmt.Errorf("error tearing down previous cluster: %s", err)
@@ -87,15 +100,15 @@ func run(deploy deployer, o options) error {
 	)
 
 	var (
-		beforeResources []byte
-		upResources     []byte
-		downResources   []byte
-		afterResources  []byte
+		beforeResources []inventory.Resource
+		upResources     []inventory.Resource
+		downResources   []inventory.Resource
+		afterResources  []inventory.Resource
 	)
 
 	if o.checkLeaks {
 		errs = util.AppendError(errs, control.XMLWrap(&suite, "listResources Before", func() error {
-			beforeResources, err = listResources()
+			beforeResources, err = listResources(resources)
 			return err
 		}))
 	}
@@ -124,7 +137,7 @@ func run(deploy deployer, o options) error {
 			})
 		}
 		// Start the cluster using this version.
-		if err := control.XMLWrap(&suite, "Up", deploy.Up); err != nil {
+		if err := control.XMLWrapRetry(&suite, "Up", upRetry(o, deploy), deploy.Up); err != nil {
 			if dump != "" {
 				control.XMLWrap(&suite, "DumpClusterLogs (--up failed)", func() error {
 					// This frequently means the cluster does not exist.
@@ -151,7 +164,7 @@ func run(deploy deployer, o options) error {
 
 	if o.checkLeaks {
 		errs = util.AppendError(errs, control.XMLWrap(&suite, "listResources Up", func() error {
-			upResources, err = listResources()
+			upResources, err = listResources(resources)
 			return err
 		}))
 	}
@@ -269,7 +282,7 @@ func run(deploy deployer, o options) error {
 
 	if o.checkLeaks {
 		errs = util.AppendError(errs, control.XMLWrap(&suite, "listResources Down", func() error {
-			downResources, err = listResources()
+			downResources, err = listResources(resources)
 			return err
 		}))
 	}
@@ -298,13 +311,13 @@ func run(deploy deployer, o options) error {
 		log.Print("Sleeping for 30 seconds...") // Wait for eventually consistent listing
 		time.Sleep(30 * time.Second)
 		if err := control.XMLWrap(&suite, "listResources After", func() error {
-			afterResources, err = listResources()
+			afterResources, err = listResources(resources)
 			return err
 		}); err != nil {
 			errs = append(errs, err)
 		} else {
 			errs = util.AppendError(errs, control.XMLWrap(&suite, "diffResources", func() error {
-				return diffResources(beforeResources, upResources, downResources, afterResources, dump)
+				return diffResources(beforeResources, upResources, downResources, afterResources, allowedLeakage, dump)
 			}))
 		}
 	}
@@ -343,8 +356,9 @@ func getKubectlVersion(dp deployer) error {
 	copied := *cmd
 	retries := 5
 	for {
-		_, err := control.Output(&copied)
+		out, err := control.Output(&copied)
 		if err == nil {
+			control.Report.ClusterVersion = strings.TrimSpace(string(out))
 			return nil
 		}
 		retries--
@@ -413,7 +427,9 @@ func listKubemarkNodes(dp deployer, dump string) error {
 	return os.WriteFile(filepath.Join(dump, "kubemark_nodes.yaml"), b, 0644)
 }
 
-func diffResources(before, clusterUp, clusterDown, after []byte, location string) error {
+// diffResources writes the resources listed along the run and those leaked to
+// leaked-resources.json, and returns an error listing each leaked resource.
+func diffResources(before, clusterUp, clusterDown, after []inventory.Resource, allow inventory.Allowlist, location string) error {
 	if location == "" {
 		var err error
 		location, err = os.MkdirTemp("", "e2e-check-resources")
@@ -422,63 +438,38 @@ func diffResources(before, clusterUp, clusterDown, after []byte, location string
 		}
 	}
 
-	var mode os.FileMode = 0664
-	bp := filepath.Join(location, "gcp-resources-before.txt")
-	up := filepath.Join(location, "gcp-resources-cluster-up.txt")
-	cdp := filepath.Join(location, "gcp-resources-cluster-down.txt")
-	ap := filepath.Join(location, "gcp-resources-after.txt")
-	dp := filepath.Join(location, "gcp-resources-diff.txt")
-
-	if err := os.WriteFile(bp, before, mode); err != nil {
-		return err
-	}
-	if err := os.WriteFile(up, clusterUp, mode); err != nil {
-		return err
-	}
-	if err := os.WriteFile(cdp, clusterDown, mode); err != nil {
-		return err
-	}
-	if err := os.WriteFile(ap, after, mode); err != nil {
+	result := inventory.NewResult(before, clusterUp, clusterDown, after, allow)
+	if err := result.Write(filepath.Join(location, "leaked-resources.json")); err != nil {
 		return err
 	}
+	return result.Err()
+}
 
-	stdout, cerr := control.Output(exec.Command("diff", "-sw", "-U0", "-F^\\[.*\\]$", bp, ap))
-	if err := os.WriteFile(dp, stdout, mode); err != nil {
-		return err
-	}
-	if cerr == nil { // No diffs
-		return nil
-	}
-	lines := strings.Split(string(stdout), "\n")
-	if len(lines) < 3 { // Ignore the +++ and --- header lines
-		return nil
+func listResources(resources inventory.Inventory) ([]inventory.Resource, error) {
+	log.Printf("Listing resources...")
+	r, err := resources.List()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list resources: %w", err)
 	}
-	lines = lines[2:]
+	return r, nil
+}
 
-	var added, report []string
-	resourceTypeRE := regexp.MustCompile(`^@@.+\s(\[\s\S+\s\])$`)
-	for _, l := range lines {
-		if matches := resourceTypeRE.FindStringSubmatch(l); matches != nil {
-			report = append(report, matches[1])
+// newInventory returns the inventory of the resources that --check-leaked-resources checks.
+func newInventory(o options) (inventory.Inventory, error) {
+	switch o.provider {
+	case "gce", "gke":
+		region := o.gcpRegion
+		if i := strings.LastIndex(o.gcpZone, "-"); region == "" && i > 0 {
+			region = o.gcpZone[:i] // us-central1-f is in us-central1
 		}
-		if strings.HasPrefix(l, "+") && len(strings.TrimPrefix(l, "+")) > 0 {
-			added = append(added, l)
-			report = append(report, l)
+		// Like cluster/gce/config-default.sh, which cluster/gce/list-resources.sh reads.
+		prefix := os.Getenv("KUBE_GCE_INSTANCE_PREFIX")
+		if prefix == "" {
+			prefix = "kubernetes"
 		}
+		return inventory.NewGCE(o.gcpProject, region, o.gcpZone, prefix, control.Output), nil
 	}
-	if len(added) > 0 {
-		return fmt.Errorf("Error: %d leaked resources\n%v", len(added), strings.Join(report, "\n"))
-	}
-	return nil
-}
-
-func listResources() ([]byte, error) {
-	log.Printf("Listing resources...")
-	stdout, err := control.Output(exec.Command("./cluster/gce/list-resources.sh"))
-	if err != nil {
-		return stdout, fmt.Errorf("Failed to list resources (%s):\n%s", err, string(stdout))
-	}
-	return stdout, err
+	return nil, fmt.Errorf("--check-leaked-resources does not support --provider=%s", o.provider)
 }
 
 func clusterSize(deploy deployer) (int, error) {
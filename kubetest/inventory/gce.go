@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// gceKinds are the gcloud compute groups of the GCE resources that GCE lists, like
+// cluster/gce/list-resources.sh does. The kind of the resources is the last word.
+var gceKinds = []string{
+	"instance-templates",
+	"instance-groups",
+	"instances",
+	"disks",
+	"addresses",
+	"target-pools",
+	"forwarding-rules",
+	"firewall-rules",
+	"routes",
+	"networks",
+	"networks subnets",
+	"routers",
+	"backend-services",
+	"health-checks",
+	"http-health-checks",
+	"target-http-proxies",
+	"target-https-proxies",
+	"url-maps",
+	"ssl-certificates",
+}
+
+// gceClusterKinds are the groups whose resources belong to a single cluster, which GCE only lists if
+// they are named after its instance prefix and, when zonal, are in its zone, like list-resources.sh.
+var gceClusterKinds = map[string]bool{
+	"instance-templates": true,
+	"instance-groups":    true,
+	"instances":          true,
+	"disks":              true,
+}
+
+// GCE is the Inventory of the GCE resources of a project.
+type GCE struct {
+	project        string
+	region         string
+	zone           string
+	instancePrefix string
+	output         func(*exec.Cmd) ([]byte, error)
+}
+
+// NewGCE returns the inventory of the GCE resources of a cluster in project, which lists them with
+// gcloud by calling output. Only global resources and those in region are listed, unless region is "".
+// Instances, disks, instance groups and instance templates are only listed if their name starts with
+// instancePrefix and, unless zone is "", if they are in zone.
+func NewGCE(project, region, zone, instancePrefix string, output func(*exec.Cmd) ([]byte, error)) *GCE {
+	return &GCE{project: project, region: region, zone: zone, instancePrefix: instancePrefix, output: output}
+}
+
+// gceResource holds the fields of a GCE resource listed by gcloud.
+type gceResource struct {
+	Name string `json:"name"`
+	// Zone and Region are the URLs of the location of the resource, if any.
+	Zone   string            `json:"zone"`
+	Region string            `json:"region"`
+	Labels map[string]string `json:"labels"`
+}
+
+// List returns the GCE resources of the project, scoped as described by NewGCE.
+func (g *GCE) List() ([]Resource, error) {
+	var resources []Resource
+	for _, group := range gceKinds {
+		args := append([]string{"compute"}, strings.Fields(group)...)
+		args = append(args, "list", "--project="+g.project, "--format=json")
+		out, err := g.output(exec.Command("gcloud", args...))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", group, err)
+		}
+		var items []gceResource
+		if err := json.Unmarshal(out, &items); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", group, err)
+		}
+
+		kind := group[strings.LastIndex(group, " ")+1:]
+		for _, item := range items {
+			var location string
+			if item.Zone != "" {
+				location = path.Base(item.Zone)
+			} else if item.Region != "" {
+				location = path.Base(item.Region)
+			}
+			if !g.inRegion(location) || !g.inCluster(group, item) {
+				continue
+			}
+			resources = append(resources, Resource{
+				Kind:   kind,
+				Name:   item.Name,
+				Region: location,
+				Labels: item.Labels,
+			})
+		}
+	}
+	return resources, nil
+}
+
+// inRegion returns true if location, a region or a zone, is in the region of g, or is global.
+func (g *GCE) inRegion(location string) bool {
+	return g.region == "" || location == "" || location == g.region || strings.HasPrefix(location, g.region+"-")
+}
+
+// inCluster returns true if item, a resource of group, may belong to the cluster of g.
+func (g *GCE) inCluster(group string, item gceResource) bool {
+	if !gceClusterKinds[group] {
+		return true
+	}
+	return strings.HasPrefix(item.Name, g.instancePrefix) && (g.zone == "" || item.Zone == "" || path.Base(item.Zone) == g.zone)
+}
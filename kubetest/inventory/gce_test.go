@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeGcloud returns the output of gcloud commands by the group they list, and "[]" for others.
+func fakeGcloud(outputs map[string]string, listed *[]string) func(*exec.Cmd) ([]byte, error) {
+	return func(cmd *exec.Cmd) ([]byte, error) {
+		args := strings.Join(cmd.Args, " ")
+		*listed = append(*listed, args)
+		for group, out := range outputs {
+			if strings.HasPrefix(args, "gcloud compute "+group+" list ") {
+				if out == "error" {
+					return nil, errors.New("exit status 1")
+				}
+				return []byte(out), nil
+			}
+		}
+		return []byte("[]"), nil
+	}
+}
+
+func TestGCEList(t *testing.T) {
+	outputs := map[string]string{
+		"instances": `[
+			{"name": "master", "zone": "https://www.googleapis.com/compute/v1/projects/p/zones/us-central1-f", "labels": {"cluster": "e2e"}},
+			{"name": "other", "zone": "https://www.googleapis.com/compute/v1/projects/p/zones/europe-west1-b"}
+		]`,
+		"addresses":        `[{"name": "master-ip", "region": "https://www.googleapis.com/compute/v1/projects/p/regions/us-central1"}]`,
+		"networks":         `[{"name": "default"}]`,
+		"networks subnets": `[{"name": "default", "region": "https://www.googleapis.com/compute/v1/projects/p/regions/us-central10"}]`,
+	}
+	var listed []string
+	resources, err := NewGCE("p", "us-central1", "", "", fakeGcloud(outputs, &listed)).List()
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+
+	expected := []Resource{
+		{Kind: "instances", Name: "master", Region: "us-central1-f", Labels: map[string]string{"cluster": "e2e"}},
+		{Kind: "addresses", Name: "master-ip", Region: "us-central1"},
+		{Kind: "networks", Name: "default"},
+	}
+	if !reflect.DeepEqual(resources, expected) {
+		t.Errorf("expected resources %v, got %v", expected, resources)
+	}
+	if len(listed) != len(gceKinds) {
+		t.Errorf("expected to list %d groups, listed %v", len(gceKinds), listed)
+	}
+	if cmd := "gcloud compute networks subnets list --project=p --format=json"; listed[10] != cmd {
+		t.Errorf("expected to list subnets with %q, got %q", cmd, listed[10])
+	}
+}
+
+func TestGCEListAllRegions(t *testing.T) {
+	outputs := map[string]string{
+		"instances": `[{"name": "other", "zone": "https://www.googleapis.com/compute/v1/projects/p/zones/europe-west1-b"}]`,
+	}
+	var listed []string
+	resources, err := NewGCE("p", "", "", "", fakeGcloud(outputs, &listed)).List()
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+	if expected := []Resource{{Kind: "instances", Name: "other", Region: "europe-west1-b"}}; !reflect.DeepEqual(resources, expected) {
+		t.Errorf("expected resources %v, got %v", expected, resources)
+	}
+}
+
+func TestGCEListCluster(t *testing.T) {
+	const zones = "https://www.googleapis.com/compute/v1/projects/p/zones/"
+	outputs := map[string]string{
+		"instance-templates": `[{"name": "e2e-minion-template"}, {"name": "other-template"}]`,
+		"instance-groups": `[
+			{"name": "e2e-minion-group", "zone": "` + zones + `us-central1-f"},
+			{"name": "e2e-minion-group", "zone": "` + zones + `us-central1-b"}
+		]`,
+		"instances": `[
+			{"name": "e2e-master", "zone": "` + zones + `us-central1-f"},
+			{"name": "other-master", "zone": "` + zones + `us-central1-f"}
+		]`,
+		"disks":          `[{"name": "e2e-master-pd", "zone": "` + zones + `us-central1-f"}, {"name": "data", "zone": "` + zones + `us-central1-f"}]`,
+		"firewall-rules": `[{"name": "default-allow-ssh"}]`,
+	}
+	var listed []string
+	resources, err := NewGCE("p", "us-central1", "us-central1-f", "e2e", fakeGcloud(outputs, &listed)).List()
+	if err != nil {
+		t.Fatalf("failed to list: %v", err)
+	}
+
+	expected := []Resource{
+		{Kind: "instance-templates", Name: "e2e-minion-template"},
+		{Kind: "instance-groups", Name: "e2e-minion-group", Region: "us-central1-f"},
+		{Kind: "instances", Name: "e2e-master", Region: "us-central1-f"},
+		{Kind: "disks", Name: "e2e-master-pd", Region: "us-central1-f"},
+		{Kind: "firewall-rules", Name: "default-allow-ssh"},
+	}
+	if !reflect.DeepEqual(resources, expected) {
+		t.Errorf("expected resources %v, got %v", expected, resources)
+	}
+}
+
+func TestGCEListErrors(t *testing.T) {
+	for group, out := range map[string]string{"disks": "error", "routes": "not json"} {
+		var listed []string
+		if _, err := NewGCE("p", "", "", "", fakeGcloud(map[string]string{group: out}, &listed)).List(); err == nil || !strings.Contains(err.Error(), group) {
+			t.Errorf("expected an error listing %s, got %v", group, err)
+		}
+	}
+}
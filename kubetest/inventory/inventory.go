@@ -0,0 +1,202 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package inventory lists the resources of cloud providers, so that kubetest can check that a
+// run does not leak any of them.
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Resource is a resource of a cloud provider, such as a GCE instance.
+type Resource struct {
+	// Kind is the kind of the resource, such as "instances".
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Region is the region or zone of the resource, or "" if it is global.
+	Region string            `json:"region,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// String returns the kind, region and name of r.
+func (r Resource) String() string {
+	if r.Region == "" {
+		return fmt.Sprintf("%s %s", r.Kind, r.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", r.Kind, r.Region, r.Name)
+}
+
+// key identifies r among the resources of an inventory.
+func (r Resource) key() string {
+	return r.Kind + "/" + r.Region + "/" + r.Name
+}
+
+// Inventory lists the resources of a cloud provider.
+type Inventory interface {
+	List() ([]Resource, error)
+}
+
+// Rule allows leaking the resources that it matches.
+type Rule struct {
+	// Kind is the kind of the resources, or "" for any kind.
+	Kind string `json:"kind,omitempty"`
+	// Name is a regular expression that the whole name of the resources matches, or "" for any name.
+	Name string `json:"name,omitempty"`
+	// Labels are labels that the resources have.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	name *regexp.Regexp
+}
+
+// Allowlist is a list of rules for resources that may be leaked, such as those that the cloud
+// provider creates by itself.
+type Allowlist []Rule
+
+// LoadAllowlist reads an Allowlist from a YAML or JSON file. There are no rules if path is "".
+func LoadAllowlist(path string) (Allowlist, error) {
+	if path == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allowlist: %w", err)
+	}
+	var a Allowlist
+	if err := yaml.Unmarshal(b, &a); err != nil {
+		return nil, fmt.Errorf("failed to parse allowlist %s: %w", path, err)
+	}
+	for i := range a {
+		if err := a[i].compile(); err != nil {
+			return nil, fmt.Errorf("invalid rule %d of allowlist %s: %w", i, path, err)
+		}
+	}
+	return a, nil
+}
+
+func (r *Rule) compile() error {
+	if r.Name == "" {
+		return nil
+	}
+	name, err := regexp.Compile("^(?:" + r.Name + ")$")
+	if err != nil {
+		return err
+	}
+	r.name = name
+	return nil
+}
+
+// Matches returns true if res matches the kind, name and labels of r.
+func (r Rule) Matches(res Resource) bool {
+	if r.Kind != "" && r.Kind != res.Kind {
+		return false
+	}
+	if r.Name != "" {
+		if r.name == nil {
+			if err := r.compile(); err != nil {
+				return false
+			}
+		}
+		if !r.name.MatchString(res.Name) {
+			return false
+		}
+	}
+	for k, v := range r.Labels {
+		if res.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Allows returns true if any of the rules matches r.
+func (a Allowlist) Allows(r Resource) bool {
+	for _, rule := range a {
+		if rule.Matches(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Leaks returns the resources of after that are not in before and that allow does not allow,
+// sorted by kind, region and name.
+func Leaks(before, after []Resource, allow Allowlist) []Resource {
+	existed := map[string]bool{}
+	for _, r := range before {
+		existed[r.key()] = true
+	}
+	leaked := []Resource{}
+	for _, r := range after {
+		if !existed[r.key()] && !allow.Allows(r) {
+			leaked = append(leaked, r)
+		}
+	}
+	sort.Slice(leaked, func(i, j int) bool {
+		return leaked[i].key() < leaked[j].key()
+	})
+	return leaked
+}
+
+// Result is the result of a check for leaked resources, with the resources listed along the
+// way to help finding what leaked them.
+type Result struct {
+	Before      []Resource `json:"before"`
+	ClusterUp   []Resource `json:"clusterUp,omitempty"`
+	ClusterDown []Resource `json:"clusterDown,omitempty"`
+	After       []Resource `json:"after"`
+	// Leaked are the resources of After that are neither in Before nor allowed.
+	Leaked []Resource `json:"leaked"`
+}
+
+// NewResult checks the resources listed before and after a run for leaks.
+func NewResult(before, clusterUp, clusterDown, after []Resource, allow Allowlist) *Result {
+	return &Result{
+		Before:      before,
+		ClusterUp:   clusterUp,
+		ClusterDown: clusterDown,
+		After:       after,
+		Leaked:      Leaks(before, after, allow),
+	}
+}
+
+// Err returns an error that lists each leaked resource, or nil if none leaked.
+func (r *Result) Err() error {
+	if len(r.Leaked) == 0 {
+		return nil
+	}
+	lines := make([]string, 0, len(r.Leaked))
+	for _, res := range r.Leaked {
+		lines = append(lines, "  "+res.String())
+	}
+	return fmt.Errorf("%d leaked resources:\n%s", len(r.Leaked), strings.Join(lines, "\n"))
+}
+
+// Write writes r as JSON to path.
+func (r *Result) Write(path string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0664)
+}
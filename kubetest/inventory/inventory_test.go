@@ -0,0 +1,186 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeInventory returns each of its lists in turn, like a project that changes between calls to List.
+type fakeInventory struct {
+	lists [][]Resource
+}
+
+func (f *fakeInventory) List() ([]Resource, error) {
+	l := f.lists[0]
+	f.lists = f.lists[1:]
+	return l, nil
+}
+
+func TestLeaks(t *testing.T) {
+	network := Resource{Kind: "networks", Name: "default"}
+	instance := Resource{Kind: "instances", Name: "master", Region: "us-central1-f"}
+	disk := Resource{Kind: "disks", Name: "master-pd", Region: "us-central1-f"}
+	route := Resource{Kind: "routes", Name: "default-route-1234"}
+	kept := Resource{Kind: "addresses", Name: "ingress", Region: "us-central1", Labels: map[string]string{"keep": "true"}}
+
+	cases := []struct {
+		name   string
+		before []Resource
+		after  []Resource
+		allow  Allowlist
+		leaked []Resource
+	}{
+		{
+			name:   "nothing leaked",
+			before: []Resource{network},
+			after:  []Resource{network},
+			leaked: []Resource{},
+		},
+		{
+			name:   "deleted resources are not leaks",
+			before: []Resource{network, instance},
+			after:  []Resource{network},
+			leaked: []Resource{},
+		},
+		{
+			name:   "new resources are leaks, sorted",
+			before: []Resource{network},
+			after:  []Resource{network, instance, disk},
+			leaked: []Resource{disk, instance},
+		},
+		{
+			name:   "the same name in another region is a leak",
+			before: []Resource{instance},
+			after:  []Resource{instance, {Kind: "instances", Name: "master", Region: "us-east1-b"}},
+			leaked: []Resource{{Kind: "instances", Name: "master", Region: "us-east1-b"}},
+		},
+		{
+			name:   "allowed resources are not leaks",
+			before: []Resource{network},
+			after:  []Resource{network, instance, route, kept},
+			allow: Allowlist{
+				{Kind: "routes", Name: "default-route-.*"},
+				{Labels: map[string]string{"keep": "true"}},
+			},
+			leaked: []Resource{instance},
+		},
+		{
+			name:   "rules match whole names",
+			before: []Resource{network},
+			after:  []Resource{network, route},
+			allow:  Allowlist{{Kind: "routes", Name: "default-route"}},
+			leaked: []Resource{route},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inv := &fakeInventory{lists: [][]Resource{tc.before, tc.after}}
+			before, _ := inv.List()
+			after, _ := inv.List()
+			if leaked := Leaks(before, after, tc.allow); !reflect.DeepEqual(leaked, tc.leaked) {
+				t.Errorf("expected leaks %v, got %v", tc.leaked, leaked)
+			}
+		})
+	}
+}
+
+func TestLoadAllowlist(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "allowlist.yaml")
+	contents := `
+- kind: routes
+  name: default-route-.*
+- labels:
+    keep: "true"
+`
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a, err := LoadAllowlist(path)
+	if err != nil {
+		t.Fatalf("failed to load allowlist: %v", err)
+	}
+	if len(a) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(a))
+	}
+	if !a.Allows(Resource{Kind: "routes", Name: "default-route-1"}) || a.Allows(Resource{Kind: "instances", Name: "default-route-1"}) {
+		t.Error("expected the first rule to only allow default routes")
+	}
+	if !a.Allows(Resource{Kind: "disks", Name: "data", Labels: map[string]string{"keep": "true"}}) {
+		t.Error("expected the second rule to allow resources labeled keep=true")
+	}
+
+	if a, err := LoadAllowlist(""); a != nil || err != nil {
+		t.Errorf("expected no rules and no error without a path, got %v, %v", a, err)
+	}
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("- name: '('\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadAllowlist(invalid); err == nil {
+		t.Error("expected an error for an invalid name")
+	}
+	if _, err := LoadAllowlist(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing allowlist")
+	}
+}
+
+func TestResult(t *testing.T) {
+	before := []Resource{{Kind: "networks", Name: "default"}}
+	up := append(before, Resource{Kind: "instances", Name: "master", Region: "us-central1-f"})
+	after := append(before, Resource{Kind: "firewall-rules", Name: "e2e-ports"}, Resource{Kind: "disks", Name: "pd", Region: "us-central1-f"})
+
+	r := NewResult(before, up, before, after, nil)
+	err := r.Err()
+	if err == nil {
+		t.Fatal("expected an error for the leaked resources")
+	}
+	expected := "2 leaked resources:\n  disks us-central1-f/pd\n  firewall-rules e2e-ports"
+	if err.Error() != expected {
+		t.Errorf("expected error %q, got %q", expected, err.Error())
+	}
+
+	path := filepath.Join(t.TempDir(), "leaked-resources.json")
+	if err := r.Write(path); err != nil {
+		t.Fatalf("failed to write the result: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written Result
+	if err := json.Unmarshal(b, &written); err != nil {
+		t.Fatalf("failed to parse the result: %v", err)
+	}
+	if !reflect.DeepEqual(&written, r) {
+		t.Errorf("expected to read back %+v, got %+v", r, written)
+	}
+
+	if err := NewResult(before, nil, nil, before, nil).Err(); err != nil {
+		t.Errorf("expected no error without leaks, got %v", err)
+	}
+	if !strings.Contains(string(b), `"leaked"`) {
+		t.Errorf("expected the leaked resources in the JSON, got %s", b)
+	}
+}
@@ -37,6 +37,7 @@ import (
 
 	"k8s.io/test-infra/kubetest/conformance"
 	"k8s.io/test-infra/kubetest/kind"
+	"k8s.io/test-infra/kubetest/plugin"
 	"k8s.io/test-infra/kubetest/process"
 	"k8s.io/test-infra/kubetest/util"
 )
@@ -93,10 +94,12 @@ type options struct {
 	gcpRegion               string
 	gcpZone                 string
 	ginkgoParallel          ginkgoParallelValue
+	junitOutputLimit        int
 	kubecfg                 string
 	kubemark                bool
 	kubemarkMasterSize      string
 	kubemarkNodes           string // TODO(fejta): switch to int after migration
+	leakAllowlist           string
 	logexporterGCSPath      string
 	metadataSources         string
 	noAllowDup              bool
@@ -122,6 +125,8 @@ type options struct {
 	testCmdName             string
 	testCmdArgs             []string
 	up                      bool
+	upRetries               int
+	upRetryBackoff          time.Duration
 	upgradeArgs             string
 	version                 bool
 }
@@ -133,12 +138,14 @@ func defineFlags() *options {
 	flag.BoolVar(&o.charts, "charts", false, "If true, run charts tests")
 	flag.BoolVar(&o.checkSkew, "check-version-skew", true, "Verify client and server versions match")
 	flag.BoolVar(&o.checkLeaks, "check-leaked-resources", false, "Ensure project ends with the same resources")
+	flag.StringVar(&o.leakAllowlist, "leaked-resources-allowlist", "", "Path to a YAML list of rules (kind, name regexp, labels) for resources that --check-leaked-resources allows to leak")
 	flag.StringVar(&o.cluster, "cluster", "", "Cluster name. Must be set for --deployment=gke (TODO: other deployments).")
 	flag.StringVar(&o.clusterIPRange, "cluster-ip-range", "", "Specifies CLUSTER_IP_RANGE value during --up and --test (only relevant for --deployment=bash). Auto-calculated if empty.")
-	flag.StringVar(&o.deployment, "deployment", "bash", "Choices: none/bash/conformance/gke/kind/kops/node/local")
+	flag.StringVar(&o.deployment, "deployment", "bash", "Choices: none/bash/conformance/gke/kind/kops/node/local, or exec:<binary> for an out-of-tree deployer")
 	flag.BoolVar(&o.down, "down", false, "If true, tear down the cluster before exiting.")
 	flag.StringVar(&o.dump, "dump", "", "If set, dump bring-up and cluster logs to this location on test or cluster-up failure")
 	flag.StringVar(&o.dumpPreTestLogs, "dump-pre-test-logs", "", "If set, dump cluster logs to this location before running tests")
+	flag.IntVar(&o.junitOutputLimit, "junit-output-limit", process.DefaultOutputLimit, "Bytes of stdout and of stderr to keep in each junit_runner.xml test case, or 0 to keep none")
 	flag.Var(&o.extract, "extract", "Extract k8s binaries from the specified release location")
 	flag.StringVar(&o.extractCIBucket, "extract-ci-bucket", "k8s-release-dev", "Extract k8s CI binaries from the specified GCS bucket")
 	flag.StringVar(&o.extractReleaseBucket, "extract-release-bucket", "kubernetes-release", "Extract k8s release binaries from the specified GCS bucket")
@@ -192,6 +199,8 @@ func defineFlags() *options {
 	flag.StringVar(&o.testCmdName, "test-cmd-name", "", "name to log the test command as in xml results")
 	flag.DurationVar(&timeout, "timeout", time.Duration(0), "Terminate testing after the timeout duration (s/m/h)")
 	flag.BoolVar(&o.up, "up", false, "If true, start the e2e cluster. If cluster is already up, recreate it.")
+	flag.IntVar(&o.upRetries, "up-retries", 0, "Number of times to retry --up after a transient failure, tearing down the cluster before each retry")
+	flag.DurationVar(&o.upRetryBackoff, "up-retry-backoff", time.Minute, "Time to wait before the first retry of --up, doubling for each later retry")
 	flag.StringVar(&o.upgradeArgs, "upgrade_args", "", "If set, run upgrade tests before other tests")
 	flag.BoolVar(&o.version, "version", false, "Command to print version")
 
@@ -244,6 +253,9 @@ type publisher interface {
 }
 
 func getDeployer(o *options) (deployer, error) {
+	if strings.HasPrefix(o.deployment, plugin.DeploymentPrefix) {
+		return plugin.NewDeployer(control, strings.TrimPrefix(o.deployment, plugin.DeploymentPrefix))
+	}
 	switch o.deployment {
 	case "bash":
 		return newBash(&o.clusterIPRange, o.gcpProject, o.gcpZone, o.gcpSSHProxyInstanceName, o.provider), nil
@@ -274,6 +286,9 @@ func validateFlags(o *options) error {
 	if !o.extract.Enabled() && o.extractSource {
 		return errors.New("--extract-source flag cannot be passed without --extract")
 	}
+	if o.upRetries < 0 {
+		return errors.New("--up-retries must not be negative")
+	}
 	return nil
 }
 
@@ -298,6 +313,7 @@ func main() {
 	}
 
 	control = process.NewControl(timeout, interrupt, terminate, verbose)
+	control.OutputLimit = o.junitOutputLimit
 
 	// do things when we know we are running in the kubetest image
 	if os.Getenv("KUBETEST_IN_DOCKER") == "true" {
@@ -340,9 +356,14 @@ func complete(o *options) error {
 		interrupt.Reset(timeout)
 	}
 
+	control.Report.Deployer = o.deployment
+	if o.testCmdName != "" {
+		control.Report.SetStepPhase(o.testCmdName, process.PhaseTest)
+	}
 	if o.dump != "" {
 		defer writeMetadata(o.dump, o.metadataSources)
 		defer control.WriteXML(&suite, o.dump, time.Now())
+		defer control.WriteReport(o.dump, time.Now())
 	}
 	if o.logexporterGCSPath != "" {
 		o.testArgs += fmt.Sprintf(" --logexporter-gcs-path=%s", o.logexporterGCSPath)
@@ -766,6 +787,7 @@ func prepareGcp(o *options) error {
 		if p == nil {
 			return fmt.Errorf("boskos does not have a free %s at the moment", resType)
 		}
+		control.Report.AddBoskosResource(resType, p.Name)
 
 		go func(c *client.Client, proj string) {
 			for range time.Tick(time.Minute * 5) {
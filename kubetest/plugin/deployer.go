@@ -0,0 +1,144 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"k8s.io/test-infra/kubetest/process"
+)
+
+// DeploymentPrefix is the prefix of the --deployment flag that selects an out-of-tree deployer,
+// followed by the path or name of its binary.
+const DeploymentPrefix = "exec:"
+
+// Deployer is an object that satisfies the kubetest main deployer interface by calling a binary.
+type Deployer struct {
+	control *process.Control
+	binary  string
+}
+
+// NewDeployer creates a new deployer that calls binary, which is looked up in $PATH if it does not
+// contain a path separator.
+func NewDeployer(ctl *process.Control, binary string) (*Deployer, error) {
+	if ctl == nil {
+		return nil, fmt.Errorf("exec deployer received nil Control")
+	}
+	if binary == "" {
+		return nil, fmt.Errorf("exec deployer requires a binary, as in --deployment=%s<binary>", DeploymentPrefix)
+	}
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("exec deployer binary %q not found: %w", binary, err)
+	}
+	return &Deployer{control: ctl, binary: path}, nil
+}
+
+// call runs the binary with req and returns its response.
+func (d *Deployer) call(req Request) (Response, error) {
+	req.Version = Version
+	in, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, err
+	}
+
+	var out bytes.Buffer
+	cmd := exec.Command(d.binary, req.Method)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &out
+	cmd.Stderr = os.Stderr
+	runErr := d.control.FinishRunning(cmd)
+
+	var resp Response
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		if runErr != nil {
+			return Response{}, fmt.Errorf("%s failed: %w", req.Method, runErr)
+		}
+		return Response{}, fmt.Errorf("%s returned an invalid response %q: %w", req.Method, out.String(), err)
+	}
+	if resp.Version != Version {
+		return Response{}, fmt.Errorf("%s returned a response of protocol version %q, expected %q", req.Method, resp.Version, Version)
+	}
+	if err := responseError(resp); err != nil {
+		return Response{}, err
+	}
+	if runErr != nil {
+		return Response{}, fmt.Errorf("%s failed: %w", req.Method, runErr)
+	}
+	return resp, nil
+}
+
+// Up brings up the cluster.
+func (d *Deployer) Up() error {
+	_, err := d.call(Request{Method: MethodUp})
+	return err
+}
+
+// IsUp returns nil if the cluster is up.
+func (d *Deployer) IsUp() error {
+	_, err := d.call(Request{Method: MethodIsUp})
+	return err
+}
+
+// DumpClusterLogs dumps the logs of the cluster to localPath, and uploads them to gcsPath if it is set.
+func (d *Deployer) DumpClusterLogs(localPath, gcsPath string) error {
+	_, err := d.call(Request{Method: MethodDumpClusterLogs, LocalPath: localPath, GCSPath: gcsPath})
+	return err
+}
+
+// TestSetup prepares the cluster for testing.
+func (d *Deployer) TestSetup() error {
+	_, err := d.call(Request{Method: MethodTestSetup})
+	return err
+}
+
+// Down tears down the cluster.
+func (d *Deployer) Down() error {
+	_, err := d.call(Request{Method: MethodDown})
+	return err
+}
+
+// GetClusterCreated returns when the cluster was created.
+func (d *Deployer) GetClusterCreated(gcpProject string) (time.Time, error) {
+	resp, err := d.call(Request{Method: MethodGetClusterCreated, GCPProject: gcpProject})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if resp.Created == nil {
+		return time.Time{}, errors.New("GetClusterCreated returned no creation time")
+	}
+	return *resp.Created, nil
+}
+
+// KubectlCommand returns the command to run kubectl with, or nil to use the default.
+func (d *Deployer) KubectlCommand() (*exec.Cmd, error) {
+	resp, err := d.call(Request{Method: MethodKubectlCommand})
+	if err != nil || resp.Command == nil {
+		return nil, err
+	}
+	cmd := exec.Command(resp.Command.Path, resp.Command.Args...)
+	if len(resp.Command.Env) > 0 {
+		cmd.Env = append(os.Environ(), resp.Command.Env...)
+	}
+	return cmd, nil
+}
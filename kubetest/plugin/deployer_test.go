@@ -0,0 +1,232 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/test-infra/kubetest/plugin"
+	"k8s.io/test-infra/kubetest/plugin/fake"
+	"k8s.io/test-infra/kubetest/process"
+	"k8s.io/test-infra/kubetest/util"
+)
+
+// fakeEnv makes the test binary run as the fake deployer, so that it can be used as the binary of
+// a plugin.Deployer.
+const fakeEnv = "KUBETEST_PLUGIN_TEST_FAKE"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeEnv) != "" {
+		fake.Main()
+	}
+	os.Exit(m.Run())
+}
+
+// newFakeDeployer returns a plugin.Deployer that runs the fake deployer with its state in a new
+// directory, and that directory.
+func newFakeDeployer(t *testing.T, fail string) (*plugin.Deployer, *process.Control, string) {
+	dir := t.TempDir()
+	t.Setenv(fakeEnv, "true")
+	t.Setenv(fake.DirEnv, dir)
+	t.Setenv(fake.FailEnv, fail)
+
+	ctl := process.NewControl(time.Hour, time.NewTimer(time.Hour), time.NewTimer(time.Hour), false)
+	d, err := plugin.NewDeployer(ctl, os.Args[0])
+	if err != nil {
+		t.Fatalf("Failed to create deployer: %v", err)
+	}
+	return d, ctl, dir
+}
+
+func TestDeployerLifecycle(t *testing.T) {
+	d, _, dir := newFakeDeployer(t, "")
+
+	if err := d.IsUp(); err == nil || !strings.Contains(err.Error(), "the fake cluster is down") {
+		t.Errorf("Expected IsUp to fail before Up with the error of the fake, got %v", err)
+	}
+	before := time.Now().Add(-time.Second)
+	if err := d.Up(); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	if err := d.IsUp(); err != nil {
+		t.Errorf("IsUp failed after Up: %v", err)
+	}
+	if err := d.TestSetup(); err != nil {
+		t.Errorf("TestSetup failed: %v", err)
+	}
+	created, err := d.GetClusterCreated("project")
+	if err != nil {
+		t.Errorf("GetClusterCreated failed: %v", err)
+	} else if created.Before(before) || created.After(time.Now()) {
+		t.Errorf("Expected the cluster to be created after %v and before now, got %v", before, created)
+	}
+
+	cmd, err := d.KubectlCommand()
+	if err != nil {
+		t.Fatalf("KubectlCommand failed: %v", err)
+	}
+	if cmd == nil || cmd.Args[0] != "kubectl" {
+		t.Errorf("Expected a kubectl command, got %v", cmd)
+	} else if kubeconfig := "KUBECONFIG=" + filepath.Join(dir, "kubeconfig"); cmd.Env[len(cmd.Env)-1] != kubeconfig {
+		t.Errorf("Expected the kubectl command to set %s, got environment %q", kubeconfig, cmd.Env)
+	}
+
+	logs := filepath.Join(dir, "artifacts")
+	if err := d.DumpClusterLogs(logs, ""); err != nil {
+		t.Errorf("DumpClusterLogs failed: %v", err)
+	} else if _, err := os.Stat(filepath.Join(logs, "fake-cluster.log")); err != nil {
+		t.Errorf("Expected DumpClusterLogs to write the log of the fake cluster: %v", err)
+	}
+
+	if err := d.Down(); err != nil {
+		t.Errorf("Down failed: %v", err)
+	}
+	if err := d.IsUp(); err == nil {
+		t.Error("Expected IsUp to fail after Down")
+	}
+}
+
+func TestDeployerXMLWrap(t *testing.T) {
+	d, ctl, _ := newFakeDeployer(t, plugin.MethodUp)
+
+	var suite util.TestSuite
+	if err := ctl.XMLWrap(&suite, "Up", d.Up); err == nil {
+		t.Fatal("Expected Up to fail")
+	}
+	if err := ctl.XMLWrap(&suite, "Down", d.Down); err != nil {
+		t.Fatalf("Down failed: %v", err)
+	}
+	if suite.Tests != 2 || suite.Failures != 1 {
+		t.Errorf("Expected 2 test cases with 1 failure, got %d with %d failures", suite.Tests, suite.Failures)
+	}
+	if failure := suite.Cases[0].Failure; failure != "fake Up failure" {
+		t.Errorf("Expected the failure of Up to be the error of the fake, got %q", failure)
+	}
+}
+
+func TestDeployerInvalidBinary(t *testing.T) {
+	ctl := process.NewControl(time.Hour, time.NewTimer(time.Hour), time.NewTimer(time.Hour), false)
+	if _, err := plugin.NewDeployer(ctl, ""); err == nil {
+		t.Error("Expected an error for an empty binary")
+	}
+	if _, err := plugin.NewDeployer(ctl, filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected an error for a missing binary")
+	}
+
+	cases := []struct {
+		name   string
+		script string
+		err    string
+	}{
+		{
+			name:   "invalid response",
+			script: "echo not json",
+			err:    "invalid response",
+		},
+		{
+			name:   "other protocol version",
+			script: `echo '{"version": "v0"}'`,
+			err:    `protocol version "v0"`,
+		},
+		{
+			name:   "non-zero exit",
+			script: `echo '{"version": "v1"}'; exit 3`,
+			err:    "exit status 3",
+		},
+		{
+			name:   "non-zero exit without a response",
+			script: "exit 3",
+			err:    "exit status 3",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			binary := filepath.Join(t.TempDir(), "deployer")
+			if err := os.WriteFile(binary, []byte("#!/bin/sh\n"+tc.script+"\n"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			d, err := plugin.NewDeployer(ctl, binary)
+			if err != nil {
+				t.Fatalf("Failed to create deployer: %v", err)
+			}
+			if err := d.Up(); err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("Expected an error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestServe(t *testing.T) {
+	h := fake.NewDeployer(t.TempDir(), "")
+	cases := []struct {
+		name string
+		req  plugin.Request
+		err  string
+	}{
+		{
+			name: "supported version",
+			req:  plugin.Request{Version: plugin.Version, Method: plugin.MethodDown},
+		},
+		{
+			name: "unsupported version",
+			req:  plugin.Request{Version: "v0", Method: plugin.MethodDown},
+			err:  `unsupported protocol version "v0"`,
+		},
+		{
+			name: "unknown method",
+			req:  plugin.Request{Version: plugin.Version, Method: "Upgrade"},
+			err:  `unknown method "Upgrade"`,
+		},
+		{
+			name: "method error",
+			req:  plugin.Request{Version: plugin.Version, Method: plugin.MethodIsUp},
+			err:  "the fake cluster is down",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			in, err := json.Marshal(tc.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			if err := plugin.Serve(h, bytes.NewReader(in), &out); err != nil {
+				t.Fatalf("Serve failed: %v", err)
+			}
+			var resp plugin.Response
+			if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+				t.Fatalf("Serve wrote an invalid response %q: %v", out.String(), err)
+			}
+			if resp.Version != plugin.Version {
+				t.Errorf("Expected a response of version %s, got %s", plugin.Version, resp.Version)
+			}
+			if !strings.Contains(resp.Error, tc.err) || (tc.err == "") != (resp.Error == "") {
+				t.Errorf("Expected error %q, got %q", tc.err, resp.Error)
+			}
+		})
+	}
+
+	if err := plugin.Serve(h, strings.NewReader("not json"), &bytes.Buffer{}); err == nil {
+		t.Error("Expected Serve to fail to read an invalid request")
+	}
+}
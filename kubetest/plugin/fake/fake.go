@@ -0,0 +1,184 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package fake implements a reference out-of-tree deployer, which pretends to bring
+up a cluster by writing a file. It keeps its state in the directory named by
+$FAKE_DEPLOYER_DIR, so that the separate calls of kubetest see the same cluster,
+and fails the method named by $FAKE_DEPLOYER_FAIL, if any.
+*/
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/test-infra/kubetest/plugin"
+)
+
+const (
+	// DirEnv names the directory that the fake deployer keeps its state in.
+	DirEnv = "FAKE_DEPLOYER_DIR"
+	// FailEnv names a method that the fake deployer fails.
+	FailEnv = "FAKE_DEPLOYER_FAIL"
+
+	clusterFile = "cluster.json"
+)
+
+// cluster is the state of the fake cluster.
+type cluster struct {
+	Created   time.Time `json:"created"`
+	TestSetUp bool      `json:"testSetUp"`
+}
+
+// Deployer is a fake deployer that implements plugin.Handler.
+type Deployer struct {
+	dir  string
+	fail string
+}
+
+// NewDeployer creates a fake deployer that keeps its state in dir and fails the method named by fail.
+func NewDeployer(dir, fail string) *Deployer {
+	return &Deployer{dir: dir, fail: fail}
+}
+
+// Main runs the fake deployer as a binary for kubetest --deployment=exec:<binary>.
+func Main() {
+	dir := os.Getenv(DirEnv)
+	if dir == "" {
+		log.Fatalf("$%s is not set", DirEnv)
+	}
+	if err := plugin.Serve(NewDeployer(dir, os.Getenv(FailEnv)), os.Stdin, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+	os.Exit(0)
+}
+
+func (d *Deployer) failed(method string) error {
+	if d.fail == method {
+		return fmt.Errorf("fake %s failure", method)
+	}
+	return nil
+}
+
+func (d *Deployer) load() (*cluster, error) {
+	b, err := os.ReadFile(filepath.Join(d.dir, clusterFile))
+	if os.IsNotExist(err) {
+		return nil, errors.New("the fake cluster is down")
+	} else if err != nil {
+		return nil, err
+	}
+	var c cluster
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (d *Deployer) save(c *cluster) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(d.dir, clusterFile), b, 0644)
+}
+
+// Up creates the fake cluster.
+func (d *Deployer) Up() error {
+	if err := d.failed(plugin.MethodUp); err != nil {
+		return err
+	}
+	log.Printf("Bringing up the fake cluster in %s", d.dir)
+	return d.save(&cluster{Created: time.Now().UTC()})
+}
+
+// IsUp returns an error if the fake cluster does not exist.
+func (d *Deployer) IsUp() error {
+	if err := d.failed(plugin.MethodIsUp); err != nil {
+		return err
+	}
+	_, err := d.load()
+	return err
+}
+
+// DumpClusterLogs writes a log of the fake cluster to localPath.
+func (d *Deployer) DumpClusterLogs(localPath, gcsPath string) error {
+	if err := d.failed(plugin.MethodDumpClusterLogs); err != nil {
+		return err
+	}
+	c, err := d.load()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(localPath, 0755); err != nil {
+		return err
+	}
+	contents := fmt.Sprintf("fake cluster created at %s\n", c.Created.Format(time.RFC3339))
+	return os.WriteFile(filepath.Join(localPath, "fake-cluster.log"), []byte(contents), 0644)
+}
+
+// TestSetup marks the fake cluster as set up for testing.
+func (d *Deployer) TestSetup() error {
+	if err := d.failed(plugin.MethodTestSetup); err != nil {
+		return err
+	}
+	c, err := d.load()
+	if err != nil {
+		return err
+	}
+	c.TestSetUp = true
+	return d.save(c)
+}
+
+// Down deletes the fake cluster.
+func (d *Deployer) Down() error {
+	if err := d.failed(plugin.MethodDown); err != nil {
+		return err
+	}
+	log.Printf("Tearing down the fake cluster in %s", d.dir)
+	if err := os.Remove(filepath.Join(d.dir, clusterFile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// GetClusterCreated returns when the fake cluster was created.
+func (d *Deployer) GetClusterCreated(gcpProject string) (time.Time, error) {
+	if err := d.failed(plugin.MethodGetClusterCreated); err != nil {
+		return time.Time{}, err
+	}
+	c, err := d.load()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return c.Created, nil
+}
+
+// KubectlCommand returns a kubectl command that uses the kubeconfig of the fake cluster.
+func (d *Deployer) KubectlCommand() (*plugin.Command, error) {
+	if err := d.failed(plugin.MethodKubectlCommand); err != nil {
+		return nil, err
+	}
+	return &plugin.Command{
+		Path: "kubectl",
+		Env:  []string{"KUBECONFIG=" + filepath.Join(d.dir, "kubeconfig")},
+	}, nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package plugin implements out-of-tree kubetest deployers, which are selected with
+--deployment=exec:<binary>.
+
+For each call to a method of the deployer, kubetest runs the binary once with the
+name of the method as its argument, writes a Request as JSON to its stdin, and
+reads a Response as JSON from its stdout. The binary must only write the Response
+to stdout; anything it writes to stderr is passed through to the kubetest log. A
+Response with an Error, and a binary that exits with a non-zero status, fail the
+call. Like the calls to built-in deployers, each call is recorded as a junit test
+case by control.XMLWrap.
+
+Requests carry the Version of the protocol. A binary that does not support the
+version must respond with an Error, and kubetest rejects responses of another
+version. Deployers written in Go can implement Handler and call Serve.
+*/
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Version is the version of the protocol between kubetest and deployer binaries.
+const Version = "v1"
+
+// The methods of a deployer, which are the Method of a Request.
+const (
+	MethodUp                = "Up"
+	MethodIsUp              = "IsUp"
+	MethodDumpClusterLogs   = "DumpClusterLogs"
+	MethodTestSetup         = "TestSetup"
+	MethodDown              = "Down"
+	MethodGetClusterCreated = "GetClusterCreated"
+	MethodKubectlCommand    = "KubectlCommand"
+)
+
+// Request is the call of a deployer method, which kubetest writes to the stdin of the binary.
+type Request struct {
+	// Version is the version of the protocol.
+	Version string `json:"version"`
+	// Method is the name of the deployer method to call.
+	Method string `json:"method"`
+	// LocalPath and GCSPath are the arguments of DumpClusterLogs.
+	LocalPath string `json:"localPath,omitempty"`
+	GCSPath   string `json:"gcsPath,omitempty"`
+	// GCPProject is the argument of GetClusterCreated.
+	GCPProject string `json:"gcpProject,omitempty"`
+}
+
+// Response is the result of a deployer method, which the binary writes to its stdout.
+type Response struct {
+	// Version is the version of the protocol.
+	Version string `json:"version"`
+	// Error is the error returned by the method, or "" if it succeeded.
+	Error string `json:"error,omitempty"`
+	// Created is the result of GetClusterCreated.
+	Created *time.Time `json:"created,omitempty"`
+	// Command is the result of KubectlCommand. If it is nil, kubetest uses its default kubectl.
+	Command *Command `json:"command,omitempty"`
+}
+
+// Command is a command to run kubectl with.
+type Command struct {
+	// Path is the path of the kubectl binary.
+	Path string `json:"path"`
+	// Args are the arguments to pass to kubectl before those of each call.
+	Args []string `json:"args,omitempty"`
+	// Env holds the environment variables, in the form "key=value", to set in addition to
+	// those of kubetest.
+	Env []string `json:"env,omitempty"`
+}
+
+// Handler is a deployer written in Go, which Serve calls the methods of.
+type Handler interface {
+	Up() error
+	IsUp() error
+	DumpClusterLogs(localPath, gcsPath string) error
+	TestSetup() error
+	Down() error
+	GetClusterCreated(gcpProject string) (time.Time, error)
+	KubectlCommand() (*Command, error)
+}
+
+// Serve reads a Request from stdin, calls the method of h that it names, and writes the Response to
+// stdout. The error of the method is written to the Response; Serve only returns an error if it fails
+// to read the Request or to write the Response.
+func Serve(h Handler, stdin io.Reader, stdout io.Writer) error {
+	var req Request
+	if err := json.NewDecoder(stdin).Decode(&req); err != nil {
+		return fmt.Errorf("failed to read request: %w", err)
+	}
+
+	resp := Response{Version: Version}
+	var err error
+	switch {
+	case req.Version != Version:
+		err = fmt.Errorf("unsupported protocol version %q, expected %q", req.Version, Version)
+	case req.Method == MethodUp:
+		err = h.Up()
+	case req.Method == MethodIsUp:
+		err = h.IsUp()
+	case req.Method == MethodDumpClusterLogs:
+		err = h.DumpClusterLogs(req.LocalPath, req.GCSPath)
+	case req.Method == MethodTestSetup:
+		err = h.TestSetup()
+	case req.Method == MethodDown:
+		err = h.Down()
+	case req.Method == MethodGetClusterCreated:
+		var created time.Time
+		if created, err = h.GetClusterCreated(req.GCPProject); err == nil {
+			resp.Created = &created
+		}
+	case req.Method == MethodKubectlCommand:
+		resp.Command, err = h.KubectlCommand()
+	default:
+		err = fmt.Errorf("unknown method %q", req.Method)
+	}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+
+	if err := json.NewEncoder(stdout).Encode(resp); err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+	return nil
+}
+
+// responseError returns the error of resp, or nil if the method succeeded.
+func responseError(resp Response) error {
+	if resp.Error == "" {
+		return nil
+	}
+	return errors.New(resp.Error)
+}
@@ -34,6 +34,9 @@ import (
 	"k8s.io/test-infra/kubetest/util"
 )
 
+// DefaultOutputLimit is the default OutputLimit of a Control.
+const DefaultOutputLimit = 10 * 1024
+
 // Control can commands until a timeout is reached, at which point it signals and then terminates them.
 type Control struct {
 	termLock    *sync.RWMutex
@@ -46,6 +49,13 @@ type Control struct {
 	Terminate *time.Timer
 
 	verbose bool
+
+	// OutputLimit bounds the bytes of stdout and of stderr that XMLWrap adds to each test case.
+	// Output is not added if it is zero.
+	OutputLimit int
+
+	// Report records the steps and commands of the run.
+	Report *Report
 }
 
 // NewControl constructs a Control with the specified arguments, instiating other necessary fields.
@@ -59,6 +69,8 @@ func NewControl(timeout time.Duration, interrupt, terminate *time.Timer, verbose
 		Interrupt:   interrupt,
 		Terminate:   terminate,
 		verbose:     verbose,
+		OutputLimit: DefaultOutputLimit,
+		Report:      &Report{},
 	}
 }
 
@@ -96,9 +108,17 @@ func (c *Control) WriteXML(suite *util.TestSuite, dump string, start time.Time)
 	log.Printf("Saved XML output to %s.", path)
 }
 
-// XMLWrap returns f(), adding junit xml testcase result for name
+// XMLWrap returns f(), adding junit xml testcase result for name, with the commands
+// and output of the commands that f ran.
 func (c *Control) XMLWrap(suite *util.TestSuite, name string, f func() error) error {
+	_, err := c.xmlWrap(suite, name, f)
+	return err
+}
+
+// xmlWrap is XMLWrap, which also returns the step recorded in the report.
+func (c *Control) xmlWrap(suite *util.TestSuite, name string, f func() error) (*Step, error) {
 	alreadyInterrupted := c.isInterrupted()
+	step := c.Report.startStep(name)
 	start := time.Now()
 	err := f()
 	duration := time.Since(start)
@@ -119,9 +139,12 @@ func (c *Control) XMLWrap(suite *util.TestSuite, name string, f func() error) er
 		suite.Failures++
 	}
 
+	c.Report.endStep(step, err)
+	tc.Properties, tc.SystemOut, tc.SystemErr = step.junit(c.OutputLimit)
+
 	suite.Cases = append(suite.Cases, tc)
 	suite.Tests++
-	return err
+	return step, err
 }
 
 func (c *Control) isTerminated() bool {
@@ -139,8 +162,16 @@ func (c *Control) isInterrupted() bool {
 }
 
 // FinishRunning returns cmd.Wait() and/or times out.
-func (c *Control) FinishRunning(cmd *exec.Cmd) error {
+func (c *Control) FinishRunning(cmd *exec.Cmd) (err error) {
 	stepName := strings.Join(cmd.Args, " ")
+	var stdout *tailBuffer
+	if c.OutputLimit > 0 {
+		stdout = newTailBuffer(c.OutputLimit)
+	}
+	stderr := newTailBuffer(max(c.OutputLimit, stderrTailBytes))
+	defer func(start time.Time) {
+		c.Report.addCommand(cmd, start, stdout, stderr, err)
+	}(time.Now())
 	if c.isTerminated() {
 		return fmt.Errorf("skipped %s (kubetest is terminated)", stepName)
 	}
@@ -150,13 +181,19 @@ func (c *Control) FinishRunning(cmd *exec.Cmd) error {
 	if cmd.Stderr == nil && c.verbose {
 		cmd.Stderr = os.Stderr
 	}
+	output, err := teeOutput(cmd, stdout, stderr)
+	if err != nil {
+		return fmt.Errorf("error starting %v: %w", stepName, err)
+	}
 	log.Printf("Running: %v", stepName)
 	defer func(start time.Time) {
 		log.Printf("Step '%s' finished in %s", stepName, time.Since(start))
 	}(time.Now())
 
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
-	if err := cmd.Start(); err != nil {
+	err = cmd.Start()
+	output.started()
+	if err != nil {
 		return fmt.Errorf("error starting %v: %w", stepName, err)
 	}
 
@@ -166,7 +203,9 @@ func (c *Control) FinishRunning(cmd *exec.Cmd) error {
 	signal.Notify(sigChannel, os.Interrupt)
 
 	go func() {
-		finished <- cmd.Wait()
+		err := cmd.Wait()
+		output.wait()
+		finished <- err
 	}()
 
 	for {
@@ -236,14 +275,22 @@ func (c *Control) executeParallelCommand(cmd *exec.Cmd, resChan chan cmdExecResu
 	start := time.Now()
 	log.Printf("Running: %v in parallel", stepName)
 
+	// send records the result in the report, where the output stands in for stderr, and sends it.
+	send := func(err error) {
+		output := newTailBuffer(max(c.OutputLimit, stderrTailBytes))
+		output.Write(stdout.Bytes())
+		c.Report.addCommand(cmd, start, nil, output, err)
+		resChan <- cmdExecResult{stepName: stepName, output: stdout.String(), execTime: time.Since(start), err: err}
+	}
+
 	if c.isTerminated() {
-		resChan <- cmdExecResult{stepName: stepName, output: stdout.String(), execTime: time.Since(start), err: fmt.Errorf("skipped %s (kubetest is terminated)", stepName)}
+		send(fmt.Errorf("skipped %s (kubetest is terminated)", stepName))
 		return
 	}
 
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 	if err := cmd.Start(); err != nil {
-		resChan <- cmdExecResult{stepName: stepName, output: stdout.String(), execTime: time.Since(start), err: fmt.Errorf("error starting %v: %w", stepName, err)}
+		send(fmt.Errorf("error starting %v: %w", stepName, err))
 		return
 	}
 
@@ -264,7 +311,7 @@ func (c *Control) executeParallelCommand(cmd *exec.Cmd, resChan chan cmdExecResu
 				}
 				err = fmt.Errorf("error during %s%s: %w", stepName, suffix, err)
 			}
-			resChan <- cmdExecResult{stepName: stepName, output: stdout.String(), execTime: time.Since(start), err: err}
+			send(err)
 			return
 
 		case <-termChan:
@@ -17,9 +17,11 @@ limitations under the License.
 package process
 
 import (
+	"encoding/xml"
 	"errors"
 	"log"
 	"os/exec"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
@@ -367,3 +369,117 @@ func TestOutputOutputs(t *testing.T) {
 		t.Errorf("output() did not echo hello world: %v", txt)
 	}
 }
+
+func TestXMLWrapCommands(t *testing.T) {
+	cases := []struct {
+		name        string
+		limit       int
+		scripts     []string
+		properties  *util.Properties
+		systemOut   string
+		systemErr   string
+		expectError bool
+	}{
+		{
+			name:  "no commands",
+			limit: 100,
+		},
+		{
+			name:    "single command",
+			limit:   100,
+			scripts: []string{"echo out; echo err >&2"},
+			properties: &util.Properties{Properties: []util.Property{
+				{Name: "command", Value: "sh -c echo out; echo err >&2"},
+				{Name: "exit-code", Value: "0"},
+			}},
+			systemOut: "out\n",
+			systemErr: "err\n",
+		},
+		{
+			name:    "retried command",
+			limit:   100,
+			scripts: []string{"echo first", "exit 4", "exit 4"},
+			properties: &util.Properties{Properties: []util.Property{
+				{Name: "command", Value: "sh -c exit 4"},
+				{Name: "exit-code", Value: "4"},
+				{Name: "retries", Value: "1"},
+			}},
+			systemOut:   "$ sh -c echo first\nfirst\n",
+			expectError: true,
+		},
+		{
+			name:    "output over the limit",
+			limit:   4,
+			scripts: []string{"echo hello world"},
+			properties: &util.Properties{Properties: []util.Property{
+				{Name: "command", Value: "sh -c echo hello world"},
+				{Name: "exit-code", Value: "0"},
+			}},
+			systemOut: "...\nrld\n",
+		},
+		{
+			name:    "output disabled",
+			scripts: []string{"echo out; echo err >&2"},
+			properties: &util.Properties{Properties: []util.Property{
+				{Name: "command", Value: "sh -c echo out; echo err >&2"},
+				{Name: "exit-code", Value: "0"},
+			}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewControl(time.Hour, time.NewTimer(time.Hour), time.NewTimer(time.Hour), false)
+			c.OutputLimit = tc.limit
+			var suite util.TestSuite
+			err := c.XMLWrap(&suite, "step", func() error {
+				var err error
+				for _, script := range tc.scripts {
+					err = c.FinishRunning(exec.Command("sh", "-c", script))
+				}
+				return err
+			})
+			if (err != nil) != tc.expectError {
+				t.Errorf("expected error %t, got %v", tc.expectError, err)
+			}
+			sc := suite.Cases[0]
+			if !reflect.DeepEqual(sc.Properties, tc.properties) {
+				t.Errorf("expected properties %v, got %v", tc.properties, sc.Properties)
+			}
+			if sc.SystemOut != tc.systemOut {
+				t.Errorf("expected system-out %q, got %q", tc.systemOut, sc.SystemOut)
+			}
+			if sc.SystemErr != tc.systemErr {
+				t.Errorf("expected system-err %q, got %q", tc.systemErr, sc.SystemErr)
+			}
+		})
+	}
+}
+
+func TestTestCaseXML(t *testing.T) {
+	tc := util.TestCase{
+		ClassName:  "e2e.go",
+		Name:       "Up",
+		Properties: &util.Properties{Properties: []util.Property{{Name: "exit-code", Value: "1"}}},
+		Failure:    "failed",
+		SystemOut:  "out",
+		SystemErr:  "err",
+	}
+	b, err := xml.Marshal(tc)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	expected := `<testcase classname="e2e.go" name="Up" time="0"><properties><property name="exit-code" value="1"></property></properties>` +
+		`<failure>failed</failure><system-out>out</system-out><system-err>err</system-err></testcase>`
+	if string(b) != expected {
+		t.Errorf("expected %s, got %s", expected, b)
+	}
+
+	b, err = xml.Marshal(util.TestCase{Name: "Up"})
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if expected := `<testcase classname="" name="Up" time="0"></testcase>`; string(b) != expected {
+		t.Errorf("expected %s, got %s", expected, b)
+	}
+}
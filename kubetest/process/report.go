@@ -0,0 +1,472 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/test-infra/kubetest/util"
+)
+
+// ReportFile is the name of the report that WriteReport creates inside the dump dir.
+const ReportFile = "kubetest-report.json"
+
+// stderrTailBytes bounds the stderr of each command kept in the report.
+const stderrTailBytes = 4096
+
+// outputGrace bounds how long FinishRunning waits, once a command exited, for the rest of the
+// output that its children may still be writing.
+var outputGrace = time.Second
+
+// The phases of a kubetest run, which group the steps of the report.
+const (
+	PhasePrepare = "prepare"
+	PhaseExtract = "extract"
+	PhaseBuild   = "build"
+	PhaseUp      = "up"
+	PhaseTest    = "test"
+	PhaseDump    = "dump"
+	PhaseDown    = "down"
+	PhaseOther   = "other"
+)
+
+// stepPhases maps the names of the steps that kubetest wraps with XMLWrap to their phase.
+var stepPhases = map[string]string{
+	"Prepare":                    PhasePrepare,
+	"GetDeployer":                PhasePrepare,
+	"Extract":                    PhaseExtract,
+	"Build":                      PhaseBuild,
+	"Stage":                      PhaseBuild,
+	"Up":                         PhaseUp,
+	"Kubemark Up":                PhaseUp,
+	"Check APIReachability":      PhaseUp,
+	"list nodes":                 PhaseUp,
+	"list kubemark nodes":        PhaseUp,
+	"IsUp":                       PhaseUp,
+	"test setup":                 PhaseTest,
+	"pre-test command":           PhaseTest,
+	"kubectl version":            PhaseTest,
+	"Test":                       PhaseTest,
+	"Node Tests":                 PhaseTest,
+	"SkewTest":                   PhaseTest,
+	"UpgradeTest":                PhaseTest,
+	"Kubemark Overall":           PhaseTest,
+	"Kubemark Test":              PhaseTest,
+	"Helm Charts":                PhaseTest,
+	"Deferred post-test command": PhaseTest,
+	"Kubemark MasterLogDump":     PhaseDump,
+	"TearDown Previous":          PhaseDown,
+	"Kubemark TearDown Previous": PhaseDown,
+	"TearDown":                   PhaseDown,
+	"Kubemark TearDown":          PhaseDown,
+	"Deferred TearDown":          PhaseDown,
+	"Down":                       PhaseDown,
+}
+
+// Report is a structured record of a kubetest run, which lets tools diagnose failures
+// without scraping the build log.
+type Report struct {
+	// Deployer is the value of --deployment.
+	Deployer string `json:"deployer,omitempty"`
+	// ClusterVersion is the output of kubectl version for the cluster under test.
+	ClusterVersion string `json:"clusterVersion,omitempty"`
+	// BoskosResources are the resources leased from boskos.
+	BoskosResources []BoskosResource `json:"boskosResources,omitempty"`
+	Start           time.Time        `json:"start"`
+	End             time.Time        `json:"end"`
+	// Interrupted is true if --timeout was reached.
+	Interrupted bool     `json:"interrupted"`
+	Phases      []*Phase `json:"phases"`
+	// Commands are the commands that were run outside of any step.
+	Commands []*Command `json:"commands,omitempty"`
+
+	lock       sync.Mutex
+	active     []*Step
+	stepPhases map[string]string
+}
+
+// BoskosResource is a resource leased from boskos.
+type BoskosResource struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// Phase groups the steps of one part of the run, such as bringing up the cluster.
+type Phase struct {
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Failed is true if any of the steps failed.
+	Failed bool    `json:"failed"`
+	Steps  []*Step `json:"steps"`
+}
+
+// Step is a step wrapped with XMLWrap, which is also a junit test case.
+type Step struct {
+	Name     string     `json:"name"`
+	Start    time.Time  `json:"start"`
+	End      time.Time  `json:"end"`
+	Error    string     `json:"error,omitempty"`
+	Commands []*Command `json:"commands,omitempty"`
+}
+
+// Command is a command run by the Control.
+type Command struct {
+	Args  []string  `json:"args"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// ExitCode is the exit code of the command, or -1 if it did not start or was killed.
+	ExitCode int    `json:"exitCode"`
+	Error    string `json:"error,omitempty"`
+	// StderrTail is the end of the stderr of the command.
+	StderrTail string `json:"stderrTail,omitempty"`
+
+	// stdout and stderr are the output kept for the junit test case of the step.
+	stdout, stderr string
+}
+
+// SetStepPhase records the steps named name under phase, for steps that kubetest does not know of,
+// such as --test-cmd-name.
+func (r *Report) SetStepPhase(name, phase string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.stepPhases == nil {
+		r.stepPhases = map[string]string{}
+	}
+	r.stepPhases[name] = phase
+}
+
+// AddBoskosResource records a resource leased from boskos.
+func (r *Report) AddBoskosResource(resourceType, name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.BoskosResources = append(r.BoskosResources, BoskosResource{Type: resourceType, Name: name})
+}
+
+// stepPhase returns the phase of the step named name.
+func (r *Report) stepPhase(name string) string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.phaseOf(name)
+}
+
+// phaseOf returns the phase of the step named name, with the lock held.
+func (r *Report) phaseOf(name string) string {
+	if phase, ok := r.stepPhases[name]; ok {
+		return phase
+	}
+	if phase, ok := stepPhases[name]; ok {
+		return phase
+	}
+	if strings.Contains(name, "DumpClusterLogs") {
+		return PhaseDump
+	}
+	return PhaseOther
+}
+
+// startStep records the start of a step; the commands run until endStep belong to it.
+func (r *Report) startStep(name string) *Step {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	s := &Step{Name: name, Start: time.Now()}
+	r.active = append(r.active, s)
+	return s
+}
+
+// endStep records the end of s, adding it to its phase.
+func (r *Report) endStep(s *Step, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	s.End = time.Now()
+	if err != nil {
+		s.Error = err.Error()
+	}
+	for i := len(r.active) - 1; i >= 0; i-- {
+		if r.active[i] == s {
+			r.active = append(r.active[:i], r.active[i+1:]...)
+			break
+		}
+	}
+
+	name := r.phaseOf(s.Name)
+	var phase *Phase
+	for _, p := range r.Phases {
+		if p.Name == name {
+			phase = p
+			break
+		}
+	}
+	if phase == nil {
+		phase = &Phase{Name: name, Start: s.Start}
+		r.Phases = append(r.Phases, phase)
+	}
+	if s.Start.Before(phase.Start) {
+		phase.Start = s.Start
+	}
+	if s.End.After(phase.End) {
+		phase.End = s.End
+	}
+	phase.Failed = phase.Failed || err != nil
+	phase.Steps = append(phase.Steps, s)
+}
+
+// addCommand records a command that finished with err, adding it to the innermost active step.
+// Either of stdout and stderr may be nil if the output was not kept.
+func (r *Report) addCommand(cmd *exec.Cmd, start time.Time, stdout, stderr *tailBuffer, err error) {
+	c := &Command{
+		Args:     cmd.Args,
+		Start:    start,
+		End:      time.Now(),
+		ExitCode: -1,
+	}
+	if cmd.ProcessState != nil {
+		c.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	if err != nil {
+		c.Error = err.Error()
+	}
+	if stdout != nil {
+		c.stdout = stdout.String()
+	}
+	if stderr != nil {
+		c.StderrTail = stderr.tail(stderrTailBytes)
+		c.stderr = stderr.String()
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if n := len(r.active); n > 0 {
+		r.active[n-1].Commands = append(r.active[n-1].Commands, c)
+	} else {
+		r.Commands = append(r.Commands, c)
+	}
+}
+
+// junit returns the properties and output of the junit test case of s, keeping at most the last
+// limit bytes of each output. The properties describe the last command, which decided the result
+// of most steps, and how many commands were retries of an earlier one.
+func (s *Step) junit(limit int) (properties *util.Properties, systemOut, systemErr string) {
+	if len(s.Commands) == 0 {
+		return nil, "", ""
+	}
+	last := s.Commands[len(s.Commands)-1]
+	properties = &util.Properties{Properties: []util.Property{
+		{Name: "command", Value: strings.Join(last.Args, " ")},
+		{Name: "exit-code", Value: strconv.Itoa(last.ExitCode)},
+	}}
+	seen := map[string]bool{}
+	retries := 0
+	for _, cmd := range s.Commands {
+		key := strings.Join(cmd.Args, "\x00")
+		if seen[key] {
+			retries++
+		}
+		seen[key] = true
+	}
+	if retries > 0 {
+		properties.Properties = append(properties.Properties, util.Property{Name: "retries", Value: strconv.Itoa(retries)})
+	}
+	if limit <= 0 {
+		return properties, "", ""
+	}
+
+	// Name the command of each output when there is more than one.
+	stdout, stderr := newTailBuffer(limit), newTailBuffer(limit)
+	for _, cmd := range s.Commands {
+		header := "$ " + strings.Join(cmd.Args, " ") + "\n"
+		for _, o := range []struct {
+			buf    *tailBuffer
+			output string
+		}{{stdout, cmd.stdout}, {stderr, cmd.stderr}} {
+			if o.output == "" {
+				continue
+			}
+			if len(s.Commands) > 1 {
+				o.buf.Write([]byte(header))
+			}
+			o.buf.Write([]byte(o.output))
+		}
+	}
+	return properties, stdout.String(), stderr.String()
+}
+
+// WriteReport creates the kubetest-report.json file inside the dump dir.
+func (c *Control) WriteReport(dump string, start time.Time) {
+	r := c.Report
+	r.lock.Lock()
+	r.Start = start
+	r.End = time.Now()
+	r.Interrupted = c.isInterrupted()
+	out, err := json.MarshalIndent(r, "", "  ")
+	r.lock.Unlock()
+	if err != nil {
+		log.Printf("Could not marshal the report: %v", err)
+		return
+	}
+	path := filepath.Join(dump, ReportFile)
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		log.Printf("Could not write the report: %v", err)
+		return
+	}
+	log.Printf("Saved the report to %s.", path)
+}
+
+// tailBuffer is an io.Writer that keeps the last max bytes written to it. It may be written to
+// and read from concurrently.
+type tailBuffer struct {
+	lock      sync.Mutex
+	max       int
+	buf       []byte
+	truncated bool
+}
+
+func newTailBuffer(max int) *tailBuffer {
+	return &tailBuffer{max: max}
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.max; over > 0 {
+		b.buf = append(b.buf[:0], b.buf[over:]...)
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+// String returns the bytes kept, marking whether earlier ones were dropped.
+func (b *tailBuffer) String() string {
+	return b.tail(b.max)
+}
+
+// tail returns at most the last n bytes kept, marking whether earlier ones were dropped.
+func (b *tailBuffer) tail(n int) string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if len(b.buf) > n {
+		return "...\n" + string(b.buf[len(b.buf)-n:])
+	}
+	if b.truncated {
+		return "...\n" + string(b.buf)
+	}
+	return string(b.buf)
+}
+
+// outputTee copies the output of a command to buffers as well as to where it was going.
+type outputTee struct {
+	// writers are the ends of the pipes that the command writes to.
+	writers []*os.File
+	// copied are closed once everything written to the pipes was copied.
+	copied []chan struct{}
+}
+
+// teeOutput copies the stdout and stderr of cmd to the given buffers as well; stdout may be nil to
+// leave stdout alone. If stdout and stderr are the same writer, they stay the same so that their
+// output stays in order, and the output is copied to stderr. Call started once cmd started, or
+// failed to, and wait once it exited.
+func teeOutput(cmd *exec.Cmd, stdout, stderr *tailBuffer) (*outputTee, error) {
+	t := &outputTee{}
+	var err error
+	if cmd.Stderr != nil && sameWriter(cmd.Stdout, cmd.Stderr) {
+		cmd.Stderr, err = t.tee(cmd.Stderr, stderr)
+		cmd.Stdout = cmd.Stderr
+	} else {
+		cmd.Stderr, err = t.tee(cmd.Stderr, stderr)
+		if err == nil && stdout != nil {
+			cmd.Stdout, err = t.tee(cmd.Stdout, stdout)
+		}
+	}
+	if err != nil {
+		t.started()
+		return nil, err
+	}
+	return t, nil
+}
+
+// tee returns the writer for cmd that writes to both w and buf. If w is nil or a file, that is a
+// pipe that the command writes to directly, like it would to w, rather than one that os/exec
+// creates and waits for. Children of the command that keep writing to it after the command
+// exited are not cut off then; the pipe is drained for as long as they write.
+func (t *outputTee) tee(w io.Writer, buf *tailBuffer) (io.Writer, error) {
+	f, ok := w.(*os.File)
+	if w != nil && !ok {
+		return tee(w, buf), nil
+	}
+	r, pw, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a pipe for the output: %w", err)
+	}
+	copied := make(chan struct{})
+	go func() {
+		defer close(copied)
+		defer r.Close()
+		if f != nil {
+			io.Copy(io.MultiWriter(f, buf), r)
+		} else {
+			io.Copy(buf, r)
+		}
+	}()
+	t.writers = append(t.writers, pw)
+	t.copied = append(t.copied, copied)
+	return pw, nil
+}
+
+// started closes the ends of the pipes that the command, now started, holds.
+func (t *outputTee) started() {
+	for _, w := range t.writers {
+		w.Close()
+	}
+}
+
+// wait waits until the output of the exited command is copied, unless its children keep the
+// pipes open for longer than outputGrace.
+func (t *outputTee) wait() {
+	grace := time.NewTimer(outputGrace)
+	defer grace.Stop()
+	for _, copied := range t.copied {
+		select {
+		case <-copied:
+		case <-grace.C:
+			return
+		}
+	}
+}
+
+// tee returns a writer that writes to both w, if it is not nil, and buf.
+func tee(w io.Writer, buf *tailBuffer) io.Writer {
+	if w == nil {
+		return buf
+	}
+	return io.MultiWriter(w, buf)
+}
+
+// sameWriter returns a == b, or false if they cannot be compared.
+func sameWriter(a, b io.Writer) (same bool) {
+	defer func() { recover() }()
+	return a == b
+}
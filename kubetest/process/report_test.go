@@ -0,0 +1,228 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/test-infra/kubetest/util"
+)
+
+func TestReport(t *testing.T) {
+	c := NewControl(time.Hour, time.NewTimer(time.Hour), time.NewTimer(time.Hour), false)
+	c.Report.Deployer = "fake"
+	c.Report.SetStepPhase("e2e.sh", PhaseTest)
+	c.Report.AddBoskosResource("gce-project", "project-1")
+
+	var suite util.TestSuite
+	run := func(script string) func() error {
+		return func() error { return c.FinishRunning(exec.Command("sh", "-c", script)) }
+	}
+	c.XMLWrap(&suite, "Prepare", run("true"))
+	c.XMLWrap(&suite, "TearDown Previous", run("true"))
+	c.XMLWrap(&suite, "Up", run("echo quota exceeded >&2; exit 3"))
+	c.XMLWrap(&suite, "DumpClusterLogs (--up failed)", run("true"))
+	c.XMLWrap(&suite, "Kubemark Overall", func() error {
+		return c.XMLWrap(&suite, "Kubemark Up", run("true"))
+	})
+	c.XMLWrap(&suite, "e2e.sh", run("true"))
+	c.XMLWrap(&suite, "diffResources", func() error { return nil })
+	c.XMLWrap(&suite, "Deferred TearDown", run("true"))
+	c.FinishRunning(exec.Command("true"))
+
+	dump := t.TempDir()
+	start := time.Now().Add(-time.Minute)
+	c.WriteReport(dump, start)
+	b, err := os.ReadFile(filepath.Join(dump, ReportFile))
+	if err != nil {
+		t.Fatalf("Failed to read the report: %v", err)
+	}
+	var r Report
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatalf("Failed to parse the report: %v", err)
+	}
+
+	if r.Deployer != "fake" {
+		t.Errorf("Expected deployer fake, got %q", r.Deployer)
+	}
+	if expected := []BoskosResource{{Type: "gce-project", Name: "project-1"}}; !reflect.DeepEqual(r.BoskosResources, expected) {
+		t.Errorf("Expected boskos resources %v, got %v", expected, r.BoskosResources)
+	}
+	if !r.Start.Equal(start) || r.End.Before(r.Start) {
+		t.Errorf("Expected the report to start at %v and end after it, got %v to %v", start, r.Start, r.End)
+	}
+
+	phases := map[string][]string{}
+	var order []string
+	for _, p := range r.Phases {
+		order = append(order, p.Name)
+		for _, s := range p.Steps {
+			phases[p.Name] = append(phases[p.Name], s.Name)
+			if s.Start.Before(p.Start) || s.End.After(p.End) {
+				t.Errorf("Step %s from %v to %v is outside of phase %s from %v to %v", s.Name, s.Start, s.End, p.Name, p.Start, p.End)
+			}
+		}
+		if p.Failed != (p.Name == PhaseUp) {
+			t.Errorf("Expected only phase %s to fail, got failed=%t for phase %s", PhaseUp, p.Failed, p.Name)
+		}
+	}
+	if expected := []string{PhasePrepare, PhaseDown, PhaseUp, PhaseDump, PhaseTest, PhaseOther}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("Expected phases %v, got %v", expected, order)
+	}
+	expected := map[string][]string{
+		PhasePrepare: {"Prepare"},
+		PhaseDown:    {"TearDown Previous", "Deferred TearDown"},
+		PhaseUp:      {"Up", "Kubemark Up"},
+		PhaseDump:    {"DumpClusterLogs (--up failed)"},
+		PhaseTest:    {"Kubemark Overall", "e2e.sh"},
+		PhaseOther:   {"diffResources"},
+	}
+	if !reflect.DeepEqual(phases, expected) {
+		t.Errorf("Expected steps %v, got %v", expected, phases)
+	}
+
+	up := r.Phases[2].Steps[0]
+	if len(up.Commands) != 1 {
+		t.Fatalf("Expected step Up to run 1 command, got %d", len(up.Commands))
+	}
+	if cmd := up.Commands[0]; cmd.ExitCode != 3 || cmd.StderrTail != "quota exceeded\n" || cmd.Error == "" {
+		t.Errorf("Expected the command of step Up to exit with 3, an error and its stderr, got %+v", cmd)
+	}
+	if up.Error == "" {
+		t.Error("Expected step Up to record its error")
+	}
+	if overall := r.Phases[4].Steps[0]; len(overall.Commands) != 0 {
+		t.Errorf("Expected the commands of nested steps to belong to the innermost step, got %d in Kubemark Overall", len(overall.Commands))
+	}
+	if len(r.Commands) != 1 || r.Commands[0].ExitCode != 0 {
+		t.Errorf("Expected 1 successful command outside of steps, got %+v", r.Commands)
+	}
+}
+
+func TestReportFinishRunningParallel(t *testing.T) {
+	c := NewControl(time.Hour, time.NewTimer(time.Hour), time.NewTimer(time.Hour), false)
+	var suite util.TestSuite
+	c.XMLWrap(&suite, "Test", func() error {
+		return c.FinishRunningParallel(exec.Command("true"), exec.Command("sh", "-c", "echo failed; exit 2"))
+	})
+
+	if len(c.Report.Phases) != 1 || len(c.Report.Phases[0].Steps) != 1 {
+		t.Fatalf("Expected a single step, got %+v", c.Report.Phases)
+	}
+	codes := map[int]string{}
+	for _, cmd := range c.Report.Phases[0].Steps[0].Commands {
+		codes[cmd.ExitCode] = cmd.StderrTail
+	}
+	if expected := map[int]string{0: "", 2: "failed\n"}; !reflect.DeepEqual(codes, expected) {
+		t.Errorf("Expected exit codes and output %v, got %v", expected, codes)
+	}
+}
+
+func TestReportKeepsOutputs(t *testing.T) {
+	c := NewControl(time.Hour, time.NewTimer(time.Hour), time.NewTimer(time.Hour), false)
+
+	var combined bytes.Buffer
+	cmd := exec.Command("sh", "-c", "echo out; echo err >&2")
+	cmd.Stdout = &combined
+	cmd.Stderr = &combined
+	if err := c.FinishRunning(cmd); err != nil {
+		t.Fatalf("Failed to run: %v", err)
+	}
+	if out := combined.String(); out != "out\nerr\n" {
+		t.Errorf("Expected the combined output of the command, got %q", out)
+	}
+
+	var stderr bytes.Buffer
+	cmd = exec.Command("sh", "-c", "echo out; echo err >&2")
+	cmd.Stderr = &stderr
+	out, err := c.Output(cmd)
+	if err != nil {
+		t.Fatalf("Failed to run: %v", err)
+	}
+	if string(out) != "out\n" || stderr.String() != "err\n" {
+		t.Errorf("Expected stdout %q and stderr %q, got %q and %q", "out\n", "err\n", out, stderr.String())
+	}
+	if tail := c.Report.Commands[1].StderrTail; tail != "err\n" {
+		t.Errorf("Expected the report to keep stderr %q, got %q", "err\n", tail)
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	b := newTailBuffer(8)
+	b.Write([]byte("hello"))
+	if s := b.String(); s != "hello" {
+		t.Errorf("Expected hello, got %q", s)
+	}
+	b.Write([]byte(" world"))
+	if s := b.String(); s != "...\nlo world" {
+		t.Errorf("Expected the last 8 bytes, got %q", s)
+	}
+	b.Write([]byte(strings.Repeat("x", 20)))
+	if s := b.String(); s != "...\n"+strings.Repeat("x", 8) {
+		t.Errorf("Expected the last 8 bytes, got %q", s)
+	}
+}
+
+func TestFinishRunningBackgroundChild(t *testing.T) {
+	c := NewControl(time.Hour, time.NewTimer(time.Hour), time.NewTimer(time.Hour), true)
+	c.OutputLimit = 0
+	done := filepath.Join(t.TempDir(), "done")
+	start := time.Now()
+	// The child keeps writing to stderr after the command exits.
+	script := "echo started >&2; (sleep 2; echo child >&2 && touch " + done + ") & exit 0"
+	if err := c.FinishRunning(exec.Command("sh", "-c", script)); err != nil {
+		t.Errorf("expected a command that exits 0 to pass while its child runs, got %v", err)
+	}
+	if d := time.Since(start); d > outputGrace+time.Second {
+		t.Errorf("expected FinishRunning not to wait for the child, took %s", d)
+	}
+	if cmd := c.Report.Commands[0]; cmd.ExitCode != 0 || cmd.StderrTail != "started\n" {
+		t.Errorf("expected the command to exit with 0 and keep its stderr, got %+v", cmd)
+	}
+	for deadline := time.Now().Add(10 * time.Second); ; time.Sleep(100 * time.Millisecond) {
+		if _, err := os.Stat(done); err == nil {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatal("expected the child to keep writing to stderr after the command exited")
+		}
+	}
+
+	// The same goes for stdout, when it is kept for the junit test case.
+	c.OutputLimit = DefaultOutputLimit
+	start = time.Now()
+	if err := c.FinishRunning(exec.Command("sh", "-c", "echo out; sleep 2 & exit 0")); err != nil {
+		t.Errorf("expected a command that exits 0 to pass while its child holds stdout, got %v", err)
+	}
+	if d := time.Since(start); d > outputGrace+time.Second {
+		t.Errorf("expected FinishRunning not to wait for the child, took %s", d)
+	}
+	if out := c.Report.Commands[1].stdout; out != "out\n" {
+		t.Errorf("expected to keep the stdout of the command, got %q", out)
+	}
+
+	if err := c.FinishRunning(exec.Command("sh", "-c", "sleep 2 & exit 2")); err == nil || !strings.Contains(err.Error(), "exit status 2") {
+		t.Errorf("expected the exit status of a failing command with a child, got %v", err)
+	}
+}
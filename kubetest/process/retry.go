@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/test-infra/kubetest/util"
+)
+
+// Classifier decides whether a failed step is worth retrying.
+type Classifier interface {
+	// Retriable returns true if err, given the stderr of the commands that the step ran, is
+	// transient, such as a quota or API error.
+	Retriable(err error, stderr string) bool
+}
+
+// ClassifierFunc is a function that implements Classifier.
+type ClassifierFunc func(err error, stderr string) bool
+
+// Retriable returns f(err, stderr).
+func (f ClassifierFunc) Retriable(err error, stderr string) bool {
+	return f(err, stderr)
+}
+
+// PatternClassifier retries a step if its error or stderr matches any of the patterns.
+type PatternClassifier []*regexp.Regexp
+
+// NewPatternClassifier compiles patterns into a PatternClassifier, panicking if one is invalid.
+func NewPatternClassifier(patterns ...string) PatternClassifier {
+	var p PatternClassifier
+	for _, pattern := range patterns {
+		p = append(p, regexp.MustCompile(pattern))
+	}
+	return p
+}
+
+// Retriable returns true if err or stderr matches any of the patterns.
+func (p PatternClassifier) Retriable(err error, stderr string) bool {
+	for _, re := range p {
+		if re.MatchString(err.Error()) || re.MatchString(stderr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Retry is how XMLWrapRetry retries a failed step.
+type Retry struct {
+	// Retries is the number of times to retry the step after it first fails.
+	Retries int
+	// Backoff is the time to wait before the first retry, which doubles for each later retry.
+	Backoff time.Duration
+	// Classifier decides which failures to retry. All of them are retried if it is nil.
+	Classifier Classifier
+	// Cleanup, if set, is called between attempts, such as to tear down a partial cluster.
+	Cleanup func() error
+}
+
+// XMLWrapRetry returns f() like XMLWrap, retrying it as long as retry allows. Each attempt, and
+// each cleanup between attempts, is added as a test case of its own within the test case for
+// name, so that failures which a retry recovers from remain visible.
+func (c *Control) XMLWrapRetry(suite *util.TestSuite, name string, retry Retry, f func() error) error {
+	if retry.Retries <= 0 {
+		return c.XMLWrap(suite, name, f)
+	}
+	phase := c.Report.stepPhase(name)
+	return c.XMLWrap(suite, name, func() error {
+		backoff := retry.Backoff
+		for attempt := 1; ; attempt++ {
+			attemptName := fmt.Sprintf("%s attempt %d", name, attempt)
+			c.Report.SetStepPhase(attemptName, phase)
+			step, err := c.xmlWrap(suite, attemptName, f)
+			if err == nil {
+				return nil
+			}
+			if attempt > retry.Retries {
+				return fmt.Errorf("%s failed after %d attempts: %w", name, attempt, err)
+			}
+			if c.isInterrupted() {
+				return err
+			}
+			if retry.Classifier != nil && !retry.Classifier.Retriable(err, step.stderr()) {
+				log.Printf("Not retrying %s, the error is not retriable: %v", name, err)
+				return err
+			}
+
+			if retry.Cleanup != nil {
+				cleanupName := "TearDown after " + attemptName
+				c.Report.SetStepPhase(cleanupName, PhaseDown)
+				if cerr := c.XMLWrap(suite, cleanupName, retry.Cleanup); cerr != nil {
+					return fmt.Errorf("%w (not retrying, cleanup failed: %v)", err, cerr)
+				}
+			}
+			log.Printf("Retrying %s in %s (attempt %d of %d) after: %v", name, backoff, attempt+1, retry.Retries+1, err)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	})
+}
+
+// stderr returns the stderr of the commands that s ran.
+func (s *Step) stderr() string {
+	var b strings.Builder
+	for _, cmd := range s.Commands {
+		if cmd.stderr != "" {
+			b.WriteString(cmd.stderr)
+		} else {
+			b.WriteString(cmd.StderrTail)
+		}
+	}
+	return b.String()
+}
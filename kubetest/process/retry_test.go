@@ -0,0 +1,189 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package process
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/test-infra/kubetest/util"
+)
+
+func TestXMLWrapRetry(t *testing.T) {
+	quota := "echo \"Quota 'CPUS' exceeded\" >&2; exit 1"
+	cases := []struct {
+		name       string
+		retries    int
+		classifier Classifier
+		cleanupErr error
+		// scripts are run by the attempts in turn, and the last one by any later attempts.
+		scripts  []string
+		cases    []string
+		failures int
+		cleanups int
+		err      string
+	}{
+		{
+			name:     "no retries",
+			scripts:  []string{quota},
+			cases:    []string{"Up"},
+			failures: 1,
+			err:      "exit status 1",
+		},
+		{
+			name:    "pass without retrying",
+			retries: 2,
+			scripts: []string{"true"},
+			cases:   []string{"Up attempt 1", "Up"},
+		},
+		{
+			name:     "pass after retrying",
+			retries:  2,
+			scripts:  []string{quota, "true"},
+			cases:    []string{"Up attempt 1", "TearDown after Up attempt 1", "Up attempt 2", "Up"},
+			failures: 1,
+			cleanups: 1,
+		},
+		{
+			name:     "retries run out",
+			retries:  1,
+			scripts:  []string{quota},
+			cases:    []string{"Up attempt 1", "TearDown after Up attempt 1", "Up attempt 2", "Up"},
+			failures: 3,
+			cleanups: 1,
+			err:      "Up failed after 2 attempts",
+		},
+		{
+			name:       "retry the stderr that the classifier matches",
+			retries:    1,
+			classifier: NewPatternClassifier(`Quota '\w+' exceeded`),
+			scripts:    []string{quota, "true"},
+			cases:      []string{"Up attempt 1", "TearDown after Up attempt 1", "Up attempt 2", "Up"},
+			failures:   1,
+			cleanups:   1,
+		},
+		{
+			name:       "do not retry errors that the classifier does not match",
+			retries:    1,
+			classifier: NewPatternClassifier(`RequestLimitExceeded`),
+			scripts:    []string{quota, "true"},
+			cases:      []string{"Up attempt 1", "Up"},
+			failures:   2,
+			err:        "exit status 1",
+		},
+		{
+			name:       "do not retry when cleanup fails",
+			retries:    1,
+			cleanupErr: errors.New("cluster is stuck"),
+			scripts:    []string{quota, "true"},
+			cases:      []string{"Up attempt 1", "TearDown after Up attempt 1", "Up"},
+			failures:   3,
+			cleanups:   1,
+			err:        "cleanup failed: cluster is stuck",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewControl(time.Hour, time.NewTimer(time.Hour), time.NewTimer(time.Hour), false)
+			var suite util.TestSuite
+			attempts, cleanups := 0, 0
+			retry := Retry{
+				Retries:    tc.retries,
+				Backoff:    time.Millisecond,
+				Classifier: tc.classifier,
+				Cleanup: func() error {
+					cleanups++
+					return tc.cleanupErr
+				},
+			}
+			err := c.XMLWrapRetry(&suite, "Up", retry, func() error {
+				script := tc.scripts[min(attempts, len(tc.scripts)-1)]
+				attempts++
+				return c.FinishRunning(exec.Command("sh", "-c", script))
+			})
+
+			if tc.err == "" && err != nil || tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+				t.Errorf("expected error %q, got %v", tc.err, err)
+			}
+			var names []string
+			for _, sc := range suite.Cases {
+				names = append(names, sc.Name)
+			}
+			if !reflect.DeepEqual(names, tc.cases) {
+				t.Errorf("expected test cases %v, got %v", tc.cases, names)
+			}
+			if suite.Failures != tc.failures {
+				t.Errorf("expected %d failures, got %d", tc.failures, suite.Failures)
+			}
+			if cleanups != tc.cleanups {
+				t.Errorf("expected %d cleanups, got %d", tc.cleanups, cleanups)
+			}
+		})
+	}
+}
+
+func TestXMLWrapRetryReport(t *testing.T) {
+	c := NewControl(time.Hour, time.NewTimer(time.Hour), time.NewTimer(time.Hour), false)
+	var suite util.TestSuite
+	attempts := 0
+	retry := Retry{Retries: 1, Cleanup: func() error { return nil }}
+	c.XMLWrapRetry(&suite, "Up", retry, func() error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+
+	phases := map[string][]string{}
+	for _, p := range c.Report.Phases {
+		for _, s := range p.Steps {
+			phases[p.Name] = append(phases[p.Name], s.Name)
+		}
+	}
+	expected := map[string][]string{
+		PhaseUp:   {"Up attempt 1", "Up attempt 2", "Up"},
+		PhaseDown: {"TearDown after Up attempt 1"},
+	}
+	if !reflect.DeepEqual(phases, expected) {
+		t.Errorf("expected steps %v, got %v", expected, phases)
+	}
+}
+
+func TestPatternClassifier(t *testing.T) {
+	p := NewPatternClassifier(`QUOTA_EXCEEDED`, `(?i)rate limit`)
+	cases := []struct {
+		err       error
+		stderr    string
+		retriable bool
+	}{
+		{err: errors.New("exit status 1"), stderr: "ERROR: QUOTA_EXCEEDED", retriable: true},
+		{err: fmt.Errorf("creating cluster: %w", errors.New("Rate Limit exceeded")), retriable: true},
+		{err: errors.New("exit status 1"), stderr: "invalid flag"},
+	}
+	for _, tc := range cases {
+		if retriable := p.Retriable(tc.err, tc.stderr); retriable != tc.retriable {
+			t.Errorf("expected Retriable(%q, %q) to be %t", tc.err, tc.stderr, tc.retriable)
+		}
+	}
+}
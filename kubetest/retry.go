@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"k8s.io/test-infra/kubetest/process"
+)
+
+// upClassifier is implemented by deployers that decide themselves which failures of Up
+// --up-retries retries.
+type upClassifier interface {
+	// RetriableUpError returns true if Up failed with err for a transient reason, given the
+	// stderr of the commands that it ran.
+	RetriableUpError(err error, stderr string) bool
+}
+
+// retriableUpErrors are transient errors of cloud APIs, which any deployer may hit.
+var retriableUpErrors = []string{
+	`(?i)quota.*exceeded`,
+	`(?i)rate ?limit`,
+	`(?i)service unavailable`,
+	`(?i)try again later`,
+	`TLS handshake timeout`,
+	`connection reset by peer`,
+	`i/o timeout`,
+}
+
+// deployerUpErrors are the transient errors of Up, by --deployment, in addition to retriableUpErrors.
+var deployerUpErrors = map[string][]string{
+	"gke": {
+		`ZONE_RESOURCE_POOL_EXHAUSTED`,
+		`does not have enough resources available`,
+		`Retry budget exhausted`,
+		`(?i)an internal error has occurred`,
+	},
+	"kops": {
+		`RequestLimitExceeded`,
+		`InsufficientInstanceCapacity`,
+		`VcpuLimitExceeded`,
+		`Throttling`,
+	},
+	"aks": {
+		`SkuNotAvailable`,
+		`AllocationFailed`,
+		`TooManyRequests`,
+		`RetryableError`,
+	},
+	"aksengine": {
+		`SkuNotAvailable`,
+		`AllocationFailed`,
+		`TooManyRequests`,
+		`RetryableError`,
+	},
+}
+
+// upRetry returns how to retry the Up of deploy, which tears down the cluster between attempts.
+func upRetry(o options, deploy deployer) process.Retry {
+	var classifier process.Classifier
+	if c, ok := deploy.(upClassifier); ok {
+		classifier = process.ClassifierFunc(c.RetriableUpError)
+	} else {
+		patterns := append(append([]string{}, retriableUpErrors...), deployerUpErrors[o.deployment]...)
+		classifier = process.NewPatternClassifier(patterns...)
+	}
+	return process.Retry{
+		Retries:    o.upRetries,
+		Backoff:    o.upRetryBackoff,
+		Classifier: classifier,
+		Cleanup:    deploy.Down,
+	}
+}
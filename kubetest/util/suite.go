@@ -28,8 +28,23 @@ type TestCase struct {
 	ClassName string   `xml:"classname,attr"`
 	Name      string   `xml:"name,attr"`
 	Time      float64  `xml:"time,attr"`
-	Failure   string   `xml:"failure,omitempty"`
-	Skipped   string   `xml:"skipped,omitempty"`
+	// Properties describe how the test case ran, such as the command of a step.
+	Properties *Properties `xml:"properties,omitempty"`
+	Failure    string      `xml:"failure,omitempty"`
+	Skipped    string      `xml:"skipped,omitempty"`
+	SystemOut  string      `xml:"system-out,omitempty"`
+	SystemErr  string      `xml:"system-err,omitempty"`
+}
+
+// Properties holds the properties of a TestCase.
+type Properties struct {
+	Properties []Property `xml:"property"`
+}
+
+// Property is a name and value that describes a TestCase.
+type Property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
 }
 
 // TestSuite holds a slice of TestCase and other summary metadata.
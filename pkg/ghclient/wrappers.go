@@ -32,6 +32,8 @@ import (
 
 type issueService interface {
 	Create(ctx context.Context, owner string, repo string, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+	CreateComment(ctx context.Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	Edit(ctx context.Context, owner string, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
 	ListByRepo(ctx context.Context, org, repo string, opt *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error)
 	ListLabels(ctx context.Context, owner, repo string, opt *github.ListOptions) ([]*github.Label, *github.Response, error)
 }
@@ -81,6 +83,27 @@ func (c *Client) CreateIssue(org, repo, title, body string, labels, assignees []
 	return result, err
 }
 
+// CreateComment tries to create and return a new comment on the specified github issue.
+func (c *Client) CreateComment(org, repo string, number int, body string) (*github.IssueComment, error) {
+	glog.Infof("CreateComment(dry=%t) #%d: %q\n", c.dryRun, number, body)
+	if c.dryRun {
+		return nil, nil
+	}
+
+	comment := &github.IssueComment{Body: &body}
+	var result *github.IssueComment
+	_, err := c.retry(
+		fmt.Sprintf("commenting on issue #%d", number),
+		func() (*github.Response, error) {
+			var resp *github.Response
+			var err error
+			result, resp, err = c.issueService.CreateComment(context.Background(), org, repo, number, comment)
+			return resp, err
+		},
+	)
+	return result, err
+}
+
 // CreateStatus creates or updates a status context on the indicated reference.
 func (c *Client) CreateStatus(owner, repo, ref string, status *github.RepoStatus) (*github.RepoStatus, error) {
 	glog.Infof("CreateStatus(dry=%t) ref:%s: %s:%s", c.dryRun, ref, *status.Context, *status.State)
@@ -98,6 +121,27 @@ func (c *Client) CreateStatus(owner, repo, ref string, status *github.RepoStatus
 	return result, err
 }
 
+// EditIssue tries to edit the specified github issue and return the edited issue. Only the
+// non-nil fields of the request are changed.
+func (c *Client) EditIssue(org, repo string, number int, issue *github.IssueRequest) (*github.Issue, error) {
+	glog.Infof("EditIssue(dry=%t) #%d: Title:%q, State:%q\n", c.dryRun, number, issue.GetTitle(), issue.GetState())
+	if c.dryRun {
+		return nil, nil
+	}
+
+	var result *github.Issue
+	_, err := c.retry(
+		fmt.Sprintf("editing issue #%d", number),
+		func() (*github.Response, error) {
+			var resp *github.Response
+			var err error
+			result, resp, err = c.issueService.Edit(context.Background(), org, repo, number, issue)
+			return resp, err
+		},
+	)
+	return result, err
+}
+
 type PRMungeFunc func(*github.PullRequest) error
 
 // ForEachPR iterates over all PRs that fit the specified criteria, calling the munge function on every PR.
@@ -321,6 +321,50 @@ func (f *fakeIssueService) Create(ctx context.Context, owner string, repo string
 	return result, resp, nil
 }
 
+func (f *fakeIssueService) CreateComment(ctx context.Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	resp := &github.Response{Rate: github.Rate{Limit: 5000, Remaining: 1000, Reset: github.Timestamp{Time: time.Now()}}}
+	if owner != f.org {
+		return nil, resp, fmt.Errorf("org '%s' not recognized, only '%s' is valid", owner, f.org)
+	}
+	if repo != f.repo {
+		return nil, resp, fmt.Errorf("repo '%s' not recognized, only '%s' is valid", repo, f.repo)
+	}
+	issue, ok := f.repoIssues[number]
+	if !ok {
+		return nil, resp, fmt.Errorf("issue #%d not found", number)
+	}
+	comments := 1
+	if issue.Comments != nil {
+		comments += *issue.Comments
+	}
+	issue.Comments = &comments
+	return &github.IssueComment{Body: comment.Body}, resp, nil
+}
+
+func (f *fakeIssueService) Edit(ctx context.Context, owner string, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	resp := &github.Response{Rate: github.Rate{Limit: 5000, Remaining: 1000, Reset: github.Timestamp{Time: time.Now()}}}
+	if owner != f.org {
+		return nil, resp, fmt.Errorf("org '%s' not recognized, only '%s' is valid", owner, f.org)
+	}
+	if repo != f.repo {
+		return nil, resp, fmt.Errorf("repo '%s' not recognized, only '%s' is valid", repo, f.repo)
+	}
+	result, ok := f.repoIssues[number]
+	if !ok {
+		return nil, resp, fmt.Errorf("issue #%d not found", number)
+	}
+	if issue.Title != nil {
+		result.Title = issue.Title
+	}
+	if issue.Body != nil {
+		result.Body = issue.Body
+	}
+	if issue.State != nil {
+		result.State = issue.State
+	}
+	return result, resp, nil
+}
+
 // ListByRepo returns 2 issues per page of results (served in order by number).
 func (f *fakeIssueService) ListByRepo(ctx context.Context, org, repo string, opt *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
 	resp := &github.Response{
@@ -408,6 +452,73 @@ func TestCreateIssue(t *testing.T) {
 	}
 }
 
+func TestCreateComment(t *testing.T) {
+	svc := newFakeIssueService("k8s", "kuber", nil, 3)
+	client := &Client{issueService: svc}
+	setForTest(client)
+	comment, err := client.CreateComment("k8s", "kuber", 2, "Comment")
+	if err != nil {
+		t.Fatalf("Unexpected error from CreateComment with valid args: %v.", err)
+	}
+	if comment == nil {
+		t.Fatalf("Expected comment returned by CreateComment to be non-nil, but it was nil.")
+	}
+	if *comment.Body != "Comment" {
+		t.Errorf("Expected comment from CreateComment to have a body of 'Comment' instead of '%s'.", *comment.Body)
+	}
+	if svc.repoIssues[2].Comments == nil || *svc.repoIssues[2].Comments != 1 {
+		t.Errorf("Expected issue #2 to have 1 comment after CreateComment.")
+	}
+
+	if _, err = client.CreateComment("k8s", "not-a-repo", 2, "Comment"); err == nil {
+		t.Error("Expected error from CreateComment on invalid repo, but didn't get an error.")
+	}
+
+	client.dryRun = true
+	if comment, err = client.CreateComment("k8s", "kuber", 2, "Comment"); err != nil || comment != nil {
+		t.Errorf("Expected CreateComment in dry run mode to return nil, nil instead of %v, %v.", comment, err)
+	}
+	if *svc.repoIssues[2].Comments != 1 {
+		t.Errorf("Expected CreateComment in dry run mode not to comment on issue #2.")
+	}
+}
+
+func TestEditIssue(t *testing.T) {
+	svc := newFakeIssueService("k8s", "kuber", nil, 3)
+	client := &Client{issueService: svc}
+	setForTest(client)
+	title, state := "New Title", "closed"
+	issue, err := client.EditIssue("k8s", "kuber", 2, &github.IssueRequest{Title: &title, State: &state})
+	if err != nil {
+		t.Fatalf("Unexpected error from EditIssue with valid args: %v.", err)
+	}
+	if issue == nil {
+		t.Fatalf("Expected issue returned by EditIssue to be non-nil, but it was nil.")
+	}
+	if *issue.Title != title {
+		t.Errorf("Expected issue from EditIssue to have a title of '%s' instead of '%s'.", title, *issue.Title)
+	}
+	if *issue.State != state {
+		t.Errorf("Expected issue from EditIssue to have a state of '%s' instead of '%s'.", state, *issue.State)
+	}
+	if *issue.Body != "2" {
+		t.Errorf("Expected EditIssue to leave the body of the issue unchanged, but it is '%s'.", *issue.Body)
+	}
+
+	if _, err = client.EditIssue("k8s", "kuber", 7, &github.IssueRequest{Title: &title}); err == nil {
+		t.Error("Expected error from EditIssue on nonexistent issue, but didn't get an error.")
+	}
+
+	client.dryRun = true
+	body := "New Body"
+	if issue, err = client.EditIssue("k8s", "kuber", 2, &github.IssueRequest{Body: &body}); err != nil || issue != nil {
+		t.Errorf("Expected EditIssue in dry run mode to return nil, nil instead of %v, %v.", issue, err)
+	}
+	if *svc.repoIssues[2].Body != "2" {
+		t.Errorf("Expected EditIssue in dry run mode not to edit issue #2.")
+	}
+}
+
 func TestGetIssues(t *testing.T) {
 	var issues []*github.Issue
 	var err error
@@ -17,19 +17,35 @@ limitations under the License.
 package diff
 
 import (
+	"flag"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/cov/patch"
 	"k8s.io/test-infra/gopherage/pkg/util"
 	"k8s.io/test-infra/robots/coverage/diff"
+	"k8s.io/test-infra/robots/coverage/report"
+
+	"sigs.k8s.io/prow/pkg/flagutil"
 )
 
 type flags struct {
 	outputFile string
 	threshold  float32
 	jobName    string
+	changes    util.ChangeFlags
+	sourceRoot string
+	config     util.ConfigFlags
+
+	publish  bool
+	confirm  bool
+	pr       report.PullRequest
+	prNumber string
+	github   flagutil.GitHubOptions
 }
 
 // MakeCommand returns a `diff` command.
@@ -39,7 +55,16 @@ func MakeCommand() *cobra.Command {
 		Use:   "diff [base-profile] [new-profile]",
 		Short: "Calculate the file level difference between two coverage profiles",
 		Long: `Calculate the file level difference between two coverage profiles.
-		Produce the result in a markdown table`,
+		Produce the result in a markdown table.
+		If --patch or --git-base is given, the coverage of the lines added or modified by the
+		pull request is reported as well.
+		If a gopherage config file is found or --source-root is set, files and code excluded by
+		the config file or by //coverage:ignore annotations are left out of the new profile, and
+		the config file may set thresholds per directory. Since the base profile was recorded
+		from other source, only the files excluded as a whole are left out of it.
+		With --publish, the report is also posted on the pull request, replacing the previous
+		report, and published as a check run that fails if coverage is low and annotates
+		uncovered changed lines. The pull request defaults to the one the prow job runs for`,
 		Run: func(cmd *cobra.Command, args []string) {
 			run(flags, cmd, args)
 		},
@@ -47,6 +72,18 @@ func MakeCommand() *cobra.Command {
 	cmd.Flags().StringVarP(&flags.outputFile, "output", "o", "-", "output file")
 	cmd.Flags().StringVarP(&flags.jobName, "jobname", "j", "", "prow job name")
 	cmd.Flags().Float32VarP(&flags.threshold, "threshold", "t", .8, "code coverage threshold")
+	flags.changes.AddFlags(cmd.Flags())
+	cmd.Flags().StringVar(&flags.sourceRoot, "source-root", "", "directory containing the source files referenced by the profiles; defaults to the directory containing the config file, or else the current directory")
+	flags.config.AddFlags(cmd.Flags())
+	cmd.Flags().BoolVar(&flags.publish, "publish", false, "post the report on the pull request and publish it as a check run")
+	cmd.Flags().BoolVar(&flags.confirm, "confirm", false, "actually make changes on GitHub, rather than only logging them")
+	cmd.Flags().StringVar(&flags.pr.Org, "org", os.Getenv("REPO_OWNER"), "organization of the pull request")
+	cmd.Flags().StringVar(&flags.pr.Repo, "repo", os.Getenv("REPO_NAME"), "repository of the pull request")
+	cmd.Flags().StringVar(&flags.prNumber, "pr", os.Getenv("PULL_NUMBER"), "number of the pull request")
+	cmd.Flags().StringVar(&flags.pr.HeadSHA, "sha", os.Getenv("PULL_PULL_SHA"), "head commit of the pull request")
+	githubFlags := flag.NewFlagSet("github", flag.ContinueOnError)
+	flags.github.AddFlags(githubFlags)
+	cmd.Flags().AddGoFlagSet(githubFlags)
 	return cmd
 }
 
@@ -62,6 +99,20 @@ func run(flags *flags, cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if flags.publish {
+		if err := validatePublishFlags(flags); err != nil {
+			fmt.Fprintf(os.Stderr, "%v.\n", err)
+			cmd.Usage()
+			os.Exit(2)
+		}
+	}
+
+	cfg, err := flags.config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v.\n", err)
+		os.Exit(1)
+	}
+
 	baseProfilePath := args[0]
 	newProfilePath := args[1]
 
@@ -77,7 +128,27 @@ func run(flags *flags, cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	postContent, isCoverageLow := diff.ContentForGitHubPost(baseProfiles, newProfiles, flags.jobName, flags.threshold)
+	// The base profile was recorded from other source, so only rules for whole files apply.
+	if baseProfiles, err = util.ApplyFileIgnores(baseProfiles, cfg, flags.sourceRoot); err == nil {
+		newProfiles, err = util.ApplyIgnores(newProfiles, cfg, flags.sourceRoot)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to apply ignore rules: %v.\n", err)
+		os.Exit(1)
+	}
+
+	var changes patch.Changes
+	var changedProfiles []*cover.Profile
+	if flags.changes.Enabled() {
+		changes, err = flags.changes.LoadChanges()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load changes: %v.\n", err)
+			os.Exit(1)
+		}
+		changedProfiles = patch.ChangedLines(newProfiles, changes)
+	}
+
+	postContent, isCoverageLow := diff.ContentForGitHubPost(baseProfiles, newProfiles, changedProfiles, flags.jobName, flags.threshold, cfg)
 
 	var file io.WriteCloser
 	if flags.outputFile == "-" {
@@ -103,4 +174,37 @@ func run(flags *flags, cmd *cobra.Command, args []string) {
 		fmt.Fprintf(os.Stderr, "Failed to write post content: %v.\n", err)
 		os.Exit(1)
 	}
+
+	if flags.publish {
+		if err := publish(flags, postContent, isCoverageLow, changedProfiles, changes); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to publish the report: %v.\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+func validatePublishFlags(flags *flags) error {
+	if flags.pr.Org == "" || flags.pr.Repo == "" {
+		return fmt.Errorf("--org and --repo are required to publish the report")
+	}
+	number, err := strconv.Atoi(flags.prNumber)
+	if err != nil || number <= 0 {
+		return fmt.Errorf("--pr must be a pull request number, not %q", flags.prNumber)
+	}
+	flags.pr.Number = number
+	if flags.pr.HeadSHA == "" {
+		return fmt.Errorf("--sha is required to publish the report")
+	}
+	return flags.github.Validate(!flags.confirm)
+}
+
+func publish(flags *flags, content string, isCoverageLow bool, changedProfiles []*cover.Profile, changes patch.Changes) error {
+	gc, err := flags.github.GitHubClient(!flags.confirm)
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+	if err := report.PostComment(gc, flags.pr, content); err != nil {
+		return err
+	}
+	return report.CreateCheckRun(gc, flags.pr, content, isCoverageLow, changedProfiles, changes)
 }
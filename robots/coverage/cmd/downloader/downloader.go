@@ -17,69 +17,78 @@ limitations under the License.
 package downloader
 
 import (
-	"cloud.google.com/go/storage"
 	"context"
 	"fmt"
 	"io"
-	"k8s.io/test-infra/robots/coverage/downloader"
 	"os"
 
 	"github.com/spf13/cobra"
+	"k8s.io/test-infra/robots/coverage/downloader"
+
+	pkgio "sigs.k8s.io/prow/pkg/io"
 )
 
 type flags struct {
-	outputFile       string
-	artifactsDirName string
-	profileName      string
+	outputFile         string
+	artifactsDirName   string
+	profileName        string
+	gcsCredentialsFile string
+	s3CredentialsFile  string
 }
 
 // MakeCommand returns a `download` command.
 func MakeCommand() *cobra.Command {
 	flags := &flags{}
 	cmd := &cobra.Command{
-		Use:   "download [bucket] [prowjob]",
+		Use:   "download [location] [prowjob]",
 		Short: "Finds and downloads the coverage profile file from the latest healthy build",
 		Long: `Finds and downloads the coverage profile file from the latest healthy build
-stored in given gcs directory.`,
+stored in the given artifact storage location.
+
+The location can be gs://bucket, s3://bucket or file:///path/to/dir. A location without
+a scheme is taken to be the name of a gcs bucket.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			run(flags, cmd, args)
 		},
 	}
 	cmd.Flags().StringVarP(&flags.outputFile, "output", "o", "-", "output file")
-	cmd.Flags().StringVarP(&flags.artifactsDirName, "artifactsDir", "a", "artifacts", "artifact directory name in GCS")
-	cmd.Flags().StringVarP(&flags.profileName, "profile", "p", "coverage-profile", "code coverage profile file name in GCS")
+	cmd.Flags().StringVarP(&flags.artifactsDirName, "artifactsDir", "a", "artifacts", "artifact directory name in the build directory")
+	cmd.Flags().StringVarP(&flags.profileName, "profile", "p", "coverage-profile", "code coverage profile file name in the artifact directory")
+	cmd.Flags().StringVar(&flags.gcsCredentialsFile, "gcs-credentials-file", "", "file with the credentials to read from gcs; application default credentials are used if empty")
+	cmd.Flags().StringVar(&flags.s3CredentialsFile, "s3-credentials-file", "", "file with the credentials to read from s3")
 	return cmd
 }
 
 func run(flags *flags, cmd *cobra.Command, args []string) {
 	if len(args) != 2 {
-		fmt.Fprintln(os.Stderr, "Expected exactly two arguments: bucket & prowjob")
+		fmt.Fprintln(os.Stderr, "Expected exactly two arguments: location & prowjob")
 		cmd.Usage()
 		os.Exit(2)
 	}
 
-	bucket := args[0]
+	storagePath := downloader.StoragePath(args[0])
 	prowjob := args[1]
 
 	var file io.WriteCloser
 	if flags.outputFile == "-" {
 		file = os.Stdout
 	} else {
-		file, err := os.Create(flags.outputFile)
+		f, err := os.Create(flags.outputFile)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to create output file: %v.", err)
+			fmt.Fprintf(os.Stderr, "Failed to create output file: %v.\n", err)
 			os.Exit(1)
 		}
-		defer file.Close()
+		defer f.Close()
+		file = f
 	}
 	ctx := context.Background()
-	client, err := storage.NewClient(ctx)
+	opener, err := pkgio.NewOpener(ctx, flags.gcsCredentialsFile, flags.s3CredentialsFile)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to create new storage client: %v.\n", err)
+		fmt.Fprintf(os.Stderr, "Failed to create storage opener: %v.\n", err)
 		os.Exit(1)
 	}
 
-	content, err := downloader.FindBaseProfile(ctx, client, bucket, prowjob, flags.artifactsDirName, flags.profileName)
+	content, err := downloader.FindBaseProfile(ctx, opener, storagePath, prowjob, flags.artifactsDirName, flags.profileName)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to find base profile file: %v.\n", err)
 		os.Exit(1)
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/config"
 	"k8s.io/test-infra/gopherage/pkg/cov/junit/calculation"
 )
 
@@ -41,8 +42,9 @@ func deltaDisplayed(change *coverageChange) string {
 }
 
 // makeTable checks each coverage change and produce the table content for coverage bot post
-// It also report on whether any coverage fells below the given threshold
-func makeTable(baseCovList, newCovList *calculation.CoverageList, coverageThreshold float32) (string, bool) {
+// It also report on whether any coverage fells below the given threshold, or the threshold cfg sets
+// for the file
+func makeTable(baseCovList, newCovList *calculation.CoverageList, coverageThreshold float32, cfg *config.Config) (string, bool) {
 	var rows []string
 	isCoverageLow := false
 	for _, change := range findChanges(baseCovList, newCovList) {
@@ -53,33 +55,71 @@ func makeTable(baseCovList, newCovList *calculation.CoverageList, coverageThresh
 			formatPercentage(change.newRatio),
 			deltaDisplayed(change)))
 
-		if change.newRatio < coverageThreshold {
+		if change.newRatio < cfg.ThresholdFor(change.name, coverageThreshold) {
 			isCoverageLow = true
 		}
 	}
 	return strings.Join(rows, "\n"), isCoverageLow
 }
 
-// ContentForGitHubPost constructs the message covbot posts
-func ContentForGitHubPost(baseProfiles, newProfiles []*cover.Profile, jobName string, coverageThreshold float32) (
+// makeChangedLinesTable produces the table of coverage of changed lines for coverage bot post, as
+// returned by patch.ChangedLines, with a row per file and the total.
+// It also reports on whether the total fell below the given threshold
+func makeChangedLinesTable(changedCovList *calculation.CoverageList, coverageThreshold float32) (string, bool) {
+	var rows []string
+	for _, cov := range changedCovList.Group {
+		rows = append(rows, fmt.Sprintf("%s | %d | %s", cov.Name, cov.NumAllStmts, formatPercentage(cov.Ratio())))
+	}
+	ratio := changedCovList.Ratio()
+	rows = append(rows, fmt.Sprintf("**Total** | %d | %s", changedCovList.NumAllStmts, formatPercentage(ratio)))
+	return strings.Join(rows, "\n"), ratio < coverageThreshold
+}
+
+// ContentForGitHubPost constructs the message covbot posts.
+// If changedProfiles is not empty, it should hold the coverage of only the lines changed by the pull
+// request (see patch.ChangedLines), which is then reported ahead of the per-file changes.
+// cfg, which may be nil, can override coverageThreshold for individual files.
+func ContentForGitHubPost(baseProfiles, newProfiles, changedProfiles []*cover.Profile, jobName string, coverageThreshold float32, cfg *config.Config) (
 	string, bool) {
 
-	rows := []string{
-		"The following is the code coverage report",
-		fmt.Sprintf("Say `/test %s` to re-run this coverage report", jobName),
-		"",
-		"File | Old Coverage | New Coverage | Delta",
-		"---- |:------------:|:------------:|:-----:",
+	var sections []string
+	isCoverageLow := false
+
+	changedCovList := calculation.ProduceCovList(changedProfiles)
+	if len(changedCovList.Group) > 0 {
+		changedTable, isChangedCoverageLow := makeChangedLinesTable(changedCovList, coverageThreshold)
+		isCoverageLow = isChangedCoverageLow
+		sections = append(sections,
+			fmt.Sprintf("%s of new lines covered (%d of %d)", formatPercentage(changedCovList.Ratio()), changedCovList.NumCoveredStmts, changedCovList.NumAllStmts),
+			"",
+			"File | Changed Lines | Coverage",
+			"---- |:-------------:|:--------:",
+			changedTable,
+			"",
+		)
 	}
 
-	table, isCoverageLow := makeTable(calculation.ProduceCovList(baseProfiles), calculation.ProduceCovList(newProfiles), coverageThreshold)
+	table, isTableCoverageLow := makeTable(calculation.ProduceCovList(baseProfiles), calculation.ProduceCovList(newProfiles), coverageThreshold, cfg)
+	if table != "" {
+		isCoverageLow = isCoverageLow || isTableCoverageLow
+		sections = append(sections,
+			"File | Old Coverage | New Coverage | Delta",
+			"---- |:------------:|:------------:|:-----:",
+			table,
+			"",
+		)
+	}
 
-	if table == "" {
+	if len(sections) == 0 {
 		return "", false
 	}
 
-	rows = append(rows, table)
-	rows = append(rows, "")
+	rows := []string{
+		"The following is the code coverage report",
+		fmt.Sprintf("Say `/test %s` to re-run this coverage report", jobName),
+		"",
+	}
+	rows = append(rows, sections...)
 
 	return strings.Join(rows, "\n"), isCoverageLow
 }
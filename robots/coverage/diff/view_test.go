@@ -19,6 +19,7 @@ package diff
 import (
 	"testing"
 
+	"golang.org/x/tools/cover"
 	"k8s.io/test-infra/gopherage/pkg/cov/junit/calculation"
 )
 
@@ -65,7 +66,7 @@ func TestMakeTable(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotRes, gotIsCoverageLow := makeTable(tt.args.baseCovList, tt.args.newCovList, tt.args.coverageThreshold)
+			gotRes, gotIsCoverageLow := makeTable(tt.args.baseCovList, tt.args.newCovList, tt.args.coverageThreshold, nil)
 			if gotRes != tt.wantRes {
 				t.Errorf("makeTable() gotRes = %v, want %v", gotRes, tt.wantRes)
 			}
@@ -75,3 +76,57 @@ func TestMakeTable(t *testing.T) {
 		})
 	}
 }
+
+func TestMakeChangedLinesTable(t *testing.T) {
+	changedCovList := &calculation.CoverageList{
+		Coverage: &calculation.Coverage{},
+		Group: []calculation.Coverage{
+			{Name: "a", NumCoveredStmts: 3, NumAllStmts: 4},
+			{Name: "b", NumCoveredStmts: 0, NumAllStmts: 2},
+		},
+	}
+
+	gotRes, gotIsCoverageLow := makeChangedLinesTable(changedCovList, .6)
+	wantRes := "a | 4 | 75.0%\n" +
+		"b | 2 | 0.0%\n" +
+		"**Total** | 6 | 50.0%"
+	if gotRes != wantRes {
+		t.Errorf("makeChangedLinesTable() gotRes = %v, want %v", gotRes, wantRes)
+	}
+	if !gotIsCoverageLow {
+		t.Errorf("makeChangedLinesTable() gotIsCoverageLow = false, want true")
+	}
+}
+
+func TestContentForGitHubPostChangedLines(t *testing.T) {
+	profiles := []*cover.Profile{
+		{
+			FileName: "a.go",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 1, NumStmt: 1, Count: 1},
+			},
+		},
+	}
+
+	gotRes, gotIsCoverageLow := ContentForGitHubPost(profiles, profiles, profiles, "job", .8, nil)
+	wantRes := "The following is the code coverage report\n" +
+		"Say `/test job` to re-run this coverage report\n" +
+		"\n" +
+		"100.0% of new lines covered (1 of 1)\n" +
+		"\n" +
+		"File | Changed Lines | Coverage\n" +
+		"---- |:-------------:|:--------:\n" +
+		"a.go | 1 | 100.0%\n" +
+		"**Total** | 1 | 100.0%\n"
+	if gotRes != wantRes {
+		t.Errorf("ContentForGitHubPost() gotRes = %q, want %q", gotRes, wantRes)
+	}
+	if gotIsCoverageLow {
+		t.Errorf("ContentForGitHubPost() gotIsCoverageLow = true, want false")
+	}
+
+	if gotRes, _ := ContentForGitHubPost(profiles, profiles, nil, "job", .8, nil); gotRes != "" {
+		t.Errorf("ContentForGitHubPost() without changes gotRes = %q, want empty", gotRes)
+	}
+}
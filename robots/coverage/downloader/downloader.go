@@ -15,93 +15,106 @@ limitations under the License.
 */
 
 // Package downloader finds and downloads the coverage profile file from the latest healthy build
-// stored in given gcs directory
+// stored in a given artifact storage location, such as a gcs or s3 bucket or a local directory
 package downloader
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"path"
 	"sort"
 	"strconv"
+	"strings"
 
-	"cloud.google.com/go/storage"
 	"github.com/sirupsen/logrus"
-	"google.golang.org/api/iterator"
 
 	prowv1 "sigs.k8s.io/prow/pkg/apis/prowjobs/v1"
+	pkgio "sigs.k8s.io/prow/pkg/io"
 )
 
-// listGcsObjects get the slice of gcs objects under a given path
-func listGcsObjects(ctx context.Context, client *storage.Client, bucketName, prefix, delim string) (
-	[]string, error) {
+// StoragePath returns the storage location as a path understood by a pkg/io Opener.
+// A location without a scheme is taken to be the name of a gcs bucket, for backwards compatibility.
+func StoragePath(location string) string {
+	if strings.Contains(location, "://") {
+		return strings.TrimSuffix(location, "/")
+	}
+	return "gs://" + strings.Trim(location, "/")
+}
+
+// joinPath joins elems onto a storage path such as gs://bucket. path.Join cannot be used on the
+// whole path, since it would collapse the slashes that follow the scheme.
+func joinPath(storagePath string, elems ...string) string {
+	return strings.TrimSuffix(storagePath, "/") + "/" + path.Join(elems...)
+}
 
-	var objects []string
-	it := client.Bucket(bucketName).Objects(ctx, &storage.Query{
-		Prefix:    prefix,
-		Delimiter: delim,
-	})
+// listDirs gets the names of the directories directly under a given prefix
+func listDirs(ctx context.Context, opener pkgio.Opener, prefix string) ([]string, error) {
+	it, err := opener.Iterator(ctx, prefix, "/")
+	if err != nil {
+		return nil, fmt.Errorf("cannot list objects under '%s': %w", prefix, err)
+	}
 
+	var dirs []string
 	for {
-		attrs, err := it.Next()
-		if err == iterator.Done {
+		attrs, err := it.Next(ctx)
+		if errors.Is(err, io.EOF) {
 			break
 		}
 		if err != nil {
-			return objects, fmt.Errorf("error iterating: %w", err)
+			return dirs, fmt.Errorf("error iterating: %w", err)
 		}
 
-		if attrs.Prefix != "" {
-			objects = append(objects, path.Base(attrs.Prefix))
+		if attrs.IsDir {
+			dirs = append(dirs, path.Base(attrs.Name))
 		}
 	}
-	logrus.Info("end of listGcsObjects(...)")
-	return objects, nil
+	return dirs, nil
 }
 
-func readGcsObject(ctx context.Context, client *storage.Client, bucket, object string) ([]byte, error) {
-	logrus.Infof("Trying to read gcs object '%s' in bucket '%s'\n", object, bucket)
-	o := client.Bucket(bucket).Object(object)
-	reader, err := o.NewReader(ctx)
+func readObject(ctx context.Context, opener pkgio.Opener, objectPath string) ([]byte, error) {
+	logrus.Infof("Trying to read object '%s'", objectPath)
+	reader, err := opener.Reader(ctx, objectPath)
 	if err != nil {
-		return nil, fmt.Errorf("cannot read object '%s': %w", object, err)
+		return nil, fmt.Errorf("cannot read object '%s': %w", objectPath, err)
 	}
+	defer reader.Close()
 	return io.ReadAll(reader)
 }
 
-// FindBaseProfile finds the coverage profile file from the latest healthy build
-// stored in given gcs directory
-func FindBaseProfile(ctx context.Context, client *storage.Client, bucket, prowJobName, artifactsDirName,
+// FindBaseProfile finds the coverage profile file from the latest healthy build stored under
+// storagePath, which is anything the opener understands, e.g. gs://bucket, s3://bucket or
+// file:///some/dir
+func FindBaseProfile(ctx context.Context, opener pkgio.Opener, storagePath, prowJobName, artifactsDirName,
 	covProfileName string) ([]byte, error) {
 
 	dirOfJob := path.Join("logs", prowJobName)
 
-	strBuilds, err := listGcsObjects(ctx, client, bucket, dirOfJob+"/", "/")
+	strBuilds, err := listDirs(ctx, opener, joinPath(storagePath, dirOfJob)+"/")
 	if err != nil {
-		return nil, fmt.Errorf("error listing gcs objects: %w", err)
+		return nil, fmt.Errorf("error listing builds: %w", err)
 	}
 
 	builds := sortBuilds(strBuilds)
 	profilePath := ""
 	for _, build := range builds {
 		buildDirPath := path.Join(dirOfJob, strconv.Itoa(build))
-		dirOfStatusJSON := path.Join(buildDirPath, prowv1.FinishedStatusFile)
+		statusJSONPath := joinPath(storagePath, buildDirPath, prowv1.FinishedStatusFile)
 
-		statusText, err := readGcsObject(ctx, client, bucket, dirOfStatusJSON)
+		statusText, err := readObject(ctx, opener, statusJSONPath)
 		if err != nil {
-			logrus.Infof("Cannot read finished.json (%s) in bucket '%s'", dirOfStatusJSON, bucket)
+			logrus.Infof("Cannot read finished.json (%s)", statusJSONPath)
 		} else if isBuildSucceeded(statusText) {
-			artifactsDirPath := path.Join(buildDirPath, artifactsDirName)
-			profilePath = path.Join(artifactsDirPath, covProfileName)
+			profilePath = joinPath(storagePath, buildDirPath, artifactsDirName, covProfileName)
 			break
 		}
 	}
 	if profilePath == "" {
-		return nil, fmt.Errorf("no healthy build found for job '%s' in bucket '%s'; total # builds = %v", dirOfJob, bucket, len(builds))
+		return nil, fmt.Errorf("no healthy build found for job '%s' in '%s'; total # builds = %v", dirOfJob, storagePath, len(builds))
 	}
-	return readGcsObject(ctx, client, bucket, profilePath)
+	return readObject(ctx, opener, profilePath)
 }
 
 // sortBuilds converts all build from str to int and sorts all builds in descending order and
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package downloader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pkgio "sigs.k8s.io/prow/pkg/io"
+)
+
+// writeBuild creates the directory of a build of job under root, with the given finished.json
+// content and coverage profile. Either may be empty to leave the file out.
+func writeBuild(t *testing.T, root, job, build, finished, profile string) {
+	t.Helper()
+	dir := filepath.Join(root, "logs", job, build)
+	files := map[string]string{
+		"finished.json":              finished,
+		"artifacts/coverage-profile": profile,
+	}
+	for name, content := range files {
+		if content == "" {
+			continue
+		}
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFindBaseProfile(t *testing.T) {
+	const job = "ci-coverage"
+	const passed = `{"timestamp": 1, "passed": true}`
+	const failed = `{"timestamp": 1, "passed": false}`
+
+	tests := []struct {
+		name    string
+		builds  map[string][2]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "latest build passed",
+			builds: map[string][2]string{
+				"9":  {passed, "mode: set\nold\n"},
+				"10": {passed, "mode: set\nnew\n"},
+			},
+			want: "mode: set\nnew\n",
+		},
+		{
+			name: "skips failed and unfinished builds",
+			builds: map[string][2]string{
+				"7":       {passed, "mode: set\ngood\n"},
+				"8":       {failed, "mode: set\nbad\n"},
+				"9":       {"", ""},
+				"latest":  {passed, "mode: set\nnot a build\n"},
+				"10-plus": {passed, "mode: set\nnot a build\n"},
+			},
+			want: "mode: set\ngood\n",
+		},
+		{
+			name: "no healthy build",
+			builds: map[string][2]string{
+				"1": {failed, "mode: set\n"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no builds",
+			wantErr: true,
+		},
+	}
+
+	ctx := context.Background()
+	opener, err := pkgio.NewOpener(ctx, "", "")
+	if err != nil {
+		t.Fatalf("Failed to create opener: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for build, files := range tt.builds {
+				writeBuild(t, root, job, build, files[0], files[1])
+			}
+
+			got, err := FindBaseProfile(ctx, opener, StoragePath("file://"+root), job, "artifacts", "coverage-profile")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindBaseProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("FindBaseProfile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoragePath(t *testing.T) {
+	tests := []struct {
+		location string
+		want     string
+	}{
+		{location: "kubernetes-ci-logs", want: "gs://kubernetes-ci-logs"},
+		{location: "gs://kubernetes-ci-logs/", want: "gs://kubernetes-ci-logs"},
+		{location: "s3://prow-logs", want: "s3://prow-logs"},
+		{location: "file:///tmp/mirror", want: "file:///tmp/mirror"},
+	}
+	for _, tt := range tests {
+		if got := StoragePath(tt.location); got != tt.want {
+			t.Errorf("StoragePath(%q) = %q, want %q", tt.location, got, tt.want)
+		}
+	}
+	if got, want := joinPath("gs://bucket", "logs/job", "1", "finished.json"), "gs://bucket/logs/job/1/finished.json"; got != want {
+		t.Errorf("joinPath() = %q, want %q", got, want)
+	}
+}
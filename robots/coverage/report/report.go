@@ -0,0 +1,185 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package report publishes the coverage report produced by covbot on a pull request, as a comment
+// that is kept up to date and as a check run
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/cov/patch"
+
+	"sigs.k8s.io/prow/pkg/github"
+)
+
+const (
+	// Marker is hidden in every comment covbot posts, so that it can find its previous comment
+	Marker = "<!-- covbot:coverage-report -->"
+	// CheckName is the name of the check run covbot publishes
+	CheckName = "coverage"
+	// maxAnnotations is the most annotations GitHub accepts in a single request
+	maxAnnotations = 50
+)
+
+type githubClient interface {
+	BotUserChecker() (func(candidate string) bool, error)
+	ListIssueComments(org, repo string, number int) ([]github.IssueComment, error)
+	CreateComment(org, repo string, number int, comment string) error
+	EditComment(org, repo string, id int, comment string) error
+	CreateCheckRun(org, repo string, checkRun github.CheckRun) error
+}
+
+// PullRequest identifies the pull request, and the commit of it, that coverage was measured on
+type PullRequest struct {
+	Org     string
+	Repo    string
+	Number  int
+	HeadSHA string
+}
+
+// PostComment posts content on the pull request, replacing the comment covbot posted previously if
+// there is one, so that the pull request only ever has a single, up to date, coverage comment.
+// If content is empty, nothing is posted, but a previous comment is updated to say so.
+func PostComment(gc githubClient, pr PullRequest, content string) error {
+	isBot, err := gc.BotUserChecker()
+	if err != nil {
+		return fmt.Errorf("failed to get the bot user: %w", err)
+	}
+	comments, err := gc.ListIssueComments(pr.Org, pr.Repo, pr.Number)
+	if err != nil {
+		return fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	var previous *github.IssueComment
+	for i, comment := range comments {
+		if isBot(comment.User.Login) && strings.Contains(comment.Body, Marker) {
+			previous = &comments[i]
+			break
+		}
+	}
+
+	if content == "" {
+		if previous == nil {
+			return nil
+		}
+		content = "The code coverage is unchanged by this pull request"
+	}
+	body := Marker + "\n" + content
+
+	if previous == nil {
+		if err := gc.CreateComment(pr.Org, pr.Repo, pr.Number, body); err != nil {
+			return fmt.Errorf("failed to create comment: %w", err)
+		}
+		return nil
+	}
+	if normalizeComment(previous.Body) == normalizeComment(body) {
+		logrus.Infof("Coverage comment %d is up to date", previous.ID)
+		return nil
+	}
+	if err := gc.EditComment(pr.Org, pr.Repo, previous.ID, body); err != nil {
+		return fmt.Errorf("failed to edit comment %d: %w", previous.ID, err)
+	}
+	return nil
+}
+
+// normalizeComment makes comment bodies comparable across GitHub round-trips,
+// which can differ in line endings and surrounding whitespace.
+func normalizeComment(s string) string {
+	return strings.TrimSpace(strings.ReplaceAll(s, "\r\n", "\n"))
+}
+
+// CreateCheckRun publishes the coverage report content as a completed check run on the head commit
+// of the pull request, which fails if isCoverageLow.
+// Every uncovered line in changedProfiles (see patch.ChangedLines) is annotated, at the path changes
+// gives for the file.
+func CreateCheckRun(gc githubClient, pr PullRequest, content string, isCoverageLow bool,
+	changedProfiles []*cover.Profile, changes patch.Changes) error {
+
+	checkRun := github.CheckRun{
+		Name:       CheckName,
+		HeadSHA:    pr.HeadSHA,
+		Status:     "completed",
+		Conclusion: "success",
+		Output: github.CheckRunOutput{
+			Title:   "Code coverage meets the threshold",
+			Summary: content,
+		},
+	}
+	if isCoverageLow {
+		checkRun.Conclusion = "failure"
+		checkRun.Output.Title = "Code coverage is below the threshold"
+	}
+	if checkRun.Output.Summary == "" {
+		checkRun.Output.Summary = "The code coverage is unchanged by this pull request"
+	}
+
+	annotations := uncoveredAnnotations(changedProfiles, changes)
+	if len(annotations) > maxAnnotations {
+		checkRun.Output.Summary += fmt.Sprintf("\n\nOnly the first %d of %d uncovered ranges of changed lines are annotated.",
+			maxAnnotations, len(annotations))
+		annotations = annotations[:maxAnnotations]
+	}
+	checkRun.Output.Annotations = annotations
+
+	if err := gc.CreateCheckRun(pr.Org, pr.Repo, checkRun); err != nil {
+		return fmt.Errorf("failed to create check run: %w", err)
+	}
+	return nil
+}
+
+// uncoveredAnnotations returns an annotation for every range of consecutive uncovered lines in
+// changedProfiles, whose blocks each cover a single line, as produced by patch.ChangedLines
+func uncoveredAnnotations(changedProfiles []*cover.Profile, changes patch.Changes) []github.CheckRunAnnotation {
+	var annotations []github.CheckRunAnnotation
+	for _, profile := range changedProfiles {
+		path := changes.Match(profile.FileName)
+		if path == "" {
+			path = profile.FileName
+		}
+		var current *github.CheckRunAnnotation
+		for _, block := range profile.Blocks {
+			if block.Count > 0 {
+				current = nil
+				continue
+			}
+			if current != nil && block.StartLine == current.EndLine+1 {
+				current.EndLine = block.StartLine
+				continue
+			}
+			annotations = append(annotations, github.CheckRunAnnotation{
+				Path:            path,
+				StartLine:       block.StartLine,
+				EndLine:         block.StartLine,
+				AnnotationLevel: "warning",
+				Title:           "Uncovered change",
+			})
+			current = &annotations[len(annotations)-1]
+		}
+	}
+	for i := range annotations {
+		a := &annotations[i]
+		if a.StartLine == a.EndLine {
+			a.Message = fmt.Sprintf("Changed line %d is not covered by tests.", a.StartLine)
+		} else {
+			a.Message = fmt.Sprintf("Changed lines %d to %d are not covered by tests.", a.StartLine, a.EndLine)
+		}
+	}
+	return annotations
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/cover"
+	"k8s.io/test-infra/gopherage/pkg/cov/patch"
+
+	"sigs.k8s.io/prow/pkg/github"
+)
+
+const botName = "covbot"
+
+type fakeClient struct {
+	comments  []github.IssueComment
+	created   []string
+	edited    map[int]string
+	checkRuns []github.CheckRun
+}
+
+// Fakes checking for the bot user, using the same signature as github.Client
+func (c *fakeClient) BotUserChecker() (func(candidate string) bool, error) {
+	return func(candidate string) bool { return candidate == botName }, nil
+}
+
+// Fakes listing an issue's comments, using the same signature as github.Client
+func (c *fakeClient) ListIssueComments(org, repo string, number int) ([]github.IssueComment, error) {
+	if repo == "list-error" {
+		return nil, errors.New("list error")
+	}
+	return c.comments, nil
+}
+
+// Fakes creating a comment, using the same signature as github.Client
+func (c *fakeClient) CreateComment(org, repo string, number int, comment string) error {
+	c.created = append(c.created, comment)
+	return nil
+}
+
+// Fakes editing a comment, using the same signature as github.Client
+func (c *fakeClient) EditComment(org, repo string, id int, comment string) error {
+	if c.edited == nil {
+		c.edited = map[int]string{}
+	}
+	c.edited[id] = comment
+	return nil
+}
+
+// Fakes creating a check run, using the same signature as github.Client
+func (c *fakeClient) CreateCheckRun(org, repo string, checkRun github.CheckRun) error {
+	c.checkRuns = append(c.checkRuns, checkRun)
+	return nil
+}
+
+func comment(id int, login, body string) github.IssueComment {
+	return github.IssueComment{ID: id, User: github.User{Login: login}, Body: body}
+}
+
+func TestPostComment(t *testing.T) {
+	pr := PullRequest{Org: "kubernetes", Repo: "test-infra", Number: 1}
+	tests := []struct {
+		name        string
+		repo        string
+		comments    []github.IssueComment
+		content     string
+		wantCreated []string
+		wantEdited  map[int]string
+		wantErr     bool
+	}{
+		{
+			name:        "first report is posted",
+			comments:    []github.IssueComment{comment(1, "someone", "lgtm")},
+			content:     "report",
+			wantCreated: []string{Marker + "\nreport"},
+		},
+		{
+			name: "previous report is edited",
+			comments: []github.IssueComment{
+				comment(1, "someone", Marker+"\nquoted by a human"),
+				comment(2, botName, Marker+"\nold report"),
+			},
+			content:    "report",
+			wantEdited: map[int]string{2: Marker + "\nreport"},
+		},
+		{
+			name:     "up to date report is left alone",
+			comments: []github.IssueComment{comment(2, botName, Marker+"\r\nreport\n")},
+			content:  "report",
+		},
+		{
+			name:     "bot comments without the marker are ignored",
+			comments: []github.IssueComment{comment(2, botName, "something else")},
+			content:  "report",
+			wantCreated: []string{
+				Marker + "\nreport",
+			},
+		},
+		{
+			name: "empty report is not posted",
+		},
+		{
+			name:       "empty report replaces previous report",
+			comments:   []github.IssueComment{comment(2, botName, Marker+"\nold report")},
+			wantEdited: map[int]string{2: Marker + "\nThe code coverage is unchanged by this pull request"},
+		},
+		{
+			name:    "listing comments fails",
+			repo:    "list-error",
+			content: "report",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &fakeClient{comments: tt.comments}
+			pr := pr
+			if tt.repo != "" {
+				pr.Repo = tt.repo
+			}
+			err := PostComment(c, pr, tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PostComment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(c.created, tt.wantCreated) {
+				t.Errorf("created comments = %q, want %q", c.created, tt.wantCreated)
+			}
+			if !reflect.DeepEqual(c.edited, tt.wantEdited) {
+				t.Errorf("edited comments = %q, want %q", c.edited, tt.wantEdited)
+			}
+		})
+	}
+}
+
+func lineProfile(fileName string, counts map[int]int, lines ...int) *cover.Profile {
+	p := &cover.Profile{FileName: fileName, Mode: "count"}
+	for _, l := range lines {
+		p.Blocks = append(p.Blocks, cover.ProfileBlock{StartLine: l, StartCol: 1, EndLine: l + 1, EndCol: 1, NumStmt: 1, Count: counts[l]})
+	}
+	return p
+}
+
+func TestCreateCheckRun(t *testing.T) {
+	pr := PullRequest{Org: "kubernetes", Repo: "test-infra", Number: 1, HeadSHA: "abc123"}
+	changes := patch.Changes{"pkg/a.go": {3, 4, 5, 7, 9}}
+	changed := []*cover.Profile{
+		lineProfile("k8s.io/test-infra/pkg/a.go", map[int]int{5: 1}, 3, 4, 5, 7, 9),
+	}
+
+	c := &fakeClient{}
+	if err := CreateCheckRun(c, pr, "report", true, changed, changes); err != nil {
+		t.Fatalf("CreateCheckRun() failed: %v", err)
+	}
+
+	want := []github.CheckRun{{
+		Name:       CheckName,
+		HeadSHA:    "abc123",
+		Status:     "completed",
+		Conclusion: "failure",
+		Output: github.CheckRunOutput{
+			Title:   "Code coverage is below the threshold",
+			Summary: "report",
+			Annotations: []github.CheckRunAnnotation{
+				{Path: "pkg/a.go", StartLine: 3, EndLine: 4, AnnotationLevel: "warning", Title: "Uncovered change", Message: "Changed lines 3 to 4 are not covered by tests."},
+				{Path: "pkg/a.go", StartLine: 7, EndLine: 7, AnnotationLevel: "warning", Title: "Uncovered change", Message: "Changed line 7 is not covered by tests."},
+				{Path: "pkg/a.go", StartLine: 9, EndLine: 9, AnnotationLevel: "warning", Title: "Uncovered change", Message: "Changed line 9 is not covered by tests."},
+			},
+		},
+	}}
+	if !reflect.DeepEqual(c.checkRuns, want) {
+		t.Errorf("bad result.\n\nexpected: %+v\nactual: %+v", want, c.checkRuns)
+	}
+}
+
+func TestCreateCheckRunLimitsAnnotations(t *testing.T) {
+	var lines []int
+	for l := 1; l <= 2*(maxAnnotations+10); l += 2 {
+		lines = append(lines, l)
+	}
+	changed := []*cover.Profile{lineProfile("a.go", nil, lines...)}
+
+	c := &fakeClient{}
+	if err := CreateCheckRun(c, PullRequest{}, "", false, changed, patch.Changes{"a.go": lines}); err != nil {
+		t.Fatalf("CreateCheckRun() failed: %v", err)
+	}
+	checkRun := c.checkRuns[0]
+	if checkRun.Conclusion != "success" {
+		t.Errorf("expected the check to succeed, got %q", checkRun.Conclusion)
+	}
+	if n := len(checkRun.Output.Annotations); n != maxAnnotations {
+		t.Errorf("expected %d annotations, got %d", maxAnnotations, n)
+	}
+	want := "The code coverage is unchanged by this pull request\n\nOnly the first 50 of 60 uncovered ranges of changed lines are annotated."
+	if checkRun.Output.Summary != want {
+		t.Errorf("expected summary %q, got %q", want, checkRun.Output.Summary)
+	}
+}
@@ -21,8 +21,11 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"math"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/github"
 	"k8s.io/test-infra/pkg/ghclient"
@@ -38,6 +41,8 @@ type RepoClient interface {
 	GetRepoLabels(org, repo string) ([]*github.Label, error)
 	GetIssues(org, repo string, options *github.IssueListByRepoOptions) ([]*github.Issue, error)
 	CreateIssue(org, repo, title, body string, labels, owners []string) (*github.Issue, error)
+	EditIssue(org, repo string, number int, issue *github.IssueRequest) (*github.Issue, error)
+	CreateComment(org, repo string, number int, body string) (*github.IssueComment, error)
 	GetCollaborators(org, repo string) ([]*github.User, error)
 }
 
@@ -63,6 +68,14 @@ func (c githubClient) CreateIssue(org, repo, title, body string, labels, owners
 	return c.Client.CreateIssue(org, repo, title, body, labels, owners)
 }
 
+func (c githubClient) EditIssue(org, repo string, number int, issue *github.IssueRequest) (*github.Issue, error) {
+	return c.Client.EditIssue(org, repo, number, issue)
+}
+
+func (c githubClient) CreateComment(org, repo string, number int, body string) (*github.IssueComment, error) {
+	return c.Client.CreateComment(org, repo, number, body)
+}
+
 // OwnerMapper finds an owner for a given test name.
 type OwnerMapper interface {
 	// TestOwner returns a GitHub username for a test, or "" if none are found.
@@ -95,12 +108,25 @@ type Issue interface {
 	Priority() (string, bool)
 }
 
+// CountedIssue is an Issue that counts how often it occurred, such as the number of flakes or of
+// failed builds. The IssueCreator comments on the open github issue for a CountedIssue when the
+// count changes significantly.
+type CountedIssue interface {
+	Issue
+	// Count returns how often the issue occurred.
+	Count() int
+}
+
 // IssueSource represents a source of auto-filed issues, such as triage-filer or flakyjob-reporter.
 type IssueSource interface {
 	Issues(*IssueCreator) ([]Issue, error)
 	RegisterFlags()
 }
 
+// ErrSourceDisabled is returned by IssueSource.Issues when the source is not configured to report
+// issues. Unlike other errors, it is not logged as an error.
+var ErrSourceDisabled = errors.New("source is disabled")
+
 // IssueCreator handles syncing identified issues with github issues.
 // This includes finding existing github issues, creating new ones, and ensuring that duplicate
 // github issues are not created.
@@ -131,6 +157,14 @@ type IssueCreator struct {
 	project string
 	// org is the github organization that owns the repo.
 	org string
+	// closeAfterDays is the number of days after which open issues that are no longer reported by
+	// their source are closed, or 0 if they should never be closed.
+	closeAfterDays int
+	// commentChangeRatio is the fraction by which the count of a CountedIssue must change to comment
+	// on its open github issue, or 0 if such comments should never be made.
+	commentChangeRatio float64
+	// now returns the current time. If nil, time.Now is used.
+	now func() time.Time
 
 	// Owners is an OwnerMapper that maps test names to owners and SIG areas.
 	Owners OwnerMapper
@@ -189,7 +223,10 @@ func (c *IssueCreator) CreateAndSync() {
 	for srcName, src := range sources {
 		glog.Infof("Generating issues from source: %s.", srcName)
 		var issues []Issue
-		if issues, err = src.Issues(c); err != nil {
+		if issues, err = src.Issues(c); errors.Is(err, ErrSourceDisabled) {
+			glog.Infof("Skipping disabled source: %s.", srcName)
+			continue
+		} else if err != nil {
 			glog.Errorf("Error generating issues. Source: %s Msg: %v.", srcName, err)
 			continue
 		}
@@ -201,7 +238,7 @@ func (c *IssueCreator) CreateAndSync() {
 		glog.Infof("Syncing issues from source: %s.", srcName)
 		created := 0
 		for _, issue := range issues {
-			if c.sync(issue) {
+			if c.sync(srcName, issue) {
 				created++
 			}
 		}
@@ -211,7 +248,28 @@ func (c *IssueCreator) CreateAndSync() {
 			len(issues),
 			srcName,
 		)
+		if closed := c.closeStale(srcName, issues); closed > 0 {
+			glog.Infof("Closed %d issues no longer reported by source: %s.", closed, srcName)
+		}
+	}
+}
+
+// currentTime returns the current time.
+func (c *IssueCreator) currentTime() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+// sortedIssues returns the issues in the cache, sorted by issue number.
+func (c *IssueCreator) sortedIssues() []*github.Issue {
+	issues := make([]*github.Issue, 0, len(c.allIssues))
+	for _, i := range c.allIssues {
+		issues = append(issues, i)
 	}
+	sort.Slice(issues, func(i, j int) bool { return *issues[i].Number < *issues[j].Number })
+	return issues
 }
 
 // loadCache loads the valid labels for the repo, the currently authenticated user, and the issue cache from github.
@@ -285,6 +343,8 @@ func (c *IssueCreator) RegisterFlags() {
 	flag.StringVar(&c.project, "project", "", "The name of the github repo to create issues in.")
 	flag.StringVar(&c.org, "org", "", "The name of the organization that owns the repo to create issues in.")
 	flag.BoolVar(&c.dryRun, "dry-run", true, "True iff only 'read' operations should be made on github.")
+	flag.IntVar(&c.closeAfterDays, "close-after-days", 7, "The number of days after which open issues that their source no longer reports are closed. 0 disables closing issues.")
+	flag.Float64Var(&c.commentChangeRatio, "comment-change-ratio", 0.5, "The fraction by which the count of an open issue must change since it was created or last commented on to comment on it. 0 disables these comments.")
 
 	for _, src := range sources {
 		src.RegisterFlags()
@@ -312,17 +372,20 @@ func setIntersect(a, b []string) (filtered, removed []string) {
 }
 
 // sync checks to see if an issue is already on github and tries to create a new issue for it if it is not.
+// If an open issue exists, it is updated instead. source is the name of the IssueSource that reported the issue.
 // True is returned iff a new issue is created.
-func (c *IssueCreator) sync(issue Issue) bool {
+func (c *IssueCreator) sync(source string, issue Issue) bool {
 	// First look for existing issues with this ID.
 	id := issue.ID()
+	var open *github.Issue
 	var closedIssues []*github.Issue
-	for _, i := range c.allIssues {
+	for _, i := range c.sortedIssues() {
 		if strings.Contains(*i.Body, id) {
 			switch *i.State {
 			case "open":
-				//if an open issue is found with the ID then the issue is already synced
-				return false
+				if open == nil {
+					open = i
+				}
 			case "closed":
 				closedIssues = append(closedIssues, i)
 			default:
@@ -330,16 +393,21 @@ func (c *IssueCreator) sync(issue Issue) bool {
 			}
 		}
 	}
-	// No open issues exist for the ID.
 	body := issue.Body(closedIssues)
+	if body != "" && !strings.Contains(body, id) {
+		glog.Fatalf("Programmer error: The following body text does not contain id '%s'.\n%s\n", id, body)
+	}
+	if open != nil {
+		// The issue is already synced, but its data may have changed.
+		c.update(source, open, issue, body)
+		return false
+	}
+	// No open issues exist for the ID.
 	if body == "" {
 		// Issue indicated that it should not be synced.
 		glog.Infof("Issue aborted sync by providing \"\" (empty) body. ID: %s.", id)
 		return false
 	}
-	if !strings.Contains(body, id) {
-		glog.Fatalf("Programmer error: The following body text does not contain id '%s'.\n%s\n", id, body)
-	}
 
 	title := issue.Title()
 	owners := issue.Owners()
@@ -368,7 +436,11 @@ func (c *IssueCreator) sync(issue Issue) bool {
 		return true
 	}
 
-	created, err := c.client.CreateIssue(c.org, c.project, title, body, labels, owners)
+	state := issueState{Source: source, LastReported: c.currentTime()}
+	if counted, ok := issue.(CountedIssue); ok {
+		state.Counted = &countRecord{Count: counted.Count(), At: state.LastReported}
+	}
+	created, err := c.client.CreateIssue(c.org, c.project, title, withState(body, state), labels, owners)
 	if err != nil {
 		glog.Errorf("Failed to create a new github issue for issue ID '%s'.\n", id)
 		return false
@@ -377,6 +449,124 @@ func (c *IssueCreator) sync(issue Issue) bool {
 	return true
 }
 
+// update refreshes the title and body of an open github issue with the data of issue, which source
+// reported, and comments on it if the count of the issue changed significantly. body is the new body
+// of the issue, or "" if only the state of the github issue should be refreshed.
+func (c *IssueCreator) update(source string, existing *github.Issue, issue Issue, body string) {
+	oldBody, oldState, hasState := parseState(*existing.Body)
+	title := issue.Title()
+	if body == "" {
+		body, title = oldBody, existing.GetTitle()
+	}
+
+	state := issueState{Source: source, LastReported: c.currentTime()}
+	var comment string
+	if counted, ok := issue.(CountedIssue); ok {
+		state.Counted = &countRecord{Count: counted.Count(), At: state.LastReported}
+		if hasState && oldState.Counted != nil {
+			if c.isSignificantChange(oldState.Counted.Count, state.Counted.Count) {
+				comment = fmt.Sprintf(
+					"The count for this issue changed from %d to %d since %s. The issue now reads:\n> %s",
+					oldState.Counted.Count,
+					state.Counted.Count,
+					oldState.Counted.At.Format(time.RFC1123),
+					title,
+				)
+			} else {
+				state.Counted = oldState.Counted
+			}
+		}
+	}
+
+	glog.Infof("Update Issue #%d: %q\n", *existing.Number, title)
+	if c.dryRun {
+		return
+	}
+
+	if comment != "" {
+		if _, err := c.client.CreateComment(c.org, c.project, *existing.Number, comment); err != nil {
+			glog.Errorf("Failed to comment on github issue #%d: %v.\n", *existing.Number, err)
+			// Comment again on the next update.
+			state.Counted = oldState.Counted
+		}
+	}
+	newBody := withState(body, state)
+	edited, err := c.client.EditIssue(c.org, c.project, *existing.Number, &github.IssueRequest{Title: &title, Body: &newBody})
+	if err != nil {
+		glog.Errorf("Failed to update github issue #%d for issue ID '%s': %v.\n", *existing.Number, issue.ID(), err)
+		return
+	}
+	c.allIssues[*edited.Number] = edited
+}
+
+// isSignificantChange returns whether a count that changed from old to new changed by at least
+// commentChangeRatio.
+func (c *IssueCreator) isSignificantChange(old, new int) bool {
+	if c.commentChangeRatio <= 0 || old == new {
+		return false
+	}
+	if old == 0 {
+		return true
+	}
+	return math.Abs(float64(new-old)) >= c.commentChangeRatio*float64(old)
+}
+
+// closeStale closes the open github issues that source reported in earlier runs but that are not
+// among issues, once source has not reported them for closeAfterDays. Issues without a state, such
+// as those created before the state was introduced, are never closed.
+// The number of issues closed is returned.
+func (c *IssueCreator) closeStale(source string, issues []Issue) int {
+	if c.closeAfterDays <= 0 {
+		return 0
+	}
+	cutoff := c.currentTime().AddDate(0, 0, -c.closeAfterDays)
+	closed := 0
+	for _, i := range c.sortedIssues() {
+		if *i.State != "open" {
+			continue
+		}
+		_, state, ok := parseState(*i.Body)
+		if !ok || state.Source != source || state.LastReported.After(cutoff) || isReported(i, issues) {
+			continue
+		}
+
+		glog.Infof("Close Issue #%d: %q\n", *i.Number, i.GetTitle())
+		closed++
+		if c.dryRun {
+			continue
+		}
+
+		comment := fmt.Sprintf(
+			"Closing this issue because %s has not reported it in the %d days since %s. A new issue will be created if it is reported again.",
+			source,
+			c.closeAfterDays,
+			state.LastReported.Format(time.RFC1123),
+		)
+		if _, err := c.client.CreateComment(c.org, c.project, *i.Number, comment); err != nil {
+			glog.Errorf("Failed to comment on github issue #%d before closing it: %v.\n", *i.Number, err)
+			continue
+		}
+		closedState := "closed"
+		edited, err := c.client.EditIssue(c.org, c.project, *i.Number, &github.IssueRequest{State: &closedState})
+		if err != nil {
+			glog.Errorf("Failed to close github issue #%d: %v.\n", *i.Number, err)
+			continue
+		}
+		c.allIssues[*edited.Number] = edited
+	}
+	return closed
+}
+
+// isReported returns whether the github issue belongs to one of issues.
+func isReported(ghIssue *github.Issue, issues []Issue) bool {
+	for _, issue := range issues {
+		if strings.Contains(*ghIssue.Body, issue.ID()) {
+			return true
+		}
+	}
+	return false
+}
+
 // TestSIG uses the IssueCreator's OwnerMapper to look up the SIG for a test.
 func (c *IssueCreator) TestSIG(testName string) string {
 	if c.Owners == nil {
@@ -472,6 +662,20 @@ func (c *IssueCreator) ExplainTestAssignments(testNames []string) string {
 	return buf.String()
 }
 
+// IsValidLabel returns whether label is one of the labels of the repo. All labels are valid if
+// the labels of the repo could not be retrieved.
+func (c *IssueCreator) IsValidLabel(label string) bool {
+	if c.validLabels == nil {
+		return true
+	}
+	for _, valid := range c.validLabels {
+		if valid == label {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *IssueCreator) isAssignable(login string) bool {
 	if c.Collaborators == nil {
 		return true
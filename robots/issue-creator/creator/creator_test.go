@@ -24,6 +24,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/test-infra/robots/issue-creator/testowner"
 
@@ -38,7 +39,9 @@ type fakeClient struct {
 	issues     []*github.Issue
 	org        string
 	project    string
-	t          *testing.T
+	// comments maps issue numbers to the bodies of the comments created on them.
+	comments map[int][]string
+	t        *testing.T
 }
 
 func (c *fakeClient) GetUser(login string) (*github.User, error) {
@@ -77,16 +80,45 @@ func (c *fakeClient) CreateIssue(org, repo string, title, body string, labels, o
 	return issue, nil
 }
 
+func (c *fakeClient) EditIssue(org, repo string, number int, request *github.IssueRequest) (*github.Issue, error) {
+	if number < 0 || number >= len(c.issues) {
+		return nil, fmt.Errorf("issue #%d not found", number)
+	}
+	issue := c.issues[number]
+	if request.Title != nil {
+		issue.Title = request.Title
+	}
+	if request.Body != nil {
+		issue.Body = request.Body
+	}
+	if request.State != nil {
+		issue.State = request.State
+	}
+	return issue, nil
+}
+
+func (c *fakeClient) CreateComment(org, repo string, number int, body string) (*github.IssueComment, error) {
+	if number < 0 || number >= len(c.issues) {
+		return nil, fmt.Errorf("issue #%d not found", number)
+	}
+	if c.comments == nil {
+		c.comments = make(map[int][]string)
+	}
+	c.comments[number] = append(c.comments[number], body)
+	return &github.IssueComment{Body: &body}, nil
+}
+
 func (c *fakeClient) GetCollaborators(org, repo string) ([]*github.User, error) {
 	return nil, errors.New("some error (allow all assignees)")
 }
 
 // Verify checks that exactly 1 issue in c.issues matches the parameters and that no
 // issues in c.issues have an empty body string (since that means they shouldn't have been created).
+// The state stored in the body of the issues is ignored.
 func (c *fakeClient) Verify(title, body string, owners, labels []string) bool {
 	matchCount := 0
 	for _, issue := range c.issues {
-		if *issue.Title != title || *issue.Body != body {
+		if issueBody, _, _ := parseState(*issue.Body); *issue.Title != title || issueBody != body {
 			continue
 		}
 		// Verify that owners matches Assignees.
@@ -146,6 +178,16 @@ func (i *fakeIssue) Priority() (string, bool) {
 	return i.priority, true
 }
 
+// fakeCountedIssue is a fakeIssue that implements CountedIssue.
+type fakeCountedIssue struct {
+	*fakeIssue
+	count int
+}
+
+func (i *fakeCountedIssue) Count() int {
+	return i.count
+}
+
 func TestIssueCreator(t *testing.T) {
 
 	i1 := &fakeIssue{
@@ -183,14 +225,14 @@ func TestIssueCreator(t *testing.T) {
 		owners:   []string{"user0"},
 		priority: "",
 	}
-	creator.sync(i0)
+	creator.sync("fake-source", i0)
 	if !c.Verify(i0.title, i0.body, i0.owners, i0.labels) {
 		t.Errorf("Failed to do a simple sync of i0\n")
 	}
 
 	// Test that issues can't be double synced.
 	origLen := len(c.issues)
-	creator.sync(i1)
+	creator.sync("fake-source", i1)
 	if len(c.issues) > origLen {
 		t.Errorf("Second sync of i1 created a duplicate issue!\n")
 	}
@@ -208,7 +250,7 @@ func TestIssueCreator(t *testing.T) {
 		priority: "",
 	}
 	origLen = len(c.issues)
-	creator.sync(i2)
+	creator.sync("fake-source", i2)
 	if len(c.issues) > origLen {
 		t.Errorf("sync of i2 with empty body should not have created issue!\n")
 	}
@@ -222,7 +264,7 @@ func TestIssueCreator(t *testing.T) {
 		owners:   []string{"user3"},
 		priority: "",
 	}
-	creator.sync(i3)
+	creator.sync("fake-source", i3)
 	if !c.Verify(i3.title, i3.body, i3.owners, []string{"kind/flake"}) {
 		t.Errorf("sync of i3 was invalid. The label 'label/wannabe' should not be added to the new issue.\n")
 	}
@@ -238,7 +280,7 @@ func TestIssueCreator(t *testing.T) {
 		priority: "",
 	}
 	origLen = len(c.issues)
-	creator.sync(i4)
+	creator.sync("fake-source", i4)
 	if len(c.issues) > origLen {
 		t.Errorf("sync of i4 with DryRun on should not have created issue!\n")
 	}
@@ -254,12 +296,156 @@ func TestIssueCreator(t *testing.T) {
 		owners:   []string{"user5", "user1"}, // Test multiple users and labels here too.
 		priority: "P0",
 	}
-	creator.sync(i5)
+	creator.sync("fake-source", i5)
 	if !c.Verify(i5.title, i5.body, i5.owners, []string{"kind/flake", "kind/flakeypastry", "priority/P0"}) {
 		t.Errorf("sync of i5 was invalid. The labels in the created issue were incorrect.\n")
 	}
 }
 
+func TestIssueCreatorUpdate(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	earlier := now.AddDate(0, 0, -2)
+	i1 := &fakeCountedIssue{
+		fakeIssue: &fakeIssue{
+			title:  "title1 failed 10 times",
+			body:   "body<ID1>",
+			id:     "<ID1>",
+			labels: []string{"kind/flake"},
+		},
+		count: 10,
+	}
+	oldState := issueState{Source: "fake-source", LastReported: earlier, Counted: &countRecord{Count: 10, At: earlier}}
+
+	c := &fakeClient{
+		t:          t,
+		userName:   "BOT_USERNAME",
+		repoLabels: []string{"kind/flake"},
+		issues: []*github.Issue{
+			makeTestIssue(i1.title, withState(i1.body, oldState), "open", i1.labels, nil, 0),
+		},
+	}
+	creator := &IssueCreator{
+		client:             c,
+		commentChangeRatio: 0.5,
+		now:                func() time.Time { return now },
+	}
+	if err := creator.loadCache(); err != nil {
+		t.Fatalf("IssueCreator failed to load data from github while initing: %v", err)
+	}
+
+	// Test that an insignificant change updates the issue without commenting on it.
+	i1.title, i1.body, i1.count = "title1 failed 14 times", "newbody<ID1>", 14
+	if creator.sync("fake-source", i1) {
+		t.Errorf("sync of open issue i1 should not have created an issue!\n")
+	}
+	if len(c.issues) != 1 {
+		t.Errorf("sync of open issue i1 created a duplicate issue!\n")
+	}
+	want := withState(i1.body, issueState{Source: "fake-source", LastReported: now, Counted: oldState.Counted})
+	if *c.issues[0].Title != i1.title || *c.issues[0].Body != want {
+		t.Errorf("Expected i1 to be updated to %q with body %q, but got %q with body %q.\n", i1.title, want, *c.issues[0].Title, *c.issues[0].Body)
+	}
+	if len(c.comments[0]) != 0 {
+		t.Errorf("Expected no comment for an insignificant change, but got %q.\n", c.comments[0])
+	}
+
+	// Test that a significant change is commented on and becomes the new baseline.
+	i1.title, i1.count = "title1 failed 15 times", 15
+	creator.sync("fake-source", i1)
+	if len(c.comments[0]) != 1 || !strings.Contains(c.comments[0][0], "from 10 to 15") {
+		t.Errorf("Expected a comment on the change from 10 to 15, but got %q.\n", c.comments[0])
+	}
+	want = withState(i1.body, issueState{Source: "fake-source", LastReported: now, Counted: &countRecord{Count: 15, At: now}})
+	if *c.issues[0].Body != want {
+		t.Errorf("Expected i1 to have body %q, but got %q.\n", want, *c.issues[0].Body)
+	}
+
+	// Test that an issue that declines to provide a body only has its state refreshed.
+	now = now.Add(time.Hour)
+	title := i1.title
+	i1.title, i1.body = "title1 ignored", ""
+	creator.sync("fake-source", i1)
+	want = withState("newbody<ID1>", issueState{Source: "fake-source", LastReported: now, Counted: &countRecord{Count: 15, At: now.Add(-time.Hour)}})
+	if *c.issues[0].Title != title || *c.issues[0].Body != want {
+		t.Errorf("Expected i1 to keep title %q with body %q, but got %q with body %q.\n", title, want, *c.issues[0].Title, *c.issues[0].Body)
+	}
+
+	// Test that DryRun prevents updates.
+	creator.dryRun = true
+	i1.title, i1.body, i1.count = "title1 failed 100 times", "dry<ID1>", 100
+	creator.sync("fake-source", i1)
+	if *c.issues[0].Title != title || len(c.comments[0]) != 1 {
+		t.Errorf("sync of i1 with DryRun on should not have updated or commented on the issue!\n")
+	}
+}
+
+func TestIssueCreatorCloseStale(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	reported := func(source string, days int) issueState {
+		return issueState{Source: source, LastReported: now.AddDate(0, 0, -days)}
+	}
+	i0 := &fakeIssue{title: "title0", body: "body<ID0>", id: "<ID0>"}
+
+	c := &fakeClient{
+		t:        t,
+		userName: "BOT_USERNAME",
+		issues: []*github.Issue{
+			// Still reported by the source.
+			makeTestIssue("title0", withState("body<ID0>", reported("fake-source", 10)), "open", nil, nil, 0),
+			// No longer reported for too long.
+			makeTestIssue("title1", withState("body<ID1>", reported("fake-source", 8)), "open", nil, nil, 1),
+			// No longer reported, but recently.
+			makeTestIssue("title2", withState("body<ID2>", reported("fake-source", 3)), "open", nil, nil, 2),
+			// Reported by another source.
+			makeTestIssue("title3", withState("body<ID3>", reported("other-source", 30)), "open", nil, nil, 3),
+			// Created before issue states.
+			makeTestIssue("title4", "body<ID4>", "open", nil, nil, 4),
+			// Already closed.
+			makeTestIssue("title5", withState("body<ID5>", reported("fake-source", 30)), "closed", nil, nil, 5),
+		},
+	}
+	creator := &IssueCreator{
+		client:         c,
+		closeAfterDays: 7,
+		now:            func() time.Time { return now },
+	}
+	if err := creator.loadCache(); err != nil {
+		t.Fatalf("IssueCreator failed to load data from github while initing: %v", err)
+	}
+
+	// Test that DryRun prevents closing issues.
+	creator.dryRun = true
+	if closed := creator.closeStale("fake-source", []Issue{i0}); closed != 1 {
+		t.Errorf("Expected closeStale with DryRun on to report 1 issue to close, but got %d.\n", closed)
+	}
+	if *c.issues[1].State != "open" || len(c.comments) != 0 {
+		t.Errorf("closeStale with DryRun on should not have closed or commented on an issue!\n")
+	}
+
+	creator.dryRun = false
+	if closed := creator.closeStale("fake-source", []Issue{i0}); closed != 1 {
+		t.Errorf("Expected closeStale to close 1 issue, but closed %d.\n", closed)
+	}
+	for i, issue := range c.issues {
+		wantState := "open"
+		if i == 1 || i == 5 {
+			wantState = "closed"
+		}
+		if *issue.State != wantState {
+			t.Errorf("Expected issue #%d to be %s, but it is %s.\n", i, wantState, *issue.State)
+		}
+	}
+	if len(c.comments) != 1 || len(c.comments[1]) != 1 || !strings.Contains(c.comments[1][0], "fake-source has not reported it") {
+		t.Errorf("Expected exactly one comment explaining why issue #1 was closed, but got %q.\n", c.comments)
+	}
+
+	// Test that closing can be disabled.
+	creator.closeAfterDays = 0
+	if closed := creator.closeStale("fake-source", nil); closed != 0 {
+		t.Errorf("Expected closeStale to close no issues when disabled, but closed %d.\n", closed)
+	}
+}
+
 func makeTestIssue(title, body, state string, labels, owners []string, number int) *github.Issue {
 	return &github.Issue{
 		Title:     &title,
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package creator
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	// stateMarker starts the hidden HTML comment holding the issueState of a github issue.
+	stateMarker = "<!-- issue-creator-state: "
+	// stateMarkerEnd ends the hidden HTML comment holding the issueState of a github issue.
+	stateMarkerEnd = " -->"
+)
+
+// issueState is what the IssueCreator remembers about a github issue between runs. It is stored in
+// a hidden HTML comment at the end of the body of the issue.
+type issueState struct {
+	// Source is the name of the IssueSource that reported the issue.
+	Source string `json:"source"`
+	// LastReported is the last time the source reported the issue.
+	LastReported time.Time `json:"last_reported"`
+	// Counted is the count of a CountedIssue when the github issue was created or last commented on
+	// about a change of the count.
+	Counted *countRecord `json:"counted,omitempty"`
+}
+
+// countRecord is the count of a CountedIssue at some time.
+type countRecord struct {
+	Count int       `json:"count"`
+	At    time.Time `json:"at"`
+}
+
+// withState returns body with state appended in a hidden HTML comment.
+func withState(body string, state issueState) string {
+	b, err := json.Marshal(state)
+	if err != nil {
+		glog.Fatalf("Programmer error: failed to marshal issue state %v: %v.", state, err)
+	}
+	return body + "\n\n" + stateMarker + string(b) + stateMarkerEnd
+}
+
+// parseState splits the body of a github issue into the body text from its Issue and its issueState.
+// The returned bool is false if the body holds no valid state, in which case the body is returned as is.
+func parseState(body string) (string, issueState, bool) {
+	var state issueState
+	start := strings.LastIndex(body, stateMarker)
+	if start < 0 {
+		return body, state, false
+	}
+	end := strings.Index(body[start:], stateMarkerEnd)
+	if end < 0 {
+		return body, state, false
+	}
+	if err := json.Unmarshal([]byte(body[start+len(stateMarker):start+end]), &state); err != nil {
+		glog.Warningf("Ignoring invalid issue state in issue body: %v.", err)
+		return body, state, false
+	}
+	return strings.TrimSuffix(body[:start], "\n\n"), state, true
+}
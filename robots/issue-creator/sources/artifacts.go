@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	pkgio "sigs.k8s.io/prow/pkg/io"
+)
+
+// errNotFound is returned by artifactStore.read when the artifact does not exist.
+var errNotFound = errors.New("artifact not found")
+
+// artifactStore reads a tree of prow job artifacts, such as a GCS bucket, with a pkg/io Opener.
+// Paths are relative to the root of the tree and separated by '/'.
+type artifactStore struct {
+	opener pkgio.Opener
+	// root is the storage path of the tree, such as gs://bucket/logs or file:///var/logs.
+	root string
+}
+
+// artifactRoot returns the storage path understood by a pkg/io Opener for location, which is a
+// gs://bucket/path or s3://bucket/path URL, or a local directory.
+func artifactRoot(location string) (string, error) {
+	for _, scheme := range []string{"gs", "s3"} {
+		if !strings.HasPrefix(location, scheme+"://") {
+			continue
+		}
+		bucket, _, _ := strings.Cut(strings.TrimPrefix(location, scheme+"://"), "/")
+		if bucket == "" {
+			return "", fmt.Errorf("no bucket in artifact location '%s'", location)
+		}
+		return strings.TrimRight(location, "/"), nil
+	}
+	if strings.Contains(location, "://") {
+		return "", fmt.Errorf("unsupported artifact location '%s', expected gs://, s3:// or a local directory", location)
+	}
+	dir, err := filepath.Abs(location)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve artifact location '%s': %w", location, err)
+	}
+	return "file://" + filepath.ToSlash(dir), nil
+}
+
+// newArtifactStore returns the artifactStore for location, as accepted by artifactRoot, which
+// reads buckets with the credentials in the given files, or anonymously if they are "".
+func newArtifactStore(ctx context.Context, location, gcsCredentialsFile, s3CredentialsFile string) (*artifactStore, error) {
+	root, err := artifactRoot(location)
+	if err != nil {
+		return nil, err
+	}
+	opener, err := pkgio.NewOpener(ctx, gcsCredentialsFile, s3CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the opener of '%s': %w", location, err)
+	}
+	return &artifactStore{opener: opener, root: root}, nil
+}
+
+// path returns the storage path of the artifact at p. path.Join cannot be used on the whole
+// path, since it would collapse the slashes that follow the scheme.
+func (s *artifactStore) path(p string) string {
+	if p = path.Clean("/" + p); p == "/" {
+		return s.root
+	}
+	return s.root + p
+}
+
+// listDirs returns the names of the directories directly under dir.
+func (s *artifactStore) listDirs(ctx context.Context, dir string) ([]string, error) {
+	prefix := s.path(dir) + "/"
+	it, err := s.opener.Iterator(ctx, prefix, "/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list '%s': %w", prefix, err)
+	}
+	var dirs []string
+	for {
+		attrs, err := it.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return dirs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list '%s': %w", prefix, err)
+		}
+		if attrs.IsDir {
+			dirs = append(dirs, path.Base(attrs.Name))
+		}
+	}
+}
+
+// read returns the contents of the artifact at file, or errNotFound if it does not exist.
+func (s *artifactStore) read(ctx context.Context, file string) ([]byte, error) {
+	reader, err := s.opener.Reader(ctx, s.path(file))
+	if pkgio.IsNotExist(err) {
+		return nil, errNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %w", s.path(file), err)
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// viewURL returns the URL of the page under base that shows the build in dir, or "" if there is
+// none, as for a local directory.
+func (s *artifactStore) viewURL(base, dir string) string {
+	scheme, rest, _ := strings.Cut(s.path(dir), "://")
+	if scheme != "gs" && scheme != "s3" {
+		return ""
+	}
+	return fmt.Sprintf("%s%s/%s", base, scheme, rest)
+}
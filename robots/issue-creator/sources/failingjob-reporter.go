@@ -0,0 +1,449 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"sigs.k8s.io/yaml"
+
+	githubapi "github.com/google/go-github/github"
+	"k8s.io/test-infra/robots/issue-creator/creator"
+)
+
+const (
+	// dashboardsAnnotation is the prow job annotation that lists the testgrid dashboards of a job.
+	dashboardsAnnotation = "testgrid-dashboards"
+	// listedFailuresCount is the maximum number of failed builds listed in the body of an issue.
+	listedFailuresCount = 10
+	// scanWorkers is the number of jobs whose builds are read concurrently.
+	scanWorkers = 16
+)
+
+// FailedBuild is a failed build of a periodic job.
+type FailedBuild struct {
+	// ID is the build ID.
+	ID string
+	// Finished is when the build finished.
+	Finished time.Time
+	// URL is the link to the build, or "" if there is none.
+	URL string
+}
+
+// FailingJob is a periodic job that failed several times in a row.
+// FailingJob implements the Issue interface so that it can be synced with github issues via the IssueCreator.
+type FailingJob struct {
+	// Name is the job's name.
+	Name string
+	// Dashboards are the testgrid dashboards of the job.
+	Dashboards []string
+	// Failures are the consecutive failed builds of the job, latest first.
+	Failures []FailedBuild
+	// Truncated is true if the job failed in every build that was read, so that it may have failed
+	// more times in a row than there are Failures.
+	Truncated bool
+
+	// reporter is a pointer to the FailingJobReporter that created this FailingJob.
+	reporter *FailingJobReporter
+}
+
+// FailingJobReporter is a munger that creates github issues for periodic jobs that failed several
+// times in a row. The results of the builds are read from the finished.json files of their prow
+// artifacts, and the jobs from prow job configs.
+type FailingJobReporter struct {
+	jobConfigPath      string
+	artifacts          string
+	gcsCredentialsFile string
+	s3CredentialsFile  string
+	viewURL            string
+	threshold          int
+	maxBuilds          int
+	syncCount          int
+
+	creator *creator.IssueCreator
+	store   *artifactStore
+}
+
+func init() {
+	creator.RegisterSourceOrDie("failingjob-reporter", &FailingJobReporter{})
+}
+
+// RegisterFlags registers options for this munger; returns any that require a restart when changed.
+func (fjr *FailingJobReporter) RegisterFlags() {
+	flag.StringVar(&fjr.jobConfigPath, "failingjob-config", "", "The prow job config file or directory of the periodic jobs to check. The source is disabled if this is not set.")
+	flag.StringVar(&fjr.artifacts, "failingjob-artifacts", "gs://kubernetes-jenkins/logs", "The gs:// or s3:// URL of a bucket, or the local directory, holding the artifacts of each periodic job in a directory named after it.")
+	flag.StringVar(&fjr.gcsCredentialsFile, "failingjob-gcs-credentials-file", "", "The GCS credentials file used to read a gs:// bucket, which is read anonymously if this is not set.")
+	flag.StringVar(&fjr.s3CredentialsFile, "failingjob-s3-credentials-file", "", "The S3 credentials file used to read an s3:// bucket.")
+	flag.StringVar(&fjr.viewURL, "failingjob-view-url", "https://prow.k8s.io/view/", "The URL of the prow page that shows builds, which the bucket and path of a build are appended to.")
+	flag.IntVar(&fjr.threshold, "failingjob-threshold", 3, "The number of consecutive failures after which an issue is filed for a periodic job.")
+	flag.IntVar(&fjr.maxBuilds, "failingjob-max-builds", 50, "The maximum number of finished builds of each periodic job to read.")
+	flag.IntVar(&fjr.syncCount, "failingjob-count", 10, "The number of failing jobs to try to sync to github.")
+}
+
+// Issues is the main work method of FailingJobReporter. It reads the periodic jobs and the results
+// of their latest builds, then returns the jobs that are failing, most consecutive failures first.
+func (fjr *FailingJobReporter) Issues(c *creator.IssueCreator) ([]creator.Issue, error) {
+	fjr.creator = c
+	if fjr.jobConfigPath == "" {
+		return nil, creator.ErrSourceDisabled
+	}
+	dashboards, err := loadPeriodics(fjr.jobConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	if fjr.store, err = newArtifactStore(ctx, fjr.artifacts, fjr.gcsCredentialsFile, fjr.s3CredentialsFile); err != nil {
+		return nil, err
+	}
+
+	failingJobs, err := fjr.scan(ctx, dashboards)
+	if err != nil {
+		return nil, err
+	}
+
+	count := fjr.syncCount
+	if len(failingJobs) < count {
+		count = len(failingJobs)
+	}
+	issues := make([]creator.Issue, 0, count)
+	for _, fj := range failingJobs[0:count] {
+		issues = append(issues, fj)
+	}
+	return issues, nil
+}
+
+// jobConfig is the part of a prow job config that FailingJobReporter reads.
+type jobConfig struct {
+	Periodics []struct {
+		Name        string            `json:"name"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"periodics"`
+}
+
+// loadPeriodics reads the periodic jobs from the prow job config file at configPath, or from the
+// .yaml files in the directory at configPath and its subdirectories.
+// It returns a map from the names of the jobs to their testgrid dashboards.
+func loadPeriodics(configPath string) (map[string][]string, error) {
+	var files []string
+	err := filepath.WalkDir(configPath, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && (p == configPath || strings.HasSuffix(p, ".yaml") || strings.HasSuffix(p, ".yml")) {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find job configs in '%s': %w", configPath, err)
+	}
+
+	periodics := make(map[string][]string)
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read job config '%s': %w", file, err)
+		}
+		var config jobConfig
+		if err := yaml.Unmarshal(b, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse job config '%s': %w", file, err)
+		}
+		for _, job := range config.Periodics {
+			var dashboards []string
+			for _, dashboard := range strings.Split(job.Annotations[dashboardsAnnotation], ",") {
+				if dashboard = strings.TrimSpace(dashboard); dashboard != "" {
+					dashboards = append(dashboards, dashboard)
+				}
+			}
+			periodics[job.Name] = dashboards
+		}
+	}
+	return periodics, nil
+}
+
+// finished is the part of the finished.json file of a build that FailingJobReporter reads.
+type finished struct {
+	Timestamp int64 `json:"timestamp"`
+	// Passed is set by newer versions of prow, Result by older ones.
+	Passed *bool  `json:"passed"`
+	Result string `json:"result"`
+}
+
+func (f finished) passed() bool {
+	if f.Passed != nil {
+		return *f.Passed
+	}
+	return f.Result == "SUCCESS"
+}
+
+// scan reads the latest builds of each of the jobs and returns those that failed at least threshold
+// times in a row, sorted by the number of failures in descending order.
+func (fjr *FailingJobReporter) scan(ctx context.Context, dashboards map[string][]string) ([]*FailingJob, error) {
+	names := make([]string, 0, len(dashboards))
+	for name := range dashboards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var (
+		lock        sync.Mutex
+		wg          sync.WaitGroup
+		failingJobs []*FailingJob
+		errs        []error
+	)
+	queue := make(chan string)
+	for i := 0; i < scanWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range queue {
+				fj, err := fjr.readJob(ctx, name)
+				lock.Lock()
+				if err != nil {
+					errs = append(errs, err)
+				} else if len(fj.Failures) > 0 && len(fj.Failures) >= fjr.threshold {
+					fj.Dashboards = dashboards[name]
+					failingJobs = append(failingJobs, fj)
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	for _, name := range names {
+		queue <- name
+	}
+	close(queue)
+	wg.Wait()
+
+	if len(errs) > 0 {
+		// Report the failing jobs that could be read, as long as some could.
+		for _, err := range errs {
+			glog.Errorf("Failed to read the builds of a periodic job: %v.", err)
+		}
+		if len(errs) == len(names) {
+			return nil, fmt.Errorf("failed to read the builds of all %d periodic jobs: %w", len(names), errors.Join(errs...))
+		}
+	}
+
+	sort.Slice(failingJobs, func(i, j int) bool {
+		if len(failingJobs[i].Failures) == len(failingJobs[j].Failures) {
+			return failingJobs[i].Name < failingJobs[j].Name
+		}
+		return len(failingJobs[i].Failures) > len(failingJobs[j].Failures)
+	})
+	return failingJobs, nil
+}
+
+// readJob reads the latest builds of the job with the given name, up to its latest passing build or
+// maxBuilds finished builds, and returns the job with its consecutive failures. Builds that have not
+// finished are skipped.
+func (fjr *FailingJobReporter) readJob(ctx context.Context, name string) (*FailingJob, error) {
+	fj := &FailingJob{Name: name, reporter: fjr}
+	dirs, err := fjr.store.listDirs(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the builds of job '%s': %w", name, err)
+	}
+
+	// Build IDs increase over time, so the latest build has the greatest ID.
+	type buildID struct {
+		id     string
+		number uint64
+	}
+	var builds []buildID
+	for _, dir := range dirs {
+		if number, err := strconv.ParseUint(dir, 10, 64); err == nil {
+			builds = append(builds, buildID{dir, number})
+		}
+	}
+	sort.Slice(builds, func(i, j int) bool { return builds[i].number > builds[j].number })
+
+	read := 0
+	for _, build := range builds {
+		if read >= fjr.maxBuilds {
+			fj.Truncated = true
+			break
+		}
+		dir := path.Join(name, build.id)
+		b, err := fjr.store.read(ctx, path.Join(dir, "finished.json"))
+		if errors.Is(err, errNotFound) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read the result of build '%s': %w", dir, err)
+		}
+		var result finished
+		if err := json.Unmarshal(b, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse the result of build '%s': %w", dir, err)
+		}
+		read++
+		if result.passed() {
+			break
+		}
+		fj.Failures = append(fj.Failures, FailedBuild{
+			ID:       build.id,
+			Finished: time.Unix(result.Timestamp, 0),
+			URL:      fjr.store.viewURL(fjr.viewURL, dir),
+		})
+	}
+	return fj, nil
+}
+
+// Title yields the initial title text of the github issue.
+func (fj *FailingJob) Title() string {
+	if fj.Truncated {
+		return fmt.Sprintf("[Failing Test] %s failed at least %d times in a row", fj.Name, len(fj.Failures))
+	}
+	return fmt.Sprintf("[Failing Test] %s failed %d times in a row", fj.Name, len(fj.Failures))
+}
+
+// ID yields the string identifier that uniquely identifies this issue.
+// This ID must appear in the body of the issue.
+// DO NOT CHANGE how this ID is formatted or duplicate issues may be created on github.
+func (fj *FailingJob) ID() string {
+	return fmt.Sprintf("Failing Periodic Job: %s", fj.Name)
+}
+
+// Count returns the number of times the job failed in a row.
+func (fj *FailingJob) Count() int {
+	return len(fj.Failures)
+}
+
+// firstFailure returns the earliest of the consecutive failed builds of the job.
+func (fj *FailingJob) firstFailure() FailedBuild {
+	return fj.Failures[len(fj.Failures)-1]
+}
+
+// SIGs returns the names of the SIGs that own the job, according to the names of its testgrid
+// dashboards. The SIG of a dashboard named "sig-<name>-<suffix>" is the shortest prefix of
+// "<name>-<suffix>" that is the name of a sig label of the repo, such as "api-machinery" for
+// "sig-api-machinery-gce".
+func (fj *FailingJob) SIGs() []string {
+	var sigs []string
+	seen := make(map[string]bool)
+	for _, dashboard := range fj.Dashboards {
+		if !strings.HasPrefix(dashboard, "sig-") {
+			continue
+		}
+		parts := strings.Split(strings.TrimPrefix(dashboard, "sig-"), "-")
+		for i := 1; i <= len(parts); i++ {
+			sig := strings.Join(parts[:i], "-")
+			if !fj.reporter.creator.IsValidLabel("sig/" + sig) {
+				continue
+			}
+			if !seen[sig] {
+				seen[sig] = true
+				sigs = append(sigs, sig)
+			}
+			break
+		}
+	}
+	return sigs
+}
+
+// Body returns the body text of the github issue and *must* contain the output of ID().
+// closedIssues is a (potentially empty) slice containing all closed issues authored by this bot
+// that contain ID() in their body.
+// If Body returns an empty string no issue is created.
+func (fj *FailingJob) Body(closedIssues []*githubapi.Issue) string {
+	// Don't reopen an issue that was closed after the job started failing, since someone already
+	// looked into these failures.
+	first := fj.firstFailure()
+	for _, closed := range closedIssues {
+		if closed.ClosedAt != nil && closed.ClosedAt.After(first.Finished) {
+			return ""
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "### %s\n", fj.ID())
+	atLeast := ""
+	if fj.Truncated {
+		atLeast = "at least "
+	}
+	fmt.Fprintf(&buf, "Failed %s**%d** times in a row.\n", atLeast, len(fj.Failures))
+	fmt.Fprintf(&buf, "First failing build: %s at %s\n", buildLink(first), first.Finished.UTC().Format(timeFormat))
+	if sigs := fj.SIGs(); len(sigs) > 0 {
+		fmt.Fprintf(&buf, "Owning SIGs: %s\n", strings.Join(sigs, ", "))
+	}
+	if len(fj.Dashboards) > 0 {
+		fmt.Fprintf(&buf, "Testgrid dashboards: %s\n", strings.Join(fj.Dashboards, ", "))
+	}
+
+	fmt.Fprint(&buf, "\n#### Failing builds:\n| Build | Finished |\n| --- | --- |\n")
+	for i, build := range fj.Failures {
+		if i == listedFailuresCount {
+			fmt.Fprintf(&buf, "\nand %d more.\n", len(fj.Failures)-listedFailuresCount)
+			break
+		}
+		fmt.Fprintf(&buf, "| %s | %s |\n", buildLink(build), build.Finished.UTC().Format(timeFormat))
+	}
+
+	// List previously closed issues if there are any.
+	if len(closedIssues) > 0 {
+		fmt.Fprint(&buf, "\n#### Previously closed issues for this job failing:\n")
+		for _, closed := range closedIssues {
+			fmt.Fprintf(&buf, "#%d ", *closed.Number)
+		}
+		fmt.Fprint(&buf, "\n")
+	}
+
+	fmt.Fprintf(&buf, "\n/kind failing-test\n")
+
+	return buf.String()
+}
+
+// buildLink returns a markdown link to build, or its ID if it has no link.
+func buildLink(build FailedBuild) string {
+	if build.URL == "" {
+		return build.ID
+	}
+	return fmt.Sprintf("[%s](%s)", build.ID, build.URL)
+}
+
+// Labels returns the labels to apply to the issue created for this failing job on github.
+func (fj *FailingJob) Labels() []string {
+	labels := []string{"kind/failing-test"}
+	for _, sig := range fj.SIGs() {
+		labels = append(labels, "sig/"+sig)
+	}
+	return labels
+}
+
+// Owners returns the list of usernames to assign to this issue on github.
+func (fj *FailingJob) Owners() []string {
+	// Periodic jobs are owned by SIGs, which are labeled, rather than by users.
+	return nil
+}
+
+// Priority calculates and returns the priority of this issue
+// The returned bool indicates if the returned priority is valid and can be used
+func (fj *FailingJob) Priority() (string, bool) {
+	return "", false
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sources
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/test-infra/robots/issue-creator/creator"
+
+	githubapi "github.com/google/go-github/github"
+)
+
+var sampleJobConfig = []byte(`
+periodics:
+- name: ci-failing
+  interval: 1h
+  annotations:
+    testgrid-dashboards: sig-node-release-blocking, sig-node-kubelet
+- name: ci-flaky
+  interval: 1h
+- name: ci-always-failing
+  interval: 1h
+  annotations:
+    testgrid-dashboards: google-gce
+presubmits:
+  kubernetes/kubernetes:
+  - name: pull-not-periodic
+`)
+
+// writeBuild writes the finished.json of a build under dir, or no finished.json if result is "".
+func writeBuild(t *testing.T, dir, job string, build int, result string) {
+	buildDir := filepath.Join(dir, job, fmt.Sprintf("%d", build))
+	if err := os.MkdirAll(buildDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if result == "" {
+		return
+	}
+	finished := fmt.Sprintf(`{"timestamp": %d, "result": %q}`, 1500000000+build, result)
+	if err := os.WriteFile(filepath.Join(buildDir, "finished.json"), []byte(finished), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// NewTestFailingJobReporter creates a FailingJobReporter that reads a local artifact tree and job
+// config, and isn't connected to an IssueCreator, so that it can be used for testing.
+func NewTestFailingJobReporter(t *testing.T) *FailingJobReporter {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config", "jobs.yaml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, sampleJobConfig, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := filepath.Join(dir, "logs")
+	// ci-failing passed, then failed 4 times, and is running again.
+	writeBuild(t, logs, "ci-failing", 10, "SUCCESS")
+	for build := 11; build <= 14; build++ {
+		writeBuild(t, logs, "ci-failing", build, "FAILURE")
+	}
+	writeBuild(t, logs, "ci-failing", 15, "")
+	// ci-flaky failed only twice in a row.
+	writeBuild(t, logs, "ci-flaky", 1, "FAILURE")
+	writeBuild(t, logs, "ci-flaky", 2, "SUCCESS")
+	writeBuild(t, logs, "ci-flaky", 3, "FAILURE")
+	writeBuild(t, logs, "ci-flaky", 4, "FAILURE")
+	// ci-always-failing failed in more builds than are read.
+	for build := 1; build <= 8; build++ {
+		writeBuild(t, logs, "ci-always-failing", build, "FAILURE")
+	}
+
+	return &FailingJobReporter{
+		jobConfigPath: filepath.Join(dir, "config"),
+		artifacts:     logs,
+		threshold:     3,
+		maxBuilds:     5,
+		syncCount:     10,
+		creator:       &creator.IssueCreator{},
+	}
+}
+
+func TestFailingJobIssues(t *testing.T) {
+	fjr := NewTestFailingJobReporter(t)
+	issues, err := fjr.Issues(&creator.IssueCreator{})
+	if err != nil {
+		t.Fatalf("Unexpected error reading failing jobs: %v.", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 failing jobs, got %d.", len(issues))
+	}
+
+	always := issues[0].(*FailingJob)
+	if always.Name != "ci-always-failing" || len(always.Failures) != 5 || !always.Truncated {
+		t.Errorf("Expected ci-always-failing to fail at least 5 times first, got %s failing %d times (truncated: %t).", always.Name, len(always.Failures), always.Truncated)
+	}
+	if title := always.Title(); !strings.Contains(title, "at least 5 times") {
+		t.Errorf("Expected the title of a truncated job to say 'at least', got %q.", title)
+	}
+
+	failing := issues[1].(*FailingJob)
+	if failing.Name != "ci-failing" || failing.Truncated {
+		t.Fatalf("Expected ci-failing to be the second failing job, got %s (truncated: %t).", failing.Name, failing.Truncated)
+	}
+	var ids []string
+	for _, build := range failing.Failures {
+		ids = append(ids, build.ID)
+	}
+	if expected := []string{"14", "13", "12", "11"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected the failed builds of ci-failing to be %q, got %q.", expected, ids)
+	}
+	if first := failing.firstFailure(); !first.Finished.Equal(time.Unix(1500000011, 0)) {
+		t.Errorf("Expected the first failure of ci-failing to finish at %v, got %v.", time.Unix(1500000011, 0), first.Finished)
+	}
+	if failing.Count() != 4 {
+		t.Errorf("Expected ci-failing to count 4 failures, got %d.", failing.Count())
+	}
+	if expected := []string{"sig-node-release-blocking", "sig-node-kubelet"}; !reflect.DeepEqual(failing.Dashboards, expected) {
+		t.Errorf("Expected the dashboards of ci-failing to be %q, got %q.", expected, failing.Dashboards)
+	}
+	if expected := []string{"kind/failing-test", "sig/node"}; !reflect.DeepEqual(failing.Labels(), expected) {
+		t.Errorf("Expected the labels of ci-failing to be %q, got %q.", expected, failing.Labels())
+	}
+
+	body := failing.Body(nil)
+	for _, expected := range []string{failing.ID(), "**4** times in a row", "First failing build: 11", "Owning SIGs: node", "| 14 |"} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected the body of ci-failing to contain %q, but it is:\n%s", expected, body)
+		}
+	}
+
+	// Issues closed before the job started failing are listed, but issues closed since then prevent
+	// a new issue.
+	number := 7
+	closedAt := time.Unix(1500000000, 0)
+	closed := []*githubapi.Issue{{Number: &number, ClosedAt: &closedAt}}
+	if body := failing.Body(closed); !strings.Contains(body, "#7") {
+		t.Errorf("Expected the body of ci-failing to list the previously closed issue #7, but it is:\n%s", body)
+	}
+	closedAt = time.Unix(1500000012, 0)
+	if body := failing.Body(closed); body != "" {
+		t.Errorf("Expected an empty body for ci-failing since an issue was closed after it started failing, got:\n%s", body)
+	}
+}
+
+func TestFailingJobDisabled(t *testing.T) {
+	fjr := &FailingJobReporter{}
+	if _, err := fjr.Issues(&creator.IssueCreator{}); !errors.Is(err, creator.ErrSourceDisabled) {
+		t.Errorf("Expected FailingJobReporter without a job config to be disabled, got error %v.", err)
+	}
+}
+
+func TestArtifactRoot(t *testing.T) {
+	abs, err := filepath.Abs("logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		location string
+		expected string
+		err      bool
+	}{
+		{
+			location: "gs://kubernetes-jenkins/logs/",
+			expected: "gs://kubernetes-jenkins/logs",
+		},
+		{
+			location: "s3://bucket",
+			expected: "s3://bucket",
+		},
+		{
+			location: "/var/logs",
+			expected: "file:///var/logs",
+		},
+		{
+			location: "logs",
+			expected: "file://" + filepath.ToSlash(abs),
+		},
+		{
+			location: "gs://",
+			err:      true,
+		},
+		{
+			location: "https://example.com/logs",
+			err:      true,
+		},
+	}
+	for _, tc := range cases {
+		root, err := artifactRoot(tc.location)
+		if (err != nil) != tc.err {
+			t.Errorf("artifactRoot(%q) returned error %v, expected an error: %t.", tc.location, err, tc.err)
+		}
+		if root != tc.expected {
+			t.Errorf("artifactRoot(%q) = %q, expected %q.", tc.location, root, tc.expected)
+		}
+	}
+}
+
+func TestArtifactStoreViewURL(t *testing.T) {
+	cases := map[string]string{
+		"gs://bucket/logs": "https://prow.k8s.io/view/gs/bucket/logs/job/1",
+		"s3://bucket":      "https://prow.k8s.io/view/s3/bucket/job/1",
+		"file:///var/logs": "",
+	}
+	for root, expected := range cases {
+		store := &artifactStore{root: root}
+		if url := store.viewURL("https://prow.k8s.io/view/", "job/1"); url != expected {
+			t.Errorf("Expected the view URL of a build in %s to be %q, got %q.", root, expected, url)
+		}
+	}
+}
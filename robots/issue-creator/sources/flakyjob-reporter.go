@@ -166,6 +166,11 @@ func (fj *FlakyJob) Title() string {
 	return fmt.Sprintf("%s flaked %d times in the past week", fj.Name, *fj.FlakeCount)
 }
 
+// Count returns the number of times the job flaked in the past week.
+func (fj *FlakyJob) Count() int {
+	return *fj.FlakeCount
+}
+
 // ID yields the string identifier that uniquely identifies this issue.
 // This ID must appear in the body of the issue.
 // DO NOT CHANGE how this ID is formatted or duplicate issues may be created on github.
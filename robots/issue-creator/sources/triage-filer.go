@@ -370,6 +370,11 @@ func (c *Cluster) Title() string {
 	)
 }
 
+// Count returns the number of builds the cluster failed in.
+func (c *Cluster) Count() int {
+	return c.totalBuilds
+}
+
 // Body returns the body text of the github issue and *must* contain the output of ID().
 // closedIssues is a (potentially empty) slice containing all closed issues authored by this bot
 // that contain ID() in their body.
@@ -37,9 +37,9 @@ import (
 	"k8s.io/test-infra/triage/utils"
 )
 
-// Dist takes two strings and returns the edit distance between them.
-// It is the only name exported from this package. If limit is 0, the
-// limit is len(a)+len(b).
+// Dist takes two strings and returns the edit distance between them. If
+// limit is 0, the limit is len(a)+len(b). To compare one string to many,
+// use a Matcher instead.
 func Dist(a string, b string, limit int) int {
 	br := berghelRoach{pattern: a}
 
@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package berghelroach
+
+// minBitParallelLength is the pattern length from which a Matcher uses the
+// bit-parallel backend. Below it, the pattern doesn't fill a block, and
+// preprocessing it doesn't pay off. See BenchmarkMatcher.
+const minBitParallelLength = wordSize
+
+// Matcher computes the edit distances between one pattern and many targets.
+// The pattern is preprocessed once, so that comparing it to each target is
+// cheaper than calling Dist. A Matcher is safe for concurrent use.
+type Matcher struct {
+	pattern string
+	myers   *myersPattern
+}
+
+// NewMatcher creates a Matcher for pattern.
+func NewMatcher(pattern string) *Matcher {
+	m := &Matcher{pattern: pattern}
+	if len(pattern) >= minBitParallelLength {
+		m.myers = newMyersPattern(pattern)
+	}
+	return m
+}
+
+// Dist returns the edit distance between the pattern and target. If it is
+// more than limit, Dist stops early and returns some value greater than
+// limit. If limit is 0, the limit is len(pattern)+len(target), as in Dist.
+func (m *Matcher) Dist(target string, limit int) int {
+	if limit == 0 {
+		limit = len(m.pattern) + len(target)
+	}
+	if m.myers != nil {
+		return m.myers.dist(target, limit)
+	}
+	br := berghelRoach{pattern: m.pattern}
+	return br.getDistance(target, limit)
+}
+
+// DistAll returns the edit distance between the pattern and each of targets,
+// with the same limit for each, as Dist does.
+func (m *Matcher) DistAll(targets []string, limit int) []int {
+	dists := make([]int, len(targets))
+	var br *berghelRoach
+	for i, target := range targets {
+		l := limit
+		if l == 0 {
+			l = len(m.pattern) + len(target)
+		}
+		if m.myers != nil {
+			dists[i] = m.myers.dist(target, l)
+			continue
+		}
+		// Reuse the Berghel-Roach arrays across targets
+		if br == nil {
+			br = &berghelRoach{pattern: m.pattern}
+		}
+		dists[i] = br.getDistance(target, l)
+	}
+	return dists
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package berghelroach
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// randomEdits returns s with n random single-byte insertions, deletions and
+// substitutions from alphabet.
+func randomEdits(r *rand.Rand, s string, alphabet string, n int) string {
+	b := []byte(s)
+	for i := 0; i < n; i++ {
+		c := alphabet[r.Intn(len(alphabet))]
+		switch pos := r.Intn(len(b) + 1); {
+		case r.Intn(3) == 0 || len(b) == 0 || pos == len(b):
+			b = append(b[:pos], append([]byte{c}, b[pos:]...)...)
+		case r.Intn(2) == 0:
+			b = append(b[:pos], b[pos+1:]...)
+		default:
+			b[pos] = c
+		}
+	}
+	return string(b)
+}
+
+func TestMyersDist(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const alphabet = "abcd"
+	// Lengths around the block boundaries
+	lengths := []int{0, 1, 2, 63, 64, 65, 127, 128, 129, 200, 300}
+
+	for _, length := range lengths {
+		var builder strings.Builder
+		for i := 0; i < length; i++ {
+			builder.WriteByte(alphabet[r.Intn(len(alphabet))])
+		}
+		pattern := builder.String()
+		p := newMyersPattern(pattern)
+
+		for _, edits := range []int{0, 1, 5, 20, 80} {
+			target := randomEdits(r, pattern, alphabet, edits)
+			want := dynamicProgrammingLevenshtein(pattern, target)
+
+			for _, limit := range []int{0, 1, 3, 10, 30, 100, len(pattern) + len(target)} {
+				got := p.dist(target, limit)
+				if (want <= limit && got != want) || (want > limit && got <= limit) {
+					t.Errorf("dist(%q, %q, %d) = %d, wanted %d", pattern, target, limit, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	const alphabet = "abcdefgh"
+
+	for _, pattern := range []string{"", "short pattern", generateRandomString(1000, 3)} {
+		targets := []string{"", pattern}
+		for _, edits := range []int{1, 10, 100} {
+			targets = append(targets, randomEdits(r, pattern, alphabet, edits))
+		}
+		targets = append(targets, generateRandomString(len(pattern), 4))
+
+		m := NewMatcher(pattern)
+		for _, limit := range []int{0, 5, 50} {
+			dists := m.DistAll(targets, limit)
+			for i, target := range targets {
+				want := dynamicProgrammingLevenshtein(pattern, target)
+				k := limit
+				if k == 0 {
+					k = len(pattern) + len(target)
+				}
+				if got := m.Dist(target, limit); (want <= k && got != want) || (want > k && got <= k) {
+					t.Errorf("Dist(%q, %d) = %d, wanted %d", target, limit, got, want)
+				}
+				if got := dists[i]; (want <= k && got != want) || (want > k && got <= k) {
+					t.Errorf("DistAll(%d)[%d] = %d, wanted %d", limit, i, got, want)
+				}
+			}
+		}
+	}
+}
+
+/*
+failureText returns a failure text like those that triage clusters: an e2e
+test failure with a Go stack trace. The seed varies the resource names, lines
+and frames, so that texts with different seeds are different failures.
+*/
+func failureText(seed int64, frames int) string {
+	r := rand.New(rand.NewSource(seed))
+	packages := []string{"e2e/framework", "e2e/storage", "e2e/apps", "e2e/network", "client-go/tools/cache", "apimachinery/pkg/util/wait"}
+	functions := []string{"WaitForPodRunningInNamespace", "ExpectNoError", "PollImmediate", "(*Framework).BeforeEach", "RunHostCmd", "ExpectEqual", "CreatePod"}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "test/e2e/%s.go:%d\n", packages[r.Intn(len(packages))], r.Intn(1000))
+	fmt.Fprintf(&b, "Unexpected error:\n    <*errors.errorString | 0x%x>: {\n", r.Int63())
+	fmt.Fprintf(&b, "        s: \"pod \\\"pod-%x\\\" in namespace \\\"e2e-tests-%d\\\" did not reach Running: timed out waiting for the condition\",\n    }\n", r.Int31(), r.Intn(10000))
+	b.WriteString("occurred\n\ngoroutine 1 [running]:\n")
+	for i := 0; i < frames; i++ {
+		pkg := packages[r.Intn(len(packages))]
+		fmt.Fprintf(&b, "k8s.io/kubernetes/test/%s.%s(0x%x, 0x%x)\n", pkg, functions[r.Intn(len(functions))], r.Int31(), r.Int31())
+		fmt.Fprintf(&b, "\t/go/src/k8s.io/kubernetes/test/%s/util.go:%d +0x%x\n", pkg, r.Intn(2000), r.Intn(4096))
+	}
+	return b.String()
+}
+
+/*
+benchmarkCandidates returns a failure text, and candidates for it to be matched
+against as clustering does: a few that are close, with the hex constants and
+line numbers of the text changed, and many that are other failures of a
+similar length.
+*/
+func benchmarkCandidates(frames int) (string, []string) {
+	text := failureText(1, frames)
+	r := rand.New(rand.NewSource(2))
+
+	var candidates []string
+	for i := 0; i < 3; i++ {
+		candidates = append(candidates, randomEdits(r, text, "0123456789abcdef", len(text)/50))
+	}
+	for seed := int64(100); seed < 120; seed++ {
+		candidates = append(candidates, failureText(seed, frames))
+	}
+	return text, candidates
+}
+
+// benchmarkLimit is the limit that findMatch uses, 10% of the average length.
+func benchmarkLimit(a, b string) int {
+	return (len(a) + len(b)) / 20
+}
+
+// BenchmarkDist matches a failure text against the candidates with Dist, as
+// findMatch did before Matcher.
+func BenchmarkDist(b *testing.B) {
+	for _, frames := range []int{2, 10, 50} {
+		text, candidates := benchmarkCandidates(frames)
+		b.Run(fmt.Sprintf("%dB", len(text)), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, candidate := range candidates {
+					Dist(text, candidate, benchmarkLimit(text, candidate))
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkMatcher matches a failure text against the candidates with a
+// Matcher, including creating it.
+func BenchmarkMatcher(b *testing.B) {
+	for _, frames := range []int{2, 10, 50} {
+		text, candidates := benchmarkCandidates(frames)
+		b.Run(fmt.Sprintf("%dB", len(text)), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				m := NewMatcher(text)
+				for _, candidate := range candidates {
+					m.Dist(candidate, benchmarkLimit(text, candidate))
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkMatcherDistAll matches a failure text against the candidates with
+// a shared limit.
+func BenchmarkMatcherDistAll(b *testing.B) {
+	for _, frames := range []int{2, 10, 50} {
+		text, candidates := benchmarkCandidates(frames)
+		limit := benchmarkLimit(text, text)
+		b.Run(fmt.Sprintf("%dB", len(text)), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				NewMatcher(text).DistAll(candidates, limit)
+			}
+		})
+	}
+}
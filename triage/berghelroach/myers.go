@@ -0,0 +1,193 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package berghelroach
+
+import (
+	"k8s.io/test-infra/triage/utils"
+)
+
+/*
+The bit-parallel backend computes edit distance with Myers' algorithm, in the
+form given by Hyyrö for distances between whole strings, described in
+
+	G. Myers, "A fast bit-vector algorithm for approximate string matching
+	based on dynamic programming", Journal of the ACM, 46(3):395-415, 1999
+
+	H. Hyyrö, "A bit-vector algorithm for computing Levenshtein and
+	Damerau edit distances", Nordic Journal of Computing, 10(1):29-39, 2003
+
+Each column of the dynamic programming matrix, with one row per byte of the
+pattern, is encoded as the differences between vertically adjacent cells,
+which are -1, 0 or +1. These are held as two bit-vectors per 64 rows (a
+"block"): pv has a bit set where the difference is +1, mv where it is -1.
+Advancing a column by one byte of the target takes a handful of word
+operations per block, instead of one operation per cell.
+
+Blocks are only computed while they can hold a cell whose distance is within
+the limit (Ukkonen's cut-off): every cell on the path to a cell with distance
+d has a distance of at most d, so cells beyond the last such block can be
+treated as infinite. This bounds the work by the limit rather than the length
+of the pattern, which matters since failure texts are long but the limits
+that clustering uses reject most candidates early.
+*/
+
+// wordSize is the number of rows of the matrix in one block.
+const wordSize = 64
+
+// myersPattern holds the pattern preprocessed for the bit-parallel backend,
+// so that it can be compared against many targets.
+type myersPattern struct {
+	length    int
+	numBlocks int
+	// peq maps each byte to the bit-vectors of the rows of the pattern that
+	// hold that byte, one per block. It is nil for bytes not in the pattern.
+	peq [256][]uint64
+}
+
+func newMyersPattern(pattern string) *myersPattern {
+	p := &myersPattern{
+		length:    len(pattern),
+		numBlocks: (len(pattern) + wordSize - 1) / wordSize,
+	}
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if p.peq[c] == nil {
+			p.peq[c] = make([]uint64, p.numBlocks)
+		}
+		p.peq[c][i/wordSize] |= 1 << (uint(i) % wordSize)
+	}
+	return p
+}
+
+// blockRows returns the number of rows of the pattern in block b.
+func (p *myersPattern) blockRows(b int) int {
+	if b == p.numBlocks-1 {
+		return p.length - b*wordSize
+	}
+	return wordSize
+}
+
+/*
+advanceBlock advances one block by one column. pv and mv hold the vertical
+differences of the block, eq the rows of the block that match the byte of the
+target, and hin the horizontal difference entering the block from above.
+highBit selects the last row of the block.
+
+Returns the horizontal difference leaving the block below its last row.
+*/
+func advanceBlock(pv, mv *uint64, eq uint64, hin int, highBit uint64) int {
+	xv := eq | *mv
+	if hin < 0 {
+		eq |= 1
+	}
+	xh := (((eq & *pv) + *pv) ^ *pv) | eq
+	ph := *mv | ^(xh | *pv)
+	mh := *pv & xh
+
+	hout := 0
+	if ph&highBit != 0 {
+		hout = 1
+	} else if mh&highBit != 0 {
+		hout = -1
+	}
+
+	ph <<= 1
+	mh <<= 1
+	if hin < 0 {
+		mh |= 1
+	} else if hin > 0 {
+		ph |= 1
+	}
+	*pv = mh | ^(xv | ph)
+	*mv = ph & xv
+	return hout
+}
+
+// dist computes the edit distance between the pattern and target. Like Dist,
+// the result is exact if it is at most limit, and greater than limit
+// otherwise.
+func (p *myersPattern) dist(target string, limit int) int {
+	if diff := utils.Abs(p.length - len(target)); diff > limit {
+		return diff
+	}
+	if p.length == 0 {
+		return len(target)
+	}
+	// The distance is never more than the longer length, which bounds the
+	// number of blocks that can be active
+	limit = utils.Min(limit, utils.Max(p.length, len(target)))
+
+	pv := make([]uint64, p.numBlocks)
+	mv := make([]uint64, p.numBlocks)
+	// score holds the distance in the last row of each active block
+	score := make([]int, p.numBlocks)
+
+	// Initially, the distance of each row is its index, so only the blocks
+	// with a row within the limit are active
+	lastBlock := utils.Min(p.numBlocks, (limit+wordSize)/wordSize) - 1
+	for b := 0; b <= lastBlock; b++ {
+		pv[b] = ^uint64(0)
+		score[b] = b*wordSize + p.blockRows(b)
+	}
+
+	for j := 0; j < len(target); j++ {
+		eqs := p.peq[target[j]]
+
+		// The first row is the distance from the empty pattern, which grows
+		// by one with each column
+		hout := 1
+		for b := 0; b <= lastBlock; b++ {
+			var eq uint64
+			if eqs != nil {
+				eq = eqs[b]
+			}
+			hout = advanceBlock(&pv[b], &mv[b], eq, hout, 1<<uint(p.blockRows(b)-1))
+			score[b] += hout
+		}
+
+		// Activate the next block if its first row can be within the limit.
+		// Its cells were out of the limit so far, and start out as an upper
+		// bound on their distance for the previous column.
+		for lastBlock < p.numBlocks-1 && score[lastBlock]-1 <= limit {
+			b := lastBlock + 1
+			pv[b], mv[b] = ^uint64(0), 0
+			rows := p.blockRows(b)
+			score[b] = score[lastBlock] - hout + rows
+			var eq uint64
+			if eqs != nil {
+				eq = eqs[b]
+			}
+			hout = advanceBlock(&pv[b], &mv[b], eq, hout, 1<<uint(rows-1))
+			score[b] += hout
+			lastBlock = b
+		}
+
+		// Deactivate the last blocks while none of their rows can be within
+		// the limit. The distance changes by at most one from row to row.
+		for lastBlock >= 0 && score[lastBlock]-(p.blockRows(lastBlock)-1) > limit {
+			lastBlock--
+		}
+		if lastBlock < 0 {
+			return limit + 1
+		}
+	}
+
+	if lastBlock < p.numBlocks-1 || score[lastBlock] > limit {
+		return limit + 1
+	}
+	return score[lastBlock]
+}
@@ -16,8 +16,22 @@ limitations under the License.
 
 package main
 
-import "k8s.io/test-infra/triage/summarize"
+import (
+	"os"
+
+	"k8s.io/test-infra/triage/summarize"
+)
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+			summarize.Serve(os.Args[2:])
+			return
+		case "normalize":
+			summarize.Normalize(os.Args[2:])
+			return
+		}
+	}
 	summarize.Main()
 }
@@ -21,6 +21,7 @@ Contains functions that cluster failures.
 package summarize
 
 import (
+	"sort"
 	"sync"
 	"time"
 
@@ -62,8 +63,8 @@ Returns:
 		...
 	}
 */
-func clusterLocal(failuresByTest failuresGroup, numWorkers int, memoize bool, maxClusterTextLength int) nestedFailuresGroups {
-	const memoPath string = "memo_cluster_local.json"
+func clusterLocal(failuresByTest failuresGroup, numWorkers int, memoize bool, maxClusterTextLength int, rules normalizationRules) nestedFailuresGroups {
+	memoPath := rules.memoPath("memo_cluster_local.json")
 	const memoMessage string = "clustering inside each test"
 
 	clustered := make(nestedFailuresGroups)
@@ -73,6 +74,32 @@ func clusterLocal(failuresByTest failuresGroup, numWorkers int, memoize bool, ma
 		return clustered
 	}
 
+	clustered = extendLocalClusters(nil, failuresByTest, numWorkers, maxClusterTextLength, rules)
+
+	// Memoize the results
+	if memoize {
+		memoizeResults(memoPath, memoMessage, clustered)
+	}
+	return clustered
+}
+
+/*
+extendLocalClusters clusters the failures for each test like clusterLocal, but starts from the
+clusters already in seeds, which maps test names to their existing clusters. New failures join an
+existing cluster if they match it, so that only failuresByTest has to be clustered. seeds is not
+modified, and can be nil.
+
+Tests in seeds that have no new failures are returned unchanged.
+*/
+func extendLocalClusters(seeds nestedFailuresGroups, failuresByTest failuresGroup, numWorkers int, maxClusterTextLength int, rules normalizationRules) nestedFailuresGroups {
+	clustered := make(nestedFailuresGroups, len(seeds)+len(failuresByTest))
+	for testName, seed := range seeds {
+		if _, ok := failuresByTest[testName]; !ok {
+			clustered[testName] = seed
+		}
+	}
+
+	numTests := 0    // The number of tests processed so far
 	numFailures := 0 // The number of failures processed so far
 	start := time.Now()
 	klog.V(2).Infof("Clustering failures for %d unique tests...", len(failuresByTest))
@@ -120,7 +147,8 @@ func clusterLocal(failuresByTest failuresGroup, numWorkers int, memoize bool, ma
 
 		for dg := range doneQueue {
 			numFailures += len(dg.input.Failures)
-			klog.V(3).Infof("%4d/%4d tests, %5d failures, %s", len(clustered)+1, len(failuresByTest), len(dg.input.Failures), dg.input.Key)
+			klog.V(3).Infof("%4d/%4d tests, %5d failures, %s", numTests+1, len(failuresByTest), len(dg.input.Failures), dg.input.Key)
+			numTests++
 			clustered[dg.input.Key] = dg.output
 		}
 	}()
@@ -133,7 +161,7 @@ func clusterLocal(failuresByTest failuresGroup, numWorkers int, memoize bool, ma
 			for pair := range workQueue {
 				doneQueue <- doneGroup{
 					pair,
-					clusterTest(pair.Failures, maxClusterTextLength),
+					extendClusters(seeds[pair.Key], pair.Failures, maxClusterTextLength, rules),
 				}
 			}
 		}()
@@ -147,12 +175,8 @@ func clusterLocal(failuresByTest failuresGroup, numWorkers int, memoize bool, ma
 	doneQueueWG.Wait()
 
 	elapsed := time.Since(start)
-	klog.V(2).Infof("Finished locally clustering %d unique tests (%d failures) in %s", len(clustered), numFailures, elapsed.String())
+	klog.V(2).Infof("Finished locally clustering %d unique tests (%d failures) in %s", numTests, numFailures, elapsed.String())
 
-	// Memoize the results
-	if memoize {
-		memoizeResults(memoPath, memoMessage, clustered)
-	}
 	return clustered
 }
 
@@ -164,6 +188,139 @@ type failure struct {
 	FailureText string `json:"failure_text"`
 }
 
+// clusterState is the result of clustering inside each test, persisted between runs so that the
+// next run only has to cluster the failures it has not seen before.
+type clusterState struct {
+	// MaxClusterTextLength is the truncation length the cluster texts were normalized with. State
+	// normalized differently cannot be reused.
+	MaxClusterTextLength int `json:"max_cluster_text_length"`
+	// NormalizationRules is the digest of the normalization rules the cluster texts were normalized
+	// with, for the same reason.
+	NormalizationRules string `json:"normalization_rules,omitempty"`
+	// Tests maps test names to their clusters.
+	Tests map[string][]stateCluster `json:"tests"`
+}
+
+// stateCluster is a cluster of the failures of one test.
+type stateCluster struct {
+	Text     string    `json:"text"`   // The normalized failure text that is the cluster's key
+	Digest   string    `json:"digest"` // The result of calling makeNgramCountsDigest() on Text
+	Failures []failure `json:"failures"`
+}
+
+// digests returns a map from the texts of the clusters in state to their digests.
+func (state *clusterState) digests() map[string]string {
+	digests := make(map[string]string)
+	for _, testClusters := range state.Tests {
+		for _, cluster := range testClusters {
+			digests[cluster.Text] = cluster.Digest
+		}
+	}
+	return digests
+}
+
+// newClusterState creates the state to persist for the result of clustering inside each test.
+// digests maps cluster texts to their known digests, so that they are not computed again, and can
+// be nil.
+func newClusterState(clustered nestedFailuresGroups, digests map[string]string, maxClusterTextLength int, rules normalizationRules) *clusterState {
+	state := &clusterState{
+		MaxClusterTextLength: maxClusterTextLength,
+		NormalizationRules:   rules.digest(),
+		Tests:                make(map[string][]stateCluster, len(clustered)),
+	}
+	for testName, clusters := range clustered {
+		keys := clusters.keys()
+		sort.Strings(keys)
+		testClusters := make([]stateCluster, 0, len(keys))
+		for _, key := range keys {
+			digest, ok := digests[key]
+			if !ok {
+				digest = makeNgramCountsDigest(key)
+			}
+			testClusters = append(testClusters, stateCluster{key, digest, clusters[key]})
+		}
+		state.Tests[testName] = testClusters
+	}
+	return state
+}
+
+/*
+clusterIncremental clusters together the failures for each test like clusterLocal, but reuses the
+clusters in state, which can be nil, from a previous run. Failures already in a cluster stay in it,
+and only failures that are not in state are clustered. Failures in state that are no longer in
+failuresByTest have aged out of the window and are dropped, along with any clusters left empty.
+
+Returns the clusters, in the same form as clusterLocal, and the state to persist for the next run.
+*/
+func clusterIncremental(failuresByTest failuresGroup, state *clusterState, numWorkers int, maxClusterTextLength int, rules normalizationRules) (nestedFailuresGroups, *clusterState) {
+	seeds := make(nestedFailuresGroups)
+	newFailures := make(failuresGroup)
+	digests := make(map[string]string) // The digests of the texts of reused clusters
+	numReused, numAged := 0, 0
+
+	if state != nil {
+		for testName, testClusters := range state.Tests {
+			if _, ok := failuresByTest[testName]; !ok {
+				for _, cluster := range testClusters {
+					numAged += len(cluster.Failures)
+				}
+			}
+		}
+	}
+
+	for testName, failures := range failuresByTest {
+		// Count the failures, as a test can fail several times in one build
+		current := make(map[failure]int, len(failures))
+		for _, flr := range failures {
+			current[flr]++
+		}
+
+		var testClusters []stateCluster
+		if state != nil {
+			testClusters = state.Tests[testName]
+		}
+		seed := make(failuresGroup)
+		for _, cluster := range testClusters {
+			if cluster.Digest != "" {
+				digests[cluster.Text] = cluster.Digest
+			}
+			for _, flr := range cluster.Failures {
+				if current[flr] == 0 {
+					numAged++
+					continue
+				}
+				current[flr]--
+				seed[cluster.Text] = append(seed[cluster.Text], flr)
+				numReused++
+			}
+		}
+		if len(seed) != 0 {
+			seeds[testName] = seed
+		}
+
+		// Whatever is left was not seen by the previous run
+		for _, flr := range failures {
+			if current[flr] > 0 {
+				current[flr]--
+				newFailures[testName] = append(newFailures[testName], flr)
+			}
+		}
+	}
+
+	klog.V(2).Infof("Reusing %d clustered failures, dropping %d aged out failures", numReused, numAged)
+	clustered := extendLocalClusters(seeds, newFailures, numWorkers, maxClusterTextLength, rules)
+
+	// Restore the order failures are loaded in, which new failures appended to existing clusters
+	// would otherwise break
+	for _, clusters := range clustered {
+		for _, clusterFailures := range clusters {
+			sort.SliceStable(clusterFailures, func(i, j int) bool { return clusterFailures[i].Build < clusterFailures[j].Build })
+		}
+	}
+
+	return clustered, newClusterState(clustered, digests, maxClusterTextLength, rules)
+}
+
 /*
 clusterGlobal combines together clustered failures from each test. Clusters come in
 grouped by the test their failures belong to. Similar cluster texts are merged across
@@ -214,8 +371,8 @@ Returns:
 		...
 	}
 */
-func clusterGlobal(newlyClustered nestedFailuresGroups, previouslyClustered []jsonCluster, memoize bool, maxClusterTextLength int) nestedFailuresGroups {
-	const memoPath string = "memo_cluster_global.json"
+func clusterGlobal(newlyClustered nestedFailuresGroups, previouslyClustered []jsonCluster, memoize bool, maxClusterTextLength int, rules normalizationRules) nestedFailuresGroups {
+	memoPath := rules.memoPath("memo_cluster_global.json")
 	const memoMessage string = "clustering across tests"
 	truncatedClusterTextLength := maxClusterTextLength + len(truncatedSep)
 
@@ -237,7 +394,7 @@ func clusterGlobal(newlyClustered nestedFailuresGroups, previouslyClustered []js
 		n := 0
 		for _, cluster := range previouslyClustered {
 			key := cluster.Key
-			normalizedKey := normalize(key, maxClusterTextLength)
+			normalizedKey := normalize(key, maxClusterTextLength, rules)
 			if key != normalizedKey {
 				klog.V(4).Infof(key)
 				klog.V(4).Infof(normalizedKey)
@@ -350,12 +507,21 @@ Returns:
 		...
 	}
 */
-func clusterTest(failures []failure, maxClusterTextLength int) failuresGroup {
-	result := make(failuresGroup, len(failures))
+func clusterTest(failures []failure, maxClusterTextLength int, rules normalizationRules) failuresGroup {
+	return extendClusters(nil, failures, maxClusterTextLength, rules)
+}
+
+// extendClusters adds failures to a copy of the clusters of one test, as clusterTest would if the
+// failures of those clusters had been clustered first. clusters can be nil.
+func extendClusters(clusters failuresGroup, failures []failure, maxClusterTextLength int, rules normalizationRules) failuresGroup {
+	result := make(failuresGroup, len(clusters)+len(failures))
+	for key, clusterFailures := range clusters {
+		result[key] = append([]failure(nil), clusterFailures...)
+	}
 	start := time.Now()
 
 	for _, flr := range failures {
-		fNorm := normalize(flr.FailureText, maxClusterTextLength)
+		fNorm := normalize(flr.FailureText, maxClusterTextLength, rules)
 
 		// If this string is already in the result list, store it
 		if _, ok := result[fNorm]; ok {
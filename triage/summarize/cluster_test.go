@@ -18,6 +18,7 @@ package summarize
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -88,7 +89,7 @@ func TestClusterTest(t *testing.T) {
 	// Run the tests
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := clusterTest(tc.arguments, defaultMaxClusterTextLength)
+			got := clusterTest(tc.arguments, defaultMaxClusterTextLength, nil)
 
 			if !tc.want.equal(&got) {
 				t.Errorf("clusterTest(%#v) = %#v, wanted %#v", tc.arguments, got, tc.want)
@@ -119,7 +120,7 @@ func TestClusterGlobal(t *testing.T) {
 			},
 		}
 
-		got := clusterGlobal(argument, nil, false, defaultMaxClusterTextLength)
+		got := clusterGlobal(argument, nil, false, defaultMaxClusterTextLength, nil)
 
 		if !want.equal(&got) {
 			t.Errorf("clusterGlobal(%#v) = %#v, wanted %#v", argument, got, want)
@@ -141,10 +142,87 @@ func TestClusterGlobal(t *testing.T) {
 
 		want := nestedFailuresGroups{textOld: failuresGroup{"test a": []failure{f1}}}
 
-		got := clusterGlobal(argument, previous, true, defaultMaxClusterTextLength)
+		got := clusterGlobal(argument, previous, true, defaultMaxClusterTextLength, nil)
 
 		if !want.equal(&got) {
 			t.Errorf("clusterGlobal(%#v, %#v) = %#v, wanted %#v", argument, previous, got, want)
 		}
 	})
 }
+
+func TestClusterIncremental(t *testing.T) {
+	textA := "long message immediately preceding exit code 1"
+	textA2 := "long message immediately preceding exit code 2"
+	textB := "a completely different reason for the failure"
+
+	f1 := failure{Build: "b1", Name: "test a", FailureText: textA}
+	f2 := failure{Build: "b2", Name: "test a", FailureText: textB}
+	f3 := failure{Build: "b3", Name: "test a", FailureText: textA2}
+	f4 := failure{Build: "b1", Name: "test b", FailureText: textB}
+	f5 := failure{Build: "b4", Name: "test c", FailureText: textB}
+
+	// The first run has no state, so it clusters like clusterLocal
+	first := failuresGroup{"test a": {f1, f2}, "test b": {f4}}
+	got, state := clusterIncremental(first, nil, 2, defaultMaxClusterTextLength, nil)
+	want := clusterLocal(first, 2, false, defaultMaxClusterTextLength, nil)
+	if !want.equal(&got) {
+		t.Errorf("clusterIncremental(%#v, nil) = %#v, wanted %#v", first, got, want)
+	}
+
+	// Persist the state and load it back, as the next run would
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := writeClusterState(path, state); err != nil {
+		t.Fatalf("writeClusterState() failed: %s", err)
+	}
+	state, err := loadClusterState(path, defaultMaxClusterTextLength, nil)
+	if err != nil || state == nil {
+		t.Fatalf("loadClusterState() = %#v, %v", state, err)
+	}
+	if digest := state.Tests["test a"][0].Digest; digest != makeNgramCountsDigest(state.Tests["test a"][0].Text) {
+		t.Errorf("wrong digest %q for %q", digest, state.Tests["test a"][0].Text)
+	}
+
+	// The digests of reused clusters are not computed again
+	for i, cluster := range state.Tests["test a"] {
+		if cluster.Text == textA {
+			state.Tests["test a"][i].Digest = "stored"
+		}
+	}
+
+	// f2 and test b age out, f3 joins the cluster of f1 and test c is new
+	second := failuresGroup{"test a": {f1, f3}, "test c": {f5}}
+	got, state = clusterIncremental(second, state, 2, defaultMaxClusterTextLength, nil)
+	want = nestedFailuresGroups{
+		"test a": failuresGroup{textA: {f1, f3}},
+		"test c": failuresGroup{textB: {f5}},
+	}
+	if !want.equal(&got) {
+		t.Errorf("clusterIncremental(%#v) = %#v, wanted %#v", second, got, want)
+	}
+	if len(state.Tests) != 2 || len(state.Tests["test a"]) != 1 || len(state.Tests["test a"][0].Failures) != 2 {
+		t.Errorf("wrong state after the second run: %#v", state)
+	}
+	if digest := state.Tests["test a"][0].Digest; digest != "stored" {
+		t.Errorf("wrong digest %q for the reused cluster of test a, wanted the stored one", digest)
+	}
+	if digest := state.Tests["test c"][0].Digest; digest != makeNgramCountsDigest(textB) {
+		t.Errorf("wrong digest %q for the new cluster of test c", digest)
+	}
+
+	// State normalized differently is not reused
+	state, err = loadClusterState(path, defaultMaxClusterTextLength/2, nil)
+	if err != nil || state != nil {
+		t.Errorf("loadClusterState() with a different max_cluster_text_length = %#v, %v; wanted nil", state, err)
+	}
+	rules := normalizationRules{{Regex: "x", Replacement: "y"}}
+	state, err = loadClusterState(path, defaultMaxClusterTextLength, rules)
+	if err != nil || state != nil {
+		t.Errorf("loadClusterState() with different normalization rules = %#v, %v; wanted nil", state, err)
+	}
+
+	// There is no state before the first run
+	state, err = loadClusterState(filepath.Join(t.TempDir(), "missing.json"), defaultMaxClusterTextLength, nil)
+	if err != nil || state != nil {
+		t.Errorf("loadClusterState() of a missing file = %#v, %v; wanted nil", state, err)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Contains 'triage normalize', which shows how a failure text normalizes and which existing cluster it
+would join, to help write normalization rules.
+*/
+
+package summarize
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/klog/v2"
+)
+
+// normalizeFlags represents the command-line arguments to 'triage normalize' and their values.
+type normalizeFlags struct {
+	text                 string
+	previous             string
+	normalizationRules   string
+	maxClusterTextLength int
+}
+
+// parseNormalizeFlags parses the command-line arguments following 'normalize' and returns them as
+// a normalizeFlags object.
+func parseNormalizeFlags(args []string) normalizeFlags {
+	var flags normalizeFlags
+
+	fs := flag.NewFlagSet("normalize", flag.ExitOnError)
+	fs.StringVar(&flags.text, "text", "", "failure text to normalize; read from stdin if not set")
+	fs.StringVar(&flags.previous, "previous", "", "path to an output whose clusters the failure text could join")
+	fs.StringVar(&flags.normalizationRules, "normalization_rules", "", "path to a file of project-specific regex replacements to apply to failure texts before clustering")
+	fs.IntVar(&flags.maxClusterTextLength, "max_cluster_text_length", defaultMaxClusterTextLength, "truncate failure text to this length for clustering purposes")
+	// Parsing exits on error
+	_ = fs.Parse(args)
+
+	return flags
+}
+
+/*
+explainNormalization normalizes text, and finds which of clusters it would join when clustering
+across tests. Like clusterGlobal, only clusters whose keys are unchanged by normalization can be
+joined.
+
+Returns the normalized text, and the cluster, which is nil if text would start a new cluster.
+*/
+func explainNormalization(text string, clusters []jsonCluster, maxClusterTextLength int, rules normalizationRules) (string, *jsonCluster) {
+	normalized := normalize(text, maxClusterTextLength, rules)
+
+	byKey := make(map[string]int, len(clusters))
+	keys := make([]string, 0, len(clusters))
+	for i, cluster := range clusters {
+		if normalize(cluster.Key, maxClusterTextLength, rules) != cluster.Key {
+			continue
+		}
+		byKey[cluster.Key] = i
+		keys = append(keys, cluster.Key)
+	}
+
+	key := normalized
+	if _, ok := byKey[key]; !ok {
+		var found bool
+		key, found = findMatch(normalized, keys)
+		if !found {
+			return normalized, nil
+		}
+	}
+	return normalized, &clusters[byKey[key]]
+}
+
+func explain(flags normalizeFlags, stdin io.Reader, stdout io.Writer) error {
+	text := flags.text
+	if text == "" {
+		contents, err := io.ReadAll(stdin)
+		if err != nil {
+			return fmt.Errorf("Could not read failure text: %s", err)
+		}
+		text = string(contents)
+	}
+
+	var rules normalizationRules
+	var err error
+	if flags.normalizationRules != "" {
+		rules, err = loadNormalizationRules(flags.normalizationRules)
+		if err != nil {
+			return err
+		}
+	}
+
+	var clusters []jsonCluster
+	if flags.previous != "" {
+		clusters, err = loadPrevious(flags.previous)
+		if err != nil {
+			return err
+		}
+	}
+
+	normalized, cluster := explainNormalization(text, clusters, flags.maxClusterTextLength, rules)
+
+	fmt.Fprintf(stdout, "Normalized text:\n%s\n\n", normalized)
+	switch {
+	case flags.previous == "":
+	case cluster == nil:
+		fmt.Fprintf(stdout, "Would start a new cluster\n")
+	default:
+		fmt.Fprintf(stdout, "Would join cluster %s (owner: %s) with key:\n%s\n", cluster.ID, cluster.Owner, cluster.Key)
+	}
+	return nil
+}
+
+// Normalize runs 'triage normalize' with the command-line arguments following 'normalize'.
+func Normalize(args []string) {
+	setUpLogging(true, 0)
+
+	if err := explain(parseNormalizeFlags(args), os.Stdin, os.Stdout); err != nil {
+		klog.Fatal(err)
+	}
+}
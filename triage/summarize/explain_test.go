@@ -0,0 +1,111 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summarize
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExplainNormalization(t *testing.T) {
+	rules := normalizationRules{{Regex: `pod-[a-z0-9]{10}`, Replacement: "POD"}}
+	if err := rules.compile(); err != nil {
+		t.Fatalf("compile() failed: %s", err)
+	}
+	clusters := []jsonCluster{
+		{Key: "timed out waiting for POD to be running", ID: "timeout"},
+		{Key: "some completely different failure of a volume mount", ID: "volume"},
+		// Not a key that normalization produces, so it can't be joined
+		{Key: "panic at 0x1234", ID: "panic"},
+	}
+
+	testCases := []struct {
+		name           string
+		text           string
+		wantNormalized string
+		wantID         string
+	}{
+		{
+			name:           "Same key",
+			text:           "timed out waiting for pod-abcde12345 to be running",
+			wantNormalized: "timed out waiting for POD to be running",
+			wantID:         "timeout",
+		},
+		{
+			name:           "Similar key",
+			text:           "timed out waiting for pod-abcde12345 to be running!",
+			wantNormalized: "timed out waiting for POD to be running!",
+			wantID:         "timeout",
+		},
+		{
+			name:           "Key that changes under normalization",
+			text:           "panic at 0x1234",
+			wantNormalized: "panic at UNIQ1",
+		},
+		{
+			name:           "New cluster",
+			text:           "connection refused",
+			wantNormalized: "connection refused",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			normalized, cluster := explainNormalization(tc.text, clusters, defaultMaxClusterTextLength, rules)
+			if normalized != tc.wantNormalized {
+				t.Errorf("explainNormalization(%q) normalized to %q, wanted %q", tc.text, normalized, tc.wantNormalized)
+			}
+			id := ""
+			if cluster != nil {
+				id = cluster.ID
+			}
+			if id != tc.wantID {
+				t.Errorf("explainNormalization(%q) joined cluster %q, wanted %q", tc.text, id, tc.wantID)
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	dir := t.TempDir()
+	rulesPath := filepath.Join(dir, "rules.json")
+	if err := os.WriteFile(rulesPath, []byte(`[{"regex": "pod-[a-z0-9]{10}", "replacement": "POD"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	previousPath := filepath.Join(dir, "failure_data.json")
+	previous := jsonOutput{Clustered: []jsonCluster{{Key: "timed out waiting for POD", ID: "timeout", Owner: "node"}}, Builds: buildsToColumns(nil)}
+	if err := writeResults(previousPath, previous); err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	flags := normalizeFlags{previous: previousPath, normalizationRules: rulesPath, maxClusterTextLength: defaultMaxClusterTextLength}
+	if err := explain(flags, strings.NewReader("timed out waiting for pod-abcde12345"), &out); err != nil {
+		t.Fatalf("explain() failed: %s", err)
+	}
+	want := "Normalized text:\ntimed out waiting for POD\n\nWould join cluster timeout (owner: node) with key:\ntimed out waiting for POD\n"
+	if out.String() != want {
+		t.Errorf("explain() wrote %q, wanted %q", out.String(), want)
+	}
+
+	flags.normalizationRules = filepath.Join(dir, "missing.json")
+	if err := explain(flags, strings.NewReader(""), &out); err == nil {
+		t.Errorf("explain() with a missing rules file succeeded")
+	}
+}
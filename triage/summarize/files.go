@@ -63,6 +63,43 @@ func loadPrevious(filepath string) ([]jsonCluster, error) {
 	return previous.Clustered, nil
 }
 
+// loadClusterState loads the clustering state persisted by a previous run. It returns nil if there
+// is no state at filepath yet, or if the state was normalized with a different maxClusterTextLength
+// or different rules.
+func loadClusterState(filepath string, maxClusterTextLength int, rules normalizationRules) (*clusterState, error) {
+	if _, err := os.Stat(filepath); os.IsNotExist(err) {
+		klog.V(2).Infof("No clustering state at %s, all failures will be clustered", filepath)
+		return nil, nil
+	}
+
+	var state clusterState
+	err := getJSON(filepath, &state)
+	if err != nil {
+		return nil, fmt.Errorf("Could not get clustering state JSON: %s", err)
+	}
+
+	if state.MaxClusterTextLength != maxClusterTextLength {
+		klog.Warningf("Clustering state was normalized with max_cluster_text_length %d instead of %d, all failures will be clustered",
+			state.MaxClusterTextLength, maxClusterTextLength)
+		return nil, nil
+	}
+	if state.NormalizationRules != rules.digest() {
+		klog.Warningf("Clustering state was normalized with different normalization rules, all failures will be clustered")
+		return nil, nil
+	}
+
+	return &state, nil
+}
+
+// writeClusterState persists the clustering state for the next run.
+func writeClusterState(filepath string, state *clusterState) error {
+	err := writeJSON(filepath, state)
+	if err != nil {
+		return fmt.Errorf("Could not write clustering state to disk: %s", err)
+	}
+	return nil
+}
+
 // loadOwners loads an owners JSON file and returns it.
 func loadOwners(filepath string) (map[string][]string, error) {
 	var owners map[string][]string
@@ -75,6 +112,23 @@ func loadOwners(filepath string) (map[string][]string, error) {
 	return owners, nil
 }
 
+// loadNormalizationRules loads a normalization rules JSON file and returns the compiled rules.
+func loadNormalizationRules(filepath string) (normalizationRules, error) {
+	var rules normalizationRules
+
+	err := getJSON(filepath, &rules)
+	if err != nil {
+		return nil, fmt.Errorf("Could not get normalization rules JSON: %s", err)
+	}
+
+	err = rules.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
 // writeResults outputs the results of clustering to a file.
 func writeResults(filepath string, data jsonOutput) error {
 	err := writeJSON(filepath, data)
@@ -103,6 +157,24 @@ func writeRenderedSlice(filepath string, clustered []jsonCluster, builds columns
 	return nil
 }
 
+// writeFlakes outputs the results of a call to renderFlakes() to a file.
+func writeFlakes(filepath string, flakes map[string]flakyJob) error {
+	err := writeJSON(filepath, flakes)
+	if err != nil {
+		return fmt.Errorf("Could not write flaky tests report to disk: %s", err)
+	}
+	return nil
+}
+
+// writeAlerts outputs the results of a call to renderAlerts() to a file.
+func writeAlerts(filepath string, alerts alertsOutput) error {
+	err := writeJSON(filepath, alerts)
+	if err != nil {
+		return fmt.Errorf("Could not write alerts to disk: %s", err)
+	}
+	return nil
+}
+
 /*
 getMemoizedResults attempts to retrieve memoized function results from the given filepath. If it
 succeeds, it places the results into v and returns true. Otherwise, it returns false. Internally,
@@ -0,0 +1,228 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Contains functions that relate the failures of clusters to the other builds of their jobs, for hints
+on which change caused them and whether they are flaky.
+*/
+
+package summarize
+
+import (
+	"fmt"
+	"sort"
+)
+
+// resultSuccess is the result of a build that passed.
+const resultSuccess = "SUCCESS"
+
+/*
+clusterHints holds hints about what caused a cluster.
+
+	culprits:  for each CI job the cluster failed in, the build the cluster first failed in and the
+	           last passing build before it, between which the culprit change went in
+	pr_builds: the number of presubmit builds the cluster failed in
+	ci_builds: the number of CI (periodic and postsubmit) builds the cluster failed in
+	flaky_prs: the PRs that the cluster failed in, but that also passed a build of the same job,
+	           which means the cluster is likely a flake rather than a bug in the PR
+*/
+type clusterHints struct {
+	Culprits []culpritHint `json:"culprits"`
+	PRBuilds int           `json:"pr_builds"`
+	CIBuilds int           `json:"ci_builds"`
+	FlakyPRs []string      `json:"flaky_prs"`
+}
+
+// culpritHint holds the builds of one job between which a cluster started to fail. LastPass is 0 if
+// no earlier build of the job passed.
+type culpritHint struct {
+	Job          string `json:"job"`
+	FirstFailure int    `json:"first_failure"`
+	LastPass     int    `json:"last_pass,omitempty"`
+}
+
+// prJob identifies the builds of one job for one PR.
+type prJob struct {
+	pr  string
+	job string
+}
+
+// buildHistory holds the builds grouped so that the builds around a failure can be found quickly.
+type buildHistory struct {
+	// byJob maps job names to their CI builds, sorted by build number.
+	byJob map[string][]build
+	// passedPRs holds the jobs that passed a build for each PR.
+	passedPRs map[prJob]bool
+}
+
+func newBuildHistory(builds map[string]build) buildHistory {
+	history := buildHistory{make(map[string][]build), make(map[prJob]bool)}
+	for _, bld := range builds {
+		if bld.PR != "" {
+			if bld.Result == resultSuccess {
+				history.passedPRs[prJob{bld.PR, bld.Job}] = true
+			}
+			continue
+		}
+		history.byJob[bld.Job] = append(history.byJob[bld.Job], bld)
+	}
+	for _, jobBuilds := range history.byJob {
+		sort.Slice(jobBuilds, func(i, j int) bool { return jobBuilds[i].Number < jobBuilds[j].Number })
+	}
+	return history
+}
+
+// isFlake returns whether bld is a failed presubmit build of a PR that passed another build of the
+// same job.
+func (history buildHistory) isFlake(bld build) bool {
+	return bld.PR != "" && bld.Result != resultSuccess && history.passedPRs[prJob{bld.PR, bld.Job}]
+}
+
+// lastPassBefore returns the number of the last CI build of job before the build with the given
+// number that passed, or 0 if there is none.
+func (history buildHistory) lastPassBefore(job string, number int) int {
+	jobBuilds := history.byJob[job]
+	// The index of the first build that is not before number
+	i := sort.Search(len(jobBuilds), func(i int) bool { return jobBuilds[i].Number >= number })
+	for i--; i >= 0; i-- {
+		if jobBuilds[i].Result == resultSuccess {
+			return jobBuilds[i].Number
+		}
+	}
+	return 0
+}
+
+// clusterBuilds returns the builds cluster failed in, in the order of its tests and jobs. A build
+// that several tests failed in is returned once for each.
+func clusterBuilds(cluster jsonCluster, jobPaths map[string]string, builds map[string]build) []build {
+	var clusterBuilds []build
+	for _, test := range cluster.Tests {
+		for _, job := range test.Jobs {
+			for _, number := range job.BuildNumbers {
+				if bld, ok := builds[fmt.Sprintf("%s/%s", jobPaths[job.Name], number)]; ok {
+					clusterBuilds = append(clusterBuilds, bld)
+				}
+			}
+		}
+	}
+	return clusterBuilds
+}
+
+// annotateHints fills in the hints of each cluster. It modifies the data parameter in place.
+func annotateHints(data *jsonOutput, builds map[string]build) {
+	history := newBuildHistory(builds)
+
+	for i := range data.Clustered {
+		cluster := &data.Clustered[i]
+		hints := clusterHints{Culprits: make([]culpritHint, 0), FlakyPRs: make([]string, 0)}
+
+		seen := make(map[string]bool)
+		firstFailures := make(map[string]int)
+		flakyPRs := make(map[string]bool)
+		for _, bld := range clusterBuilds(*cluster, data.Builds.JobPaths, builds) {
+			if seen[bld.Path] {
+				continue
+			}
+			seen[bld.Path] = true
+
+			if bld.PR != "" {
+				hints.PRBuilds++
+				if history.isFlake(bld) {
+					flakyPRs[bld.PR] = true
+				}
+				continue
+			}
+			hints.CIBuilds++
+			if first, ok := firstFailures[bld.Job]; !ok || bld.Number < first {
+				firstFailures[bld.Job] = bld.Number
+			}
+		}
+
+		for job, first := range firstFailures {
+			hints.Culprits = append(hints.Culprits, culpritHint{job, first, history.lastPassBefore(job, first)})
+		}
+		sort.Slice(hints.Culprits, func(i, j int) bool { return hints.Culprits[i].Job < hints.Culprits[j].Job })
+		for pr := range flakyPRs {
+			hints.FlakyPRs = append(hints.FlakyPRs, pr)
+		}
+		sort.Strings(hints.FlakyPRs)
+
+		cluster.Hints = hints
+	}
+}
+
+/*
+flakyJob holds the flakiness of one job, in the form that the flakyjob-reporter of
+robots/issue-creator reads.
+
+	consistency: the fraction of the job's builds that passed
+	flakes:      the number of the job's presubmit builds that failed for a PR that also passed a
+	             build of the job
+	flakiest:    maps the names of tests to the number of such builds they failed in
+*/
+type flakyJob struct {
+	Consistency float64        `json:"consistency"`
+	Flakes      int            `json:"flakes"`
+	Flakiest    map[string]int `json:"flakiest"`
+}
+
+// renderFlakes creates the flaky tests report for the annotated data, which maps the names of jobs
+// with at least one flake to their flakiness.
+func renderFlakes(data jsonOutput, builds map[string]build) map[string]flakyJob {
+	history := newBuildHistory(builds)
+
+	total := make(map[string]int)
+	passed := make(map[string]int)
+	flakes := make(map[string]int)
+	for _, bld := range builds {
+		total[bld.Job]++
+		if bld.Result == resultSuccess {
+			passed[bld.Job]++
+		}
+		if history.isFlake(bld) {
+			flakes[bld.Job]++
+		}
+	}
+
+	report := make(map[string]flakyJob, len(flakes))
+	for job, n := range flakes {
+		report[job] = flakyJob{
+			Consistency: float64(passed[job]) / float64(total[job]),
+			Flakes:      n,
+			Flakiest:    make(map[string]int),
+		}
+	}
+
+	// Count each flaky build once for each test that failed in it
+	counted := make(map[string]bool)
+	for _, cluster := range data.Clustered {
+		for _, test := range cluster.Tests {
+			for _, job := range test.Jobs {
+				for _, number := range job.BuildNumbers {
+					bld, ok := builds[fmt.Sprintf("%s/%s", data.Builds.JobPaths[job.Name], number)]
+					key := test.Name + "\x00" + bld.Path
+					if !ok || !history.isFlake(bld) || counted[key] {
+						continue
+					}
+					counted[key] = true
+					report[bld.Job].Flakiest[test.Name]++
+				}
+			}
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summarize
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestAnnotateHints(t *testing.T) {
+	builds := make(map[string]build)
+	addBuild := func(job string, number int, result string, pr string) {
+		path := fmt.Sprintf("gs://logs/%s/%d", job, number)
+		builds[path] = build{Path: path, Job: job, Number: number, Started: number, Result: result, PR: pr}
+	}
+	// A CI job that started failing after build 2
+	addBuild("ci", 1, "SUCCESS", "")
+	addBuild("ci", 2, "SUCCESS", "")
+	addBuild("ci", 3, "FAILURE", "")
+	addBuild("ci", 4, "FAILURE", "")
+	addBuild("ci", 5, "SUCCESS", "")
+	// A CI job that never passed
+	addBuild("ci-new", 7, "FAILURE", "")
+	// A presubmit job that passed on retry for PR 10, but not for PR 11
+	addBuild("pr", 20, "FAILURE", "10")
+	addBuild("pr", 21, "SUCCESS", "10")
+	addBuild("pr", 22, "FAILURE", "11")
+
+	data := jsonOutput{
+		Clustered: []jsonCluster{{
+			Key: "key",
+			Tests: []test{
+				{Name: "test-a", Jobs: []job{{"ci", []string{"4", "3"}}, {"pr", []string{"22", "20"}}}},
+				{Name: "test-b", Jobs: []job{{"ci", []string{"4"}}, {"ci-new", []string{"7"}}}},
+			},
+		}},
+		Builds: buildsToColumns(builds),
+	}
+
+	annotateHints(&data, builds)
+
+	want := clusterHints{
+		Culprits: []culpritHint{{Job: "ci", FirstFailure: 3, LastPass: 2}, {Job: "ci-new", FirstFailure: 7}},
+		PRBuilds: 2,
+		CIBuilds: 3,
+		FlakyPRs: []string{"10"},
+	}
+	if got := data.Clustered[0].Hints; !reflect.DeepEqual(got, want) {
+		t.Errorf("annotateHints() = %#v, wanted %#v", got, want)
+	}
+
+	wantFlakes := map[string]flakyJob{
+		"pr": {Consistency: 1.0 / 3, Flakes: 1, Flakiest: map[string]int{"test-a": 1}},
+	}
+	if got := renderFlakes(data, builds); !reflect.DeepEqual(got, wantFlakes) {
+		t.Errorf("renderFlakes() = %#v, wanted %#v", got, wantFlakes)
+	}
+}
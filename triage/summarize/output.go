@@ -39,8 +39,9 @@ type jsonOutput struct {
 }
 
 // render accepts a map from build paths to builds, and the global clusters, and renders them in a
-// format consumable by the web page.
-func render(builds map[string]build, clustered nestedFailuresGroups, maxFailureTextLength int) jsonOutput {
+// format consumable by the web page. digests maps cluster texts to their known digests, which are
+// used as the cluster IDs rather than computing them again, and can be nil.
+func render(builds map[string]build, clustered nestedFailuresGroups, digests map[string]string, maxFailureTextLength int) jsonOutput {
 	clusteredSorted := clustered.sortByMostAggregatedFailures()
 
 	flattenedClusters := make([]flattenedGlobalCluster, len(clusteredSorted))
@@ -49,9 +50,14 @@ func render(builds map[string]build, clustered nestedFailuresGroups, maxFailureT
 		k := pair.Key
 		clusters := pair.Group
 
+		digest, ok := digests[k]
+		if !ok {
+			digest = makeNgramCountsDigest(k)
+		}
+
 		flattenedClusters[i] = flattenedGlobalCluster{
 			k,
-			makeNgramCountsDigest(k),
+			digest,
 			clusters.sortByMostFailures(),
 		}
 	}
@@ -219,21 +225,28 @@ func annotateOwners(data *jsonOutput, builds map[string]build, owners map[string
 // If parameters prefix and owner are both the empty string, the function will return empty objects.
 func renderSlice(data jsonOutput, builds map[string]build, prefix string, owner string) ([]jsonCluster, columns) {
 	clustered := make([]jsonCluster, 0)
-	// Maps build paths to builds
-	buildsOut := make(map[string]build)
-	jobs := make(sets.String)
 
-	// For each cluster whose owner field is the owner parameter, or whose id field has a prefix of
-	// the prefix parameter, add its tests' jobs to the jobs set.
+	// Find each cluster whose owner field is the owner parameter, or whose id field has a prefix of
+	// the prefix parameter.
 	for _, cluster := range data.Clustered {
 		if owner != "" && cluster.Owner == owner {
 			clustered = append(clustered, cluster)
 		} else if prefix != "" && strings.HasPrefix(cluster.ID, prefix) {
 			clustered = append(clustered, cluster)
-		} else {
-			continue
 		}
+	}
+
+	return clustered, clusterBuildsToColumns(clustered, builds)
+}
+
+// clusterBuildsToColumns returns the columnar form of the builds of the jobs belonging to clusters.
+func clusterBuildsToColumns(clusters []jsonCluster, builds map[string]build) columns {
+	// Maps build paths to builds
+	buildsOut := make(map[string]build)
+	jobs := make(sets.String)
 
+	// Add the clusters' tests' jobs to the jobs set.
+	for _, cluster := range clusters {
 		for _, tst := range cluster.Tests {
 			for _, jb := range tst.Jobs {
 				jobs.Insert(jb.Name)
@@ -248,7 +261,7 @@ func renderSlice(data jsonOutput, builds map[string]build, prefix string, owner
 		}
 	}
 
-	return clustered, buildsToColumns(buildsOut)
+	return buildsToColumns(buildsOut)
 }
 
 // flattenedGlobalCluster is the key and value of a specific global cluster (as clusterText and
@@ -274,14 +287,24 @@ jsonCluster represents a global cluster as it will be written to the JSON.
 	spans: common spans between all of the cluster's failure texts
 	tests: the build numbers that belong to the cluster's failures as per testGroupByJob()
 	owner: the SIG that owns the cluster, determined by annotateOwners()
+	first_seen: the start time of the earliest build the cluster is known to have failed in,
+	            determined by annotateTrends()
+	delta: the failures in the last day minus the failures in the day before, determined by
+	       annotateTrends()
+	trend: whether the cluster is new, spiking, stable or fading, determined by annotateTrends()
+	hints: the builds around the cluster's failures, see clusterHints, determined by annotateHints()
 */
 type jsonCluster struct {
-	Key   string `json:"key"`
-	ID    string `json:"id"`
-	Text  string `json:"text"`
-	Spans []int  `json:"spans"`
-	Tests []test `json:"tests"`
-	Owner string `json:"owner"`
+	Key       string       `json:"key"`
+	ID        string       `json:"id"`
+	Text      string       `json:"text"`
+	Spans     []int        `json:"spans"`
+	Tests     []test       `json:"tests"`
+	Owner     string       `json:"owner"`
+	FirstSeen int          `json:"first_seen,omitempty"`
+	Delta     int          `json:"delta"`
+	Trend     string       `json:"trend,omitempty"`
+	Hints     clusterHints `json:"hints"`
 }
 
 // clustersToDisplay transposes and sorts the flattened output of clusterGlobal.
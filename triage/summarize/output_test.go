@@ -83,3 +83,26 @@ func TestAnnotateOwnersEmptyParams(t *testing.T) {
 		return
 	}
 }
+
+func TestRenderDigests(t *testing.T) {
+	builds := map[string]build{"gs://bucket/logs/job/1": {Path: "gs://bucket/logs/job/1", Started: 1000, Job: "job", Number: 1}}
+	flr := failure{Started: 1000, Build: "gs://bucket/logs/job/1", Name: "test a", FailureText: "some failure"}
+	// Clusters with a single failure are not displayed
+	clustered := nestedFailuresGroups{
+		"stored text": failuresGroup{"test a": {flr, flr}},
+		"new text":    failuresGroup{"test a": {flr, flr}},
+	}
+
+	// Stored digests are used as the cluster IDs, and the others are computed
+	data := render(builds, clustered, map[string]string{"stored text": "stored"}, defaultMaxFailureTextLength)
+	ids := make(map[string]string)
+	for _, cluster := range data.Clustered {
+		ids[cluster.Key] = cluster.ID
+	}
+	if ids["stored text"] != "stored" {
+		t.Errorf("ID of the cluster with a stored digest = %q, wanted %q", ids["stored text"], "stored")
+	}
+	if want := makeNgramCountsDigest("new text"); ids["new text"] != want {
+		t.Errorf("ID of the new cluster = %q, wanted %q", ids["new text"], want)
+	}
+}
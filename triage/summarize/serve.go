@@ -0,0 +1,516 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Contains the HTTP server of 'triage serve', which loads a clustered output into memory and answers
+queries about it as JSON, so that clients don't need to download and filter the whole output.
+*/
+
+package summarize
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// defaultQueryLimit is how many clusters a query returns when it doesn't set a limit.
+const defaultQueryLimit = 100
+
+// serveFlags represents the command-line arguments to 'triage serve' and their values.
+type serveFlags struct {
+	output  string
+	address string
+}
+
+// parseServeFlags parses the command-line arguments following 'serve' and returns them as a
+// serveFlags object.
+func parseServeFlags(args []string) serveFlags {
+	var flags serveFlags
+
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.StringVar(&flags.output, "output", "failure_data.json", "path to the output to serve; it is reloaded whenever it changes")
+	fs.StringVar(&flags.address, "address", ":8080", "address to listen on")
+	// Parsing exits on error
+	_ = fs.Parse(args)
+
+	return flags
+}
+
+/*
+clusterIndex holds an output in memory, with the maps needed to answer queries about it.
+
+byID maps cluster IDs to indexes in data.Clustered.
+
+buildIndexes maps job names and build numbers to indexes in the columnar builds of data.
+*/
+type clusterIndex struct {
+	data         jsonOutput
+	byID         map[string]int
+	buildIndexes map[string]map[int]int
+}
+
+// newClusterIndex indexes data.
+func newClusterIndex(data jsonOutput) (*clusterIndex, error) {
+	index := &clusterIndex{
+		data:         data,
+		byID:         make(map[string]int, len(data.Clustered)),
+		buildIndexes: make(map[string]map[int]int, len(data.Builds.Jobs)),
+	}
+	for i, cluster := range data.Clustered {
+		index.byID[cluster.ID] = i
+	}
+
+	for jobName, collection := range data.Builds.Jobs {
+		indexes, err := decodeJobCollection(collection)
+		if err != nil {
+			return nil, fmt.Errorf("Could not decode the builds of job '%s': %s", jobName, err)
+		}
+		index.buildIndexes[jobName] = indexes
+	}
+
+	return index, nil
+}
+
+/*
+decodeJobCollection turns a jobCollection into a map from build numbers to indexes in the columnar
+builds. The collection is either as created by buildsToColumns, or as it is after a round trip
+through JSON, where numbers become float64 and map keys become strings.
+*/
+func decodeJobCollection(collection jobCollection) (map[int]int, error) {
+	// Expands the condensed form of [first build number, count, first index]
+	expand := func(first, count, base int) map[int]int {
+		indexes := make(map[int]int, count)
+		for i := 0; i < count; i++ {
+			indexes[first+i] = base + i
+		}
+		return indexes
+	}
+
+	switch c := collection.(type) {
+	case map[int]int:
+		return c, nil
+	case []int:
+		if len(c) != 3 {
+			return nil, fmt.Errorf("condensed form has %d elements, not 3", len(c))
+		}
+		return expand(c[0], c[1], c[2]), nil
+	case map[string]interface{}:
+		indexes := make(map[int]int, len(c))
+		for number, index := range c {
+			n, err := strconv.Atoi(number)
+			if err != nil {
+				return nil, fmt.Errorf("invalid build number '%s'", number)
+			}
+			i, ok := index.(float64)
+			if !ok {
+				return nil, fmt.Errorf("invalid index %#v of build %d", index, n)
+			}
+			indexes[n] = int(i)
+		}
+		return indexes, nil
+	case []interface{}:
+		if len(c) != 3 {
+			return nil, fmt.Errorf("condensed form has %d elements, not 3", len(c))
+		}
+		values := make([]int, 3)
+		for i, v := range c {
+			f, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("invalid element %#v of condensed form", v)
+			}
+			values[i] = int(f)
+		}
+		return expand(values[0], values[1], values[2]), nil
+	default:
+		return nil, fmt.Errorf("unknown form %#v", collection)
+	}
+}
+
+// build returns the build with the given number of the given job, if it is in the output.
+func (index *clusterIndex) build(jobName string, number string) (build, bool) {
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return build{}, false
+	}
+	i, ok := index.buildIndexes[jobName][n]
+	if !ok {
+		return build{}, false
+	}
+
+	cols := index.data.Builds.Cols
+	if i < 0 || i >= len(cols.Started) {
+		return build{}, false
+	}
+	return build{
+		Path:        fmt.Sprintf("%s/%d", index.data.Builds.JobPaths[jobName], n),
+		Started:     cols.Started[i],
+		Elapsed:     cols.Elapsed[i],
+		TestsRun:    cols.TestsRun[i],
+		TestsFailed: cols.TestsFailed[i],
+		Result:      cols.Result[i],
+		Executor:    cols.Executor[i],
+		Job:         jobName,
+		Number:      n,
+		PR:          cols.PR[i],
+	}, true
+}
+
+/*
+clusterQuery holds the parameters of a query.
+
+text and textRE match against cluster keys, and owner against cluster owners; clusters that don't
+match are left out. job, test, since and until match against the failures of a cluster, which are
+narrowed down to those that match; clusters with no matching failures are left out. test matches
+test names containing it, and since and until are inclusive bounds on the start time of failed
+builds, in seconds since the epoch, where 0 means unbounded.
+*/
+type clusterQuery struct {
+	text   string
+	textRE *regexp.Regexp
+	job    string
+	test   string
+	owner  string
+	since  int
+	until  int
+	limit  int
+}
+
+// parseClusterQuery parses the parameters of a query from the query string of a request.
+func parseClusterQuery(values map[string][]string) (clusterQuery, error) {
+	get := func(key string) string {
+		if v := values[key]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	getInt := func(key string, def int) (int, error) {
+		s := get(key)
+		if s == "" {
+			return def, nil
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("'%s' must be a non-negative integer, not '%s'", key, s)
+		}
+		return n, nil
+	}
+
+	query := clusterQuery{
+		text:  get("text"),
+		job:   get("job"),
+		test:  get("test"),
+		owner: get("owner"),
+	}
+
+	var err error
+	if re := get("regex"); re != "" {
+		query.textRE, err = regexp.Compile(re)
+		if err != nil {
+			return clusterQuery{}, fmt.Errorf("Invalid regex: %s", err)
+		}
+	}
+	if query.since, err = getInt("since", 0); err != nil {
+		return clusterQuery{}, err
+	}
+	if query.until, err = getInt("until", 0); err != nil {
+		return clusterQuery{}, err
+	}
+	if query.limit, err = getInt("limit", defaultQueryLimit); err != nil {
+		return clusterQuery{}, err
+	}
+
+	return query, nil
+}
+
+// narrowsFailures returns whether the query narrows down the failures of the clusters it matches.
+func (query clusterQuery) narrowsFailures() bool {
+	return query.job != "" || query.test != "" || query.since != 0 || query.until != 0
+}
+
+/*
+match returns the cluster narrowed down to the failures that match query, and whether the cluster
+matches at all.
+*/
+func (index *clusterIndex) match(cluster jsonCluster, query clusterQuery) (jsonCluster, bool) {
+	if query.text != "" && !strings.Contains(cluster.Key, query.text) {
+		return jsonCluster{}, false
+	}
+	if query.textRE != nil && !query.textRE.MatchString(cluster.Key) {
+		return jsonCluster{}, false
+	}
+	if query.owner != "" && cluster.Owner != query.owner {
+		return jsonCluster{}, false
+	}
+	if !query.narrowsFailures() {
+		return cluster, true
+	}
+
+	tests := make([]test, 0, len(cluster.Tests))
+	for _, tst := range cluster.Tests {
+		if query.test != "" && !strings.Contains(tst.Name, query.test) {
+			continue
+		}
+
+		jobs := make([]job, 0, len(tst.Jobs))
+		for _, jb := range tst.Jobs {
+			if query.job != "" && jb.Name != query.job {
+				continue
+			}
+
+			numbers := make([]string, 0, len(jb.BuildNumbers))
+			for _, number := range jb.BuildNumbers {
+				if query.since != 0 || query.until != 0 {
+					bld, ok := index.build(jb.Name, number)
+					if !ok || (query.since != 0 && bld.Started < query.since) || (query.until != 0 && bld.Started > query.until) {
+						continue
+					}
+				}
+				numbers = append(numbers, number)
+			}
+			if len(numbers) > 0 {
+				jobs = append(jobs, job{jb.Name, numbers})
+			}
+		}
+		if len(jobs) > 0 {
+			tests = append(tests, test{tst.Name, jobs})
+		}
+	}
+	if len(tests) == 0 {
+		return jsonCluster{}, false
+	}
+
+	// Don't modify the tests of the indexed cluster
+	cluster.Tests = tests
+	return cluster, true
+}
+
+// clustersResponse is the response to a query for clusters.
+type clustersResponse struct {
+	// Total is the number of matching clusters, of which at most the query's limit are returned
+	Total    int           `json:"total"`
+	Clusters []jsonCluster `json:"clusters"`
+}
+
+// query returns the clusters matching query, in the order of the output.
+func (index *clusterIndex) query(query clusterQuery) clustersResponse {
+	response := clustersResponse{Clusters: make([]jsonCluster, 0)}
+	for _, cluster := range index.data.Clustered {
+		matched, ok := index.match(cluster, query)
+		if !ok {
+			continue
+		}
+		response.Total++
+		if len(response.Clusters) < query.limit {
+			response.Clusters = append(response.Clusters, matched)
+		}
+	}
+	return response
+}
+
+// clusterBuilds returns the builds that the failures of cluster belong to, most recent first.
+func (index *clusterIndex) clusterBuilds(cluster jsonCluster) []build {
+	seen := make(map[string]bool)
+	builds := make([]build, 0)
+	for _, tst := range cluster.Tests {
+		for _, jb := range tst.Jobs {
+			for _, number := range jb.BuildNumbers {
+				bld, ok := index.build(jb.Name, number)
+				if !ok || seen[bld.Path] {
+					continue
+				}
+				seen[bld.Path] = true
+				builds = append(builds, bld)
+			}
+		}
+	}
+
+	sortBuildsByStarted(builds)
+	return builds
+}
+
+// sortBuildsByStarted sorts builds by their start time, most recent first, then by path.
+func sortBuildsByStarted(builds []build) {
+	sort.Slice(builds, func(i, j int) bool {
+		if builds[i].Started == builds[j].Started {
+			return builds[i].Path < builds[j].Path
+		}
+		return builds[i].Started > builds[j].Started
+	})
+}
+
+/*
+clusterServer serves the API over the output at path, which it reloads whenever its modification
+time changes, so that it can keep serving the output of the latest run.
+
+	GET /api/clusters?text=&regex=&job=&test=&owner=&since=&until=&limit=
+		the clusters matching the query, see clusterQuery
+	GET /api/clusters/<id>?job=&test=&since=&until=
+		the cluster with the given ID
+	GET /api/clusters/<id>/builds?job=&test=&since=&until=
+		the builds that the cluster with the given ID failed in
+*/
+type clusterServer struct {
+	path string
+
+	lock    sync.Mutex
+	index   *clusterIndex
+	modTime time.Time
+}
+
+// currentIndex returns the index of the output, which is loaded again if it has changed.
+func (s *clusterServer) currentIndex() (*clusterIndex, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		if s.index != nil {
+			klog.Warningf("Could not check output for changes, serving the loaded output: %s", err)
+			return s.index, nil
+		}
+		return nil, fmt.Errorf("Could not find output: %s", err)
+	}
+	if s.index != nil && info.ModTime().Equal(s.modTime) {
+		return s.index, nil
+	}
+
+	klog.V(2).Infof("Loading output from %s", s.path)
+	var data jsonOutput
+	if err := getJSON(s.path, &data); err != nil {
+		if s.index != nil {
+			klog.Warningf("Could not load changed output, serving the previous output: %s", err)
+			return s.index, nil
+		}
+		return nil, err
+	}
+	index, err := newClusterIndex(data)
+	if err != nil {
+		return nil, err
+	}
+	klog.V(2).Infof("Loaded %d clusters", len(data.Clustered))
+
+	s.index, s.modTime = index, info.ModTime()
+	return s.index, nil
+}
+
+func (s *clusterServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("Method %s is not allowed", r.Method))
+		return
+	}
+
+	index, err := s.currentIndex()
+	if err != nil {
+		klog.Errorf("Could not load output: %s", err)
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("The output is not available"))
+		return
+	}
+	serveIndex(w, r, index)
+}
+
+// serveIndex answers the request r about index.
+func serveIndex(w http.ResponseWriter, r *http.Request, index *clusterIndex) {
+	query, err := parseClusterQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if path == "/api/clusters" {
+		writeJSONResponse(w, index.query(query))
+		return
+	}
+
+	rest := strings.TrimPrefix(path, "/api/clusters/")
+	if rest == path || rest == "" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("Unknown path '%s'", r.URL.Path))
+		return
+	}
+	id, wantBuilds := strings.CutSuffix(rest, "/builds")
+	if strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, fmt.Errorf("Unknown path '%s'", r.URL.Path))
+		return
+	}
+
+	i, ok := index.byID[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("No cluster with ID '%s'", id))
+		return
+	}
+	// Only the failure filters of the query apply to a single cluster
+	cluster, ok := index.match(index.data.Clustered[i], clusterQuery{job: query.job, test: query.test, since: query.since, until: query.until})
+	if !ok {
+		cluster = index.data.Clustered[i]
+		cluster.Tests = make([]test, 0)
+	}
+
+	if wantBuilds {
+		writeJSONResponse(w, index.clusterBuilds(cluster))
+	} else {
+		writeJSONResponse(w, cluster)
+	}
+}
+
+// writeJSONResponse writes v as the JSON body of a successful response.
+func writeJSONResponse(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		klog.Warningf("Could not write response: %s", err)
+	}
+}
+
+// writeError writes err as the JSON body of a response with the given status.
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(map[string]string{"error": err.Error()}); err != nil {
+		klog.Warningf("Could not write response: %s", err)
+	}
+}
+
+func serve(flags serveFlags) {
+	setUpLogging(true, 2)
+
+	server := &clusterServer{path: flags.output}
+	// Load the output up front, so that a bad path is caught at startup
+	if _, err := server.currentIndex(); err != nil {
+		klog.Fatalf("Could not load output: %s", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/clusters", server)
+	mux.Handle("/api/clusters/", server)
+
+	klog.V(0).Infof("Serving %s on %s", flags.output, flags.address)
+	klog.Fatal(http.ListenAndServe(flags.address, mux))
+}
+
+// Serve runs 'triage serve' with the command-line arguments following 'serve'.
+func Serve(args []string) {
+	serve(parseServeFlags(args))
+}
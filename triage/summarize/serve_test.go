@@ -0,0 +1,197 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summarize
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestClusterServer(t *testing.T) {
+	// job-a has dense build numbers and job-b sparse ones, so that both forms of jobCollection are
+	// read back
+	builds := map[string]build{
+		"gs://logs/job-a/1": {Path: "gs://logs/job-a/1", Job: "job-a", Number: 1, Started: 100, Result: "FAILURE"},
+		"gs://logs/job-a/2": {Path: "gs://logs/job-a/2", Job: "job-a", Number: 2, Started: 200, Result: "FAILURE"},
+		"gs://logs/job-a/3": {Path: "gs://logs/job-a/3", Job: "job-a", Number: 3, Started: 300, Result: "SUCCESS"},
+		"gs://logs/job-b/1": {Path: "gs://logs/job-b/1", Job: "job-b", Number: 1, Started: 150, Result: "FAILURE", PR: "12"},
+		"gs://logs/job-b/5": {Path: "gs://logs/job-b/5", Job: "job-b", Number: 5, Started: 250, Result: "FAILURE"},
+	}
+	timeout := jsonCluster{
+		Key:   "timed out waiting for pod",
+		ID:    "id-timeout",
+		Owner: "node",
+		Tests: []test{
+			{Name: "[sig-node] pods start", Jobs: []job{{"job-a", []string{"2", "1"}}, {"job-b", []string{"5"}}}},
+			{Name: "[sig-node] pods stop", Jobs: []job{{"job-b", []string{"1"}}}},
+		},
+	}
+	panicked := jsonCluster{
+		Key:   "panic: runtime error",
+		ID:    "id-panic",
+		Owner: "apps",
+		Tests: []test{{Name: "[sig-apps] deployments", Jobs: []job{{"job-a", []string{"1"}}}}},
+	}
+	data := jsonOutput{Clustered: []jsonCluster{timeout, panicked}, Builds: buildsToColumns(builds)}
+
+	// Serve the output from a file, as it would be served
+	path := filepath.Join(t.TempDir(), "failure_data.json")
+	if err := writeResults(path, data); err != nil {
+		t.Fatalf("writeResults() failed: %s", err)
+	}
+	server := httptest.NewServer(&clusterServer{path: path})
+	defer server.Close()
+
+	get := func(url string, wantStatus int, v interface{}) {
+		t.Helper()
+		resp, err := http.Get(server.URL + url)
+		if err != nil {
+			t.Fatalf("GET %s failed: %s", url, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != wantStatus {
+			t.Errorf("GET %s returned status %d, wanted %d", url, resp.StatusCode, wantStatus)
+			return
+		}
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Errorf("GET %s returned invalid JSON: %s", url, err)
+		}
+	}
+
+	clustersCases := []struct {
+		name      string
+		url       string
+		wantTotal int
+		want      []jsonCluster
+	}{
+		{
+			name:      "All clusters",
+			url:       "/api/clusters",
+			wantTotal: 2,
+			want:      []jsonCluster{timeout, panicked},
+		},
+		{
+			name:      "Limited",
+			url:       "/api/clusters?limit=1",
+			wantTotal: 2,
+			want:      []jsonCluster{timeout},
+		},
+		{
+			name:      "Text",
+			url:       "/api/clusters?text=panic",
+			wantTotal: 1,
+			want:      []jsonCluster{panicked},
+		},
+		{
+			name:      "Regex",
+			url:       "/api/clusters?regex=^timed%20out",
+			wantTotal: 1,
+			want:      []jsonCluster{timeout},
+		},
+		{
+			name:      "Owner",
+			url:       "/api/clusters?owner=apps",
+			wantTotal: 1,
+			want:      []jsonCluster{panicked},
+		},
+		{
+			name:      "Job narrows failures",
+			url:       "/api/clusters?job=job-b",
+			wantTotal: 1,
+			want: []jsonCluster{{
+				Key:   timeout.Key,
+				ID:    timeout.ID,
+				Owner: timeout.Owner,
+				Tests: []test{
+					{Name: "[sig-node] pods start", Jobs: []job{{"job-b", []string{"5"}}}},
+					{Name: "[sig-node] pods stop", Jobs: []job{{"job-b", []string{"1"}}}},
+				},
+			}},
+		},
+		{
+			name:      "Test and time range narrow failures",
+			url:       "/api/clusters?test=pods%20start&since=200&until=250",
+			wantTotal: 1,
+			want: []jsonCluster{{
+				Key:   timeout.Key,
+				ID:    timeout.ID,
+				Owner: timeout.Owner,
+				Tests: []test{{Name: "[sig-node] pods start", Jobs: []job{{"job-a", []string{"2"}}, {"job-b", []string{"5"}}}}},
+			}},
+		},
+		{
+			name:      "Nothing matches",
+			url:       "/api/clusters?since=1000",
+			wantTotal: 0,
+			want:      []jsonCluster{},
+		},
+	}
+	for _, tc := range clustersCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got clustersResponse
+			get(tc.url, http.StatusOK, &got)
+			if got.Total != tc.wantTotal || !reflect.DeepEqual(got.Clusters, tc.want) {
+				t.Errorf("GET %s = %#v, wanted %d clusters in total and %#v", tc.url, got, tc.wantTotal, tc.want)
+			}
+		})
+	}
+
+	var gotCluster jsonCluster
+	get("/api/clusters/id-panic", http.StatusOK, &gotCluster)
+	if !reflect.DeepEqual(gotCluster, panicked) {
+		t.Errorf("GET cluster = %#v, wanted %#v", gotCluster, panicked)
+	}
+
+	var gotBuilds []build
+	get("/api/clusters/id-timeout/builds?until=200", http.StatusOK, &gotBuilds)
+	wantBuilds := []build{builds["gs://logs/job-a/2"], builds["gs://logs/job-b/1"], builds["gs://logs/job-a/1"]}
+	if !reflect.DeepEqual(gotBuilds, wantBuilds) {
+		t.Errorf("GET cluster builds = %#v, wanted %#v", gotBuilds, wantBuilds)
+	}
+
+	var gotError map[string]string
+	get("/api/clusters/id-unknown", http.StatusNotFound, &gotError)
+	get("/api/clusters?regex=(", http.StatusBadRequest, &gotError)
+	get("/api/clusters?limit=-1", http.StatusBadRequest, &gotError)
+	get("/api/other", http.StatusNotFound, &gotError)
+}
+
+func TestClusterServerReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "failure_data.json")
+	server := &clusterServer{path: path}
+
+	if _, err := server.currentIndex(); err == nil {
+		t.Errorf("currentIndex() succeeded without an output")
+	}
+
+	first := jsonOutput{Clustered: []jsonCluster{{Key: "first", ID: "1"}}, Builds: buildsToColumns(nil)}
+	if err := writeResults(path, first); err != nil {
+		t.Fatalf("writeResults() failed: %s", err)
+	}
+	index, err := server.currentIndex()
+	if err != nil || len(index.data.Clustered) != 1 {
+		t.Fatalf("currentIndex() = %v, %v, wanted the first output", index, err)
+	}
+
+	if index2, _ := server.currentIndex(); index2 != index {
+		t.Errorf("currentIndex() loaded the output again although it is unchanged")
+	}
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Contains the sources that builds and test failures can be loaded from: the JSON files exported from
+BigQuery, or a tree of Prow job artifacts.
+*/
+
+package summarize
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// source provides the builds and test failures to summarize.
+type source interface {
+	// load returns a map from build paths to builds, and a map from test names to the failures of
+	// that test, sorted by build.
+	load() (map[string]build, map[string][]failure, error)
+}
+
+// bigQuerySource loads builds and failures from the JSON files exported from BigQuery by
+// update_summaries.sh.
+type bigQuerySource struct {
+	buildsFilepath string
+	testsFilepaths []string
+	memoize        bool
+}
+
+func (s bigQuerySource) load() (map[string]build, map[string][]failure, error) {
+	return loadFailures(s.buildsFilepath, s.testsFilepaths, s.memoize)
+}
+
+/*
+prowArtifactsSource loads builds and failures from a tree of Prow job artifacts, as uploaded by
+Prow's sidecar, so that triage can run without the Kettle and BigQuery pipeline. Every directory
+with both a started.json and a finished.json is a build, and the junit_*.xml files anywhere below it
+hold its test results.
+
+fsys holds the artifacts, and location is where they came from, which prefixes the build paths.
+Builds started before oldest, in seconds since the epoch, are skipped, unless it is 0.
+*/
+type prowArtifactsSource struct {
+	location string
+	fsys     fs.FS
+	oldest   int
+}
+
+// remoteArtifactsRE matches the artifact locations that are downloaded with gsutil.
+var remoteArtifactsRE = regexp.MustCompile(`^(gs|s3)://`)
+
+// artifactFilesRE matches the artifact files that are needed to summarize builds, so that nothing
+// else needs to be downloaded.
+var artifactFilesRE = regexp.MustCompile(`(^|/)(started\.json|finished\.json|junit[^/]*\.xml)$`)
+
+/*
+newProwArtifactsSource creates a source for the Prow artifacts at location, which is either a local
+directory or a gs:// or s3:// URL. Remote artifacts are first downloaded to a temporary directory
+with gsutil, which needs to be configured with credentials for S3, and the returned cleanup
+function removes it. Builds started before oldest are skipped, as for prowArtifactsSource.
+*/
+func newProwArtifactsSource(location string, oldest int) (src prowArtifactsSource, cleanup func(), err error) {
+	location = strings.TrimSuffix(location, "/")
+	if !remoteArtifactsRE.MatchString(location) {
+		dir := strings.TrimPrefix(location, "file://")
+		return prowArtifactsSource{location, os.DirFS(dir), oldest}, func() {}, nil
+	}
+
+	dir, err := os.MkdirTemp("", "triage-artifacts")
+	if err != nil {
+		return prowArtifactsSource{}, nil, fmt.Errorf("Could not create directory for artifacts: %s", err)
+	}
+	cleanup = func() { os.RemoveAll(dir) }
+
+	klog.V(2).Infof("Downloading artifacts from %s...", location)
+	// Exclude (with a negative lookahead, which gsutil's Python regular expressions support)
+	// everything that isn't needed
+	exclude := `^(?!(.*/)?(started\.json|finished\.json|junit[^/]*\.xml)$)`
+	cmd := exec.Command("gsutil", "-m", "-q", "rsync", "-r", "-x", exclude, location, dir)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		cleanup()
+		return prowArtifactsSource{}, nil, fmt.Errorf("Could not download artifacts from '%s': %s", location, err)
+	}
+
+	return prowArtifactsSource{location, os.DirFS(dir), oldest}, cleanup, nil
+}
+
+// prowStarted holds the fields of a started.json that triage uses.
+type prowStarted struct {
+	Timestamp int    `json:"timestamp"`
+	Node      string `json:"node"`
+}
+
+// prowFinished holds the fields of a finished.json that triage uses.
+type prowFinished struct {
+	Timestamp int    `json:"timestamp"`
+	Passed    *bool  `json:"passed"`
+	Result    string `json:"result"`
+}
+
+// junitSuite is a <testsuite> or <testsuites> element, which can be nested.
+type junitSuite struct {
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []junitCase  `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+	Errors    []junitFailure `xml:"error"`
+	Skipped   *struct{}      `xml:"skipped"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (s prowArtifactsSource) load() (map[string]build, map[string][]failure, error) {
+	// Find the build directories and all junit files, which are matched up afterwards
+	buildDirs := make(map[string]bool)
+	var junitFiles []string
+	err := fs.WalkDir(s.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !artifactFilesRE.MatchString(p) {
+			return nil
+		}
+		if d.Name() == "started.json" {
+			buildDirs[path.Dir(p)] = true
+		} else if strings.HasSuffix(p, ".xml") {
+			junitFiles = append(junitFiles, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Could not list artifacts in '%s': %s", s.location, err)
+	}
+
+	junitByBuild := make(map[string][]string)
+	for _, p := range junitFiles {
+		for dir := path.Dir(p); ; dir = path.Dir(dir) {
+			if buildDirs[dir] {
+				junitByBuild[dir] = append(junitByBuild[dir], p)
+				break
+			}
+			if dir == "." {
+				break
+			}
+		}
+	}
+
+	builds := make(map[string]build)
+	tests := make(map[string][]failure)
+	for dir := range buildDirs {
+		bld, ok, err := s.loadBuild(dir)
+		if err != nil {
+			// Nor should a single bad build
+			klog.Warningf("Could not load build '%s': %s", dir, err)
+			continue
+		}
+		if !ok {
+			klog.V(3).Infof("Skipping unfinished build %s", dir)
+			continue
+		}
+		if bld.Started < s.oldest {
+			klog.V(3).Infof("Skipping build %s, which is too old", dir)
+			continue
+		}
+
+		for _, p := range junitByBuild[dir] {
+			run, failures, err := s.loadJUnit(p, bld)
+			if err != nil {
+				// A single bad file shouldn't prevent triage from running
+				klog.Warningf("Could not load test results from '%s': %s", p, err)
+				continue
+			}
+			bld.TestsRun += run
+			bld.TestsFailed += len(failures)
+			for _, flr := range failures {
+				tests[flr.Name] = append(tests[flr.Name], flr)
+			}
+		}
+
+		builds[bld.Path] = bld
+	}
+
+	// Sort the failures within each test by build, as loadTests does
+	for _, testSlice := range tests {
+		sort.Slice(testSlice, func(i, j int) bool { return testSlice[i].Build < testSlice[j].Build })
+	}
+
+	klog.V(2).Infof("Loaded %d builds and %d failed tests from %s", len(builds), len(tests), s.location)
+	return builds, tests, nil
+}
+
+// loadBuild creates the build for the build directory dir. ok is false if the build has no
+// finished.json yet.
+func (s prowArtifactsSource) loadBuild(dir string) (bld build, ok bool, err error) {
+	var started prowStarted
+	if err := s.readJSON(path.Join(dir, "started.json"), &started); err != nil {
+		return build{}, false, err
+	}
+	var finished prowFinished
+	if err := s.readJSON(path.Join(dir, "finished.json"), &finished); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return build{}, false, nil
+		}
+		return build{}, false, err
+	}
+
+	bld = build{
+		Path:     s.location + "/" + dir,
+		Started:  started.Timestamp,
+		Executor: started.Node,
+		Job:      path.Base(path.Dir(dir)),
+		Result:   finished.Result,
+	}
+	if finished.Timestamp > started.Timestamp {
+		bld.Elapsed = finished.Timestamp - started.Timestamp
+	}
+	if bld.Result == "" && finished.Passed != nil {
+		bld.Result = "FAILURE"
+		if *finished.Passed {
+			bld.Result = "SUCCESS"
+		}
+	}
+	bld.Number, err = strconv.Atoi(path.Base(dir))
+	if err != nil {
+		return build{}, false, fmt.Errorf("Build directory '%s' is not named after a build number", dir)
+	}
+	// Presubmit builds are stored under pr-logs/pull/<repo>/<pr>/<job>/<build>
+	if strings.Contains(bld.Path, "pr-logs") {
+		parts := strings.Split(bld.Path, "/")
+		bld.PR = parts[len(parts)-3]
+	}
+
+	return bld, true, nil
+}
+
+// loadJUnit reads the test results in the junit file at p for bld, and returns the number of tests
+// that were run and the failures.
+func (s prowArtifactsSource) loadJUnit(p string, bld build) (run int, failures []failure, err error) {
+	contents, err := fs.ReadFile(s.fsys, p)
+	if err != nil {
+		return 0, nil, err
+	}
+	var suite junitSuite
+	if err := xml.Unmarshal(contents, &suite); err != nil {
+		return 0, nil, fmt.Errorf("Could not unmarshal junit XML: %s", err)
+	}
+
+	var walk func(suite junitSuite)
+	walk = func(suite junitSuite) {
+		for _, tc := range suite.Cases {
+			if tc.Skipped != nil {
+				continue
+			}
+			run++
+
+			problems := append(tc.Failures, tc.Errors...)
+			if len(problems) == 0 {
+				continue
+			}
+			name := tc.Name
+			if tc.ClassName != "" {
+				name = tc.ClassName + " " + name
+			}
+			texts := make([]string, 0, len(problems))
+			for _, problem := range problems {
+				text := strings.TrimSpace(problem.Text)
+				if text == "" {
+					text = problem.Message
+				}
+				texts = append(texts, text)
+			}
+			failures = append(failures, failure{
+				Started:     bld.Started,
+				Build:       bld.Path,
+				Name:        name,
+				FailureText: strings.Join(texts, "\n"),
+			})
+		}
+		for _, child := range suite.Suites {
+			walk(child)
+		}
+	}
+	walk(suite)
+
+	return run, failures, nil
+}
+
+func (s prowArtifactsSource) readJSON(p string, v interface{}) error {
+	contents, err := fs.ReadFile(s.fsys, p)
+	if err != nil {
+		return fmt.Errorf("Could not read '%s': %w", p, err)
+	}
+	if err := json.Unmarshal(contents, v); err != nil {
+		return fmt.Errorf("Could not unmarshal '%s': %s", p, err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summarize
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestProwArtifactsSource(t *testing.T) {
+	file := func(s string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(s)} }
+
+	fsys := fstest.MapFS{
+		// A periodic build with a failure and an error, in nested suites
+		"logs/ci-job/10/started.json":  file(`{"timestamp": 1000, "node": "node-1"}`),
+		"logs/ci-job/10/finished.json": file(`{"timestamp": 1600, "passed": false, "result": "FAILURE"}`),
+		"logs/ci-job/10/artifacts/junit_01.xml": file(`<testsuites>
+  <testsuite name="e2e">
+    <testcase name="passes" classname="suite"></testcase>
+    <testcase name="fails" classname="suite"><failure message="short">  the failure text  </failure></testcase>
+    <testcase name="skipped" classname="suite"><skipped></skipped></testcase>
+  </testsuite>
+</testsuites>`),
+		"logs/ci-job/10/artifacts/nested/junit_runner.xml": file(`<testsuite>
+  <testcase name="errors"><error message="only a message"></error></testcase>
+</testsuite>`),
+		// A passing presubmit build, whose finished.json has no result
+		"pr-logs/pull/org_repo/123/pr-job/7/started.json":            file(`{"timestamp": 2000}`),
+		"pr-logs/pull/org_repo/123/pr-job/7/finished.json":           file(`{"timestamp": 2010, "passed": true}`),
+		"pr-logs/pull/org_repo/123/pr-job/7/artifacts/junit_01.xml":  file(`<testsuite><testcase name="passes"></testcase></testsuite>`),
+		"pr-logs/pull/org_repo/123/pr-job/7/artifacts/other.xml":     file(`not junit`),
+		"pr-logs/pull/org_repo/123/pr-job/7/artifacts/build-log.txt": file(`ignored`),
+		"pr-logs/pull/org_repo/123/pr-job/7/artifacts/junit_bad.xml": file(`<testsuite><testcase`),
+		// A build that started too long ago
+		"logs/ci-job/9/started.json":           file(`{"timestamp": 400}`),
+		"logs/ci-job/9/finished.json":          file(`{"timestamp": 450, "passed": false, "result": "FAILURE"}`),
+		"logs/ci-job/9/artifacts/junit_01.xml": file(`<testsuite><testcase name="fails"><failure>x</failure></testcase></testsuite>`),
+		// Builds with a malformed started.json or a non-numeric directory
+		"logs/ci-job/12/started.json":      file(`{"timestamp": "soon"}`),
+		"logs/ci-job/12/finished.json":     file(`{"timestamp": 3100}`),
+		"logs/ci-job/latest/started.json":  file(`{"timestamp": 3000}`),
+		"logs/ci-job/latest/finished.json": file(`{"timestamp": 3100}`),
+		// A build that is still running
+		"logs/ci-job/11/started.json":           file(`{"timestamp": 3000}`),
+		"logs/ci-job/11/artifacts/junit_01.xml": file(`<testsuite><testcase name="fails"><failure>x</failure></testcase></testsuite>`),
+	}
+
+	builds, tests, err := prowArtifactsSource{"gs://bucket", fsys, 500}.load()
+	if err != nil {
+		t.Fatalf("load() failed: %s", err)
+	}
+
+	wantBuilds := map[string]build{
+		"gs://bucket/logs/ci-job/10": {
+			Path:        "gs://bucket/logs/ci-job/10",
+			Started:     1000,
+			Elapsed:     600,
+			TestsRun:    3,
+			TestsFailed: 2,
+			Result:      "FAILURE",
+			Executor:    "node-1",
+			Job:         "ci-job",
+			Number:      10,
+		},
+		"gs://bucket/pr-logs/pull/org_repo/123/pr-job/7": {
+			Path:     "gs://bucket/pr-logs/pull/org_repo/123/pr-job/7",
+			Started:  2000,
+			Elapsed:  10,
+			TestsRun: 1,
+			Result:   "SUCCESS",
+			Job:      "pr-job",
+			Number:   7,
+			PR:       "123",
+		},
+	}
+	if !reflect.DeepEqual(builds, wantBuilds) {
+		t.Errorf("builds = %#v, wanted %#v", builds, wantBuilds)
+	}
+
+	wantTests := map[string][]failure{
+		"suite fails": {{Started: 1000, Build: "gs://bucket/logs/ci-job/10", Name: "suite fails", FailureText: "the failure text"}},
+		"errors":      {{Started: 1000, Build: "gs://bucket/logs/ci-job/10", Name: "errors", FailureText: "only a message"}},
+	}
+	if !reflect.DeepEqual(tests, wantTests) {
+		t.Errorf("tests = %#v, wanted %#v", tests, wantTests)
+	}
+}
@@ -33,12 +33,19 @@ const defaultMaxFailureTextLength = 100000
 
 // summarizeFlags represents the command-line arguments to the summarize and their values.
 type summarizeFlags struct {
+	source               string
 	builds               string
 	tests                []string
+	artifacts            string
+	artifactsDays        int
 	previous             string
+	state                string
 	owners               string
+	normalizationRules   string
 	output               string
 	outputSlices         string
+	outputAlerts         string
+	outputFlakes         string
 	numWorkers           int
 	memoize              bool
 	maxClusterTextLength int
@@ -49,11 +56,18 @@ type summarizeFlags struct {
 func parseFlags() summarizeFlags {
 	var flags summarizeFlags
 
+	flag.StringVar(&flags.source, "source", "bigquery", "where to load builds and failures from: 'bigquery' for the builds file and tests files exported from BigQuery, or 'prow' for the Prow job artifacts at --artifacts")
 	flag.StringVar(&flags.builds, "builds", "", "path to builds.json file from BigQuery")
+	flag.StringVar(&flags.artifacts, "artifacts", "", "local directory, gs:// or s3:// URL of the Prow job artifacts to load, with --source=prow")
+	flag.IntVar(&flags.artifactsDays, "artifacts_days", 14, "only load the Prow builds started within this many days, with --source=prow; 0 loads them all")
 	flag.StringVar(&flags.previous, "previous", "", "path to previous output")
+	flag.StringVar(&flags.state, "state", "", "path to clustering state; if set, only failures that were not clustered by the previous run are clustered, and the state is updated")
 	flag.StringVar(&flags.owners, "owners", "", "path to test owner SIGs file")
+	flag.StringVar(&flags.normalizationRules, "normalization_rules", "", "path to a file of project-specific regex replacements to apply to failure texts before clustering")
 	flag.StringVar(&flags.output, "output", "failure_data.json", "output path")
 	flag.StringVar(&flags.outputSlices, "output_slices", "", "path to slices output (must include PREFIX in template)")
+	flag.StringVar(&flags.outputAlerts, "output_alerts", "", "path to alerts output, with the new and spiking clusters and those resolved since the previous output")
+	flag.StringVar(&flags.outputFlakes, "output_flakes", "", "path to flaky tests report output, in the format read by the flakyjob-reporter of robots/issue-creator")
 	flag.IntVar(&flags.numWorkers, "num_workers", 2*runtime.NumCPU()-1, "number of worker goroutines to spawn for parallelized functions") // This has shown to be a sensible number of workers
 	flag.BoolVar(&flags.memoize, "memoize", false, "whether to memoize certain function results to JSON (and use previously memoized results if they exist)")
 	flag.IntVar(&flags.maxClusterTextLength, "max_cluster_text_length", defaultMaxClusterTextLength, "truncate failure text to this length for clustering purposes")
@@ -67,6 +81,15 @@ func parseFlags() summarizeFlags {
 	if !(strings.Contains(flags.outputSlices, "PREFIX")) {
 		klog.Fatalf("'PREFIX' not in output_slices flag")
 	}
+	switch flags.source {
+	case "bigquery":
+	case "prow":
+		if flags.artifacts == "" {
+			klog.Fatalf("artifacts flag is required with source 'prow'")
+		}
+	default:
+		klog.Fatalf("Unknown source '%s'", flags.source)
+	}
 
 	return flags
 }
@@ -95,11 +118,34 @@ func summarize(flags summarizeFlags) {
 	// Log flag info
 	klog.V(1).Infof("Running with %d workers (%d detected CPUs)", flags.numWorkers, runtime.NumCPU())
 
-	builds, failedTests, err := loadFailures(flags.builds, flags.tests, flags.memoize)
+	var src source = bigQuerySource{flags.builds, flags.tests, flags.memoize}
+	if flags.source == "prow" {
+		oldest := 0
+		if flags.artifactsDays > 0 {
+			oldest = int(time.Now().AddDate(0, 0, -flags.artifactsDays).Unix())
+		}
+		prowSource, cleanup, err := newProwArtifactsSource(flags.artifacts, oldest)
+		if err != nil {
+			klog.Fatalf("Could not get artifacts: %s", err)
+		}
+		defer cleanup()
+		src = prowSource
+	}
+
+	builds, failedTests, err := src.load()
 	if err != nil {
 		klog.Fatalf("Could not load failures: %s", err)
 	}
 
+	var rules normalizationRules
+	if flags.normalizationRules != "" {
+		rules, err = loadNormalizationRules(flags.normalizationRules)
+		if err != nil {
+			klog.Fatalf("Could not load normalization rules: %s", err)
+		}
+		klog.V(2).Infof("Loaded %d normalization rules", len(rules))
+	}
+
 	var previousClustered []jsonCluster
 	if flags.previous != "" {
 		klog.V(2).Infof("Loading previous")
@@ -109,14 +155,32 @@ func summarize(flags summarizeFlags) {
 		}
 	}
 
-	clusteredLocal := clusterLocal(failedTests, flags.numWorkers, flags.memoize, flags.maxClusterTextLength)
+	var clusteredLocal nestedFailuresGroups
+	var digests map[string]string // The digests of cluster texts, if known
+	if flags.state != "" {
+		klog.V(2).Infof("Loading clustering state")
+		state, err := loadClusterState(flags.state, flags.maxClusterTextLength, rules)
+		if err != nil {
+			klog.Warningf("Could not load clustering state, all failures will be clustered: %s", err)
+		}
 
-	clustered := clusterGlobal(clusteredLocal, previousClustered, flags.memoize, flags.maxClusterTextLength)
+		clusteredLocal, state = clusterIncremental(failedTests, state, flags.numWorkers, flags.maxClusterTextLength, rules)
+		digests = state.digests()
+
+		err = writeClusterState(flags.state, state)
+		if err != nil {
+			klog.Warningf("Could not save clustering state: %s", err)
+		}
+	} else {
+		clusteredLocal = clusterLocal(failedTests, flags.numWorkers, flags.memoize, flags.maxClusterTextLength, rules)
+	}
+
+	clustered := clusterGlobal(clusteredLocal, previousClustered, flags.memoize, flags.maxClusterTextLength, rules)
 
 	klog.V(2).Infof("Rendering results...")
 	start := time.Now()
 
-	data := render(builds, clustered, flags.maxFailureTextLength)
+	data := render(builds, clustered, digests, flags.maxFailureTextLength)
 
 	// Load the owners from the file, if given
 	var owners map[string][]string
@@ -131,11 +195,28 @@ func summarize(flags summarizeFlags) {
 		klog.Warningf("Could not annotate owners: %s", err)
 	}
 
+	resolved := annotateTrends(&data, builds, previousClustered)
+	annotateHints(&data, builds)
+
 	err = writeResults(flags.output, data)
 	if err != nil {
 		klog.Warningf("Could not write results to file: %s", err)
 	}
 
+	if flags.outputAlerts != "" {
+		err = writeAlerts(flags.outputAlerts, renderAlerts(data, builds, resolved))
+		if err != nil {
+			klog.Warningf("Could not write alerts to file: %s", err)
+		}
+	}
+
+	if flags.outputFlakes != "" {
+		err = writeFlakes(flags.outputFlakes, renderFlakes(data, builds))
+		if err != nil {
+			klog.Warningf("Could not write flaky tests report to file: %s", err)
+		}
+	}
+
 	if flags.outputSlices != "" {
 		for subset := 0; subset < 256; subset++ {
 			idPrefix := fmt.Sprintf("%02x", subset)
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"hash/crc32"
 	"io"
+	"path"
 	"regexp"
 	"runtime"
 	"sort"
@@ -63,12 +64,75 @@ var flakeReasonOrdinalRE *regexp.Regexp = regexp.MustCompile(
 		`|goroutine \d+` + // Go goroutine IDs in stack traces
 		`|\d+\.\d{3}s`) // durations with milliseconds (300.001s -> normalize)
 
+// normalizationRule replaces the matches of a regular expression in failure texts before they are
+// clustered. The replacement can refer to submatches of the regular expression, such as $1.
+type normalizationRule struct {
+	Regex       string `json:"regex"`
+	Replacement string `json:"replacement"`
+
+	re *regexp.Regexp
+}
+
+/*
+normalizationRules are project-specific normalization rules, applied in order, for noise that the
+built-in normalization doesn't know about, like random namespace suffixes, pod hashes or temporary
+paths. A nil normalizationRules applies no rules.
+*/
+type normalizationRules []normalizationRule
+
+// compile compiles the regular expression of each rule. It must be called before the rules are
+// applied.
+func (rules normalizationRules) compile() error {
+	for i := range rules {
+		re, err := regexp.Compile(rules[i].Regex)
+		if err != nil {
+			return fmt.Errorf("Could not compile regex of rule %d: %s", i+1, err)
+		}
+		rules[i].re = re
+	}
+	return nil
+}
+
+// apply applies the rules to s, in order.
+func (rules normalizationRules) apply(s string) string {
+	for _, rule := range rules {
+		s = rule.re.ReplaceAllString(s, rule.Replacement)
+	}
+	return s
+}
+
+// digest returns a digest of the rules, which identifies what they normalize to. It returns the
+// empty string if there are no rules.
+func (rules normalizationRules) digest() string {
+	if len(rules) == 0 {
+		return ""
+	}
+	h := sha1.New()
+	for _, rule := range rules {
+		// Separate the fields with a byte that can't be in either
+		fmt.Fprintf(h, "%s\x00%s\x00", rule.Regex, rule.Replacement)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// memoPath returns the path that results clustered with the rules are memoized to, so that results
+// clustered with different rules are not reused. filepath is the path used without rules.
+func (rules normalizationRules) memoPath(filepath string) string {
+	if len(rules) == 0 {
+		return filepath
+	}
+	ext := path.Ext(filepath)
+	return fmt.Sprintf("%s_%s%s", strings.TrimSuffix(filepath, ext), rules.digest()[:12], ext)
+}
+
 /*
 normalize reduces excess entropy to make clustering easier, given
 a traceback or error message from a text.
 
 This includes:
 
+- applying the project-specific rules, see normalizationRules
+
 - blanking dates and timestamps
 
 - renumbering unique information like
@@ -81,7 +145,10 @@ This includes:
 
 - sorting randomly ordered map[] strings.
 */
-func normalize(s string, maxClusterTextLength int) string {
+func normalize(s string, maxClusterTextLength int, rules normalizationRules) string {
+	// apply the project-specific rules first, before anything they match is renumbered
+	s = rules.apply(s)
+
 	// blank out dates
 	s = flakeReasonDateRE.ReplaceAllLiteralString(s, "TIME")
 
@@ -183,7 +250,7 @@ func makeNgramCountsDigest(s string) string {
 	return fmt.Sprintf("%x", hash.Sum(nil))[:20]
 }
 
-// maxPreciseChecksPerWorker bounds how many berghelroach.Dist calls each
+// maxPreciseChecksPerWorker bounds how many precise edit distance checks each
 // parallel worker performs. Real-corpus measurement on a 14-day BigQuery
 // window (288 non-existing failure queries against a 6,470-key cluster
 // corpus) found that every true match — when one exists — is at sort
@@ -197,10 +264,10 @@ const maxPreciseChecksPerWorker = 8
 //
 // Implementation: compute ngramEditDist for every candidate (cheap; the
 // underlying counts are memoized), sort by that lower-bound proxy, then
-// parallelize the expensive berghelroach.Dist calls across workers. Each
-// worker bails after maxPreciseChecksPerWorker precise checks; the lowest
-// sort-position match wins, preserving baseline "first by ngram distance"
-// semantics.
+// parallelize the expensive edit distance checks, with one
+// berghelroach.Matcher shared across workers. Each worker bails after
+// maxPreciseChecksPerWorker precise checks; the lowest sort-position match
+// wins, preserving baseline "first by ngram distance" semantics.
 func findMatch(fnorm string, candidates []string) (result string, found bool) {
 	type distancePair struct {
 		distResult int
@@ -236,6 +303,9 @@ func findMatch(fnorm string, candidates []string) (result string, found bool) {
 		return "", false
 	}
 
+	// Preprocess fnorm once for all of the precise checks
+	matcher := berghelroach.NewMatcher(fnorm)
+
 	workers := runtime.GOMAXPROCS(0)
 	if workers < 1 {
 		workers = 1
@@ -249,7 +319,7 @@ func findMatch(fnorm string, candidates []string) (result string, found bool) {
 				return "", false
 			}
 			tried++
-			if berghelroach.Dist(fnorm, q.key, q.limit) < q.limit {
+			if matcher.Dist(q.key, q.limit) < q.limit {
 				return q.key, true
 			}
 		}
@@ -277,7 +347,7 @@ func findMatch(fnorm string, candidates []string) (result string, found bool) {
 				}
 				tried++
 				q := quals[idx]
-				if berghelroach.Dist(fnorm, q.key, q.limit) < q.limit {
+				if matcher.Dist(q.key, q.limit) < q.limit {
 					results[idx] = q.key
 					for {
 						cur := foundPos.Load()
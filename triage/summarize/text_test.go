@@ -34,7 +34,7 @@ func TestNormalize(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := normalize(tc.argument, defaultMaxClusterTextLength)
+			got := normalize(tc.argument, defaultMaxClusterTextLength, nil)
 
 			if got != tc.want {
 				t.Errorf("normalize(%s) = %s, wanted %s", tc.argument, got, tc.want)
@@ -56,7 +56,7 @@ func TestNormalize(t *testing.T) {
 		// 10*500 = (number of characters in "foobarbaz ")*(500 repetitions)
 		wantString := generatedString[:10*500] + "\n...[truncated]...\n" + generatedString[:10*500]
 
-		got := normalize(generatedString, defaultMaxClusterTextLength)
+		got := normalize(generatedString, defaultMaxClusterTextLength, nil)
 
 		if got != wantString {
 			t.Errorf("normalize(%s) = %s, wanted %s", generatedString, wantString, got)
@@ -115,7 +115,7 @@ In [It] at: k8s.io/kubernetes/test/e2e/node/pods.go:1381 @ 12/11/25 08:42:14.146
 	// Normalize all messages and compute their cluster IDs
 	var clusterIDs []string
 	for _, msg := range failureMessages {
-		normalized := normalize(msg, defaultMaxClusterTextLength)
+		normalized := normalize(msg, defaultMaxClusterTextLength, nil)
 		clusterID := makeNgramCountsDigest(normalized)
 		clusterIDs = append(clusterIDs, clusterID)
 	}
@@ -126,8 +126,8 @@ In [It] at: k8s.io/kubernetes/test/e2e/node/pods.go:1381 @ 12/11/25 08:42:14.146
 		if id != firstID {
 			t.Errorf("Failure message %d produced different cluster ID:\n  got:  %s\n  want: %s\n\nNormalized[0]: %s\nNormalized[%d]: %s",
 				i, id, firstID,
-				normalize(failureMessages[0], defaultMaxClusterTextLength),
-				i, normalize(failureMessages[i], defaultMaxClusterTextLength))
+				normalize(failureMessages[0], defaultMaxClusterTextLength, nil),
+				i, normalize(failureMessages[i], defaultMaxClusterTextLength, nil))
 		}
 	}
 }
@@ -188,7 +188,7 @@ func TestNormalizeNodeNames(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := normalize(tc.input, defaultMaxClusterTextLength)
+			got := normalize(tc.input, defaultMaxClusterTextLength, nil)
 			if got != tc.expected {
 				t.Errorf("normalize(%q) = %q, want %q", tc.input, got, tc.expected)
 			}
@@ -289,3 +289,37 @@ func TestCommonSpans(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizationRules(t *testing.T) {
+	rules := normalizationRules{
+		{Regex: `e2e-tests-([a-z]+)-[a-z0-9]{5}`, Replacement: "e2e-tests-$1-NS"},
+		{Regex: `/tmp/[^/\s]+`, Replacement: "/tmp/TMPDIR"},
+	}
+	if err := rules.compile(); err != nil {
+		t.Fatalf("compile() failed: %s", err)
+	}
+
+	// Without the rules, the random suffix and temporary directory split the failures apart
+	a := `namespace "e2e-tests-kubectl-x7c9q" has no file /tmp/kubectl-test1234/config`
+	b := `namespace "e2e-tests-kubectl-p2rzl" has no file /tmp/kubectl-test9876/config`
+	want := `namespace "e2e-tests-kubectl-NS" has no file /tmp/TMPDIR/config`
+	for _, s := range []string{a, b} {
+		if got := normalize(s, defaultMaxClusterTextLength, rules); got != want {
+			t.Errorf("normalize(%q) = %q, wanted %q", s, got, want)
+		}
+	}
+
+	if rules.digest() == "" || rules.digest() == rules[:1].digest() {
+		t.Errorf("digest() = %q, wanted a digest that differs from the digest of the first rule %q", rules.digest(), rules[:1].digest())
+	}
+	if got := normalizationRules(nil).memoPath("memo.json"); got != "memo.json" {
+		t.Errorf("memoPath() without rules = %q, wanted memo.json", got)
+	}
+	if got := rules.memoPath("memo.json"); got != "memo_"+rules.digest()[:12]+".json" {
+		t.Errorf("memoPath() = %q, wanted the digest of the rules in the path", got)
+	}
+
+	if err := (normalizationRules{{Regex: "("}}).compile(); err == nil {
+		t.Errorf("compile() of an invalid regex succeeded")
+	}
+}
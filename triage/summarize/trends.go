@@ -0,0 +1,166 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Contains functions that compare clusters against the previous run, to tell which are new, growing
+or resolved.
+*/
+
+package summarize
+
+import (
+	"fmt"
+
+	"k8s.io/test-infra/triage/utils"
+)
+
+// The trend classifications of a cluster.
+const (
+	trendNew     = "new"     // The cluster was not in the previous output, and first failed in the last day
+	trendSpiking = "spiking" // The cluster failed at least spikeFactor times as often in the last day as the day before
+	trendStable  = "stable"
+	trendFading  = "fading" // The cluster failed at most half as often in the last day as the day before
+)
+
+const (
+	secondsPerDay = 60 * 60 * 24
+	// spikeFactor is how many times more failures a cluster needs in the last day than the day
+	// before to be spiking.
+	spikeFactor = 2
+	// minSpikeFailures is how many failures a cluster needs in the last day to be spiking, so that
+	// going from one failure to two is not a spike.
+	minSpikeFailures = 5
+)
+
+/*
+annotateTrends compares each cluster to the previous output, and the failures in the last day to
+those in the day before, to fill in its first seen time, delta and trend. It modifies the data
+parameter in place.
+
+previous is the clustered field of the previous output, and can be nil when there isn't one, in which
+case no cluster can be new. Days are counted back from the most recent build.
+
+Returns the clusters of the previous output that no longer exist.
+*/
+func annotateTrends(data *jsonOutput, builds map[string]build, previous []jsonCluster) []jsonCluster {
+	previousByKey := make(map[string]jsonCluster, len(previous))
+	for _, cluster := range previous {
+		previousByKey[cluster.Key] = cluster
+	}
+
+	now := 0
+	if len(data.Builds.Cols.Started) > 0 {
+		now = utils.Max(data.Builds.Cols.Started...)
+	}
+	yesterday := now - secondsPerDay
+	dayBefore := yesterday - secondsPerDay
+
+	current := make(map[string]bool, len(data.Clustered))
+	for i := range data.Clustered {
+		cluster := &data.Clustered[i]
+		current[cluster.Key] = true
+
+		// Count the failures in the last two days, and find the earliest
+		lastDay, dayBeforeCount := 0, 0
+		earliest := 0
+		for _, test := range cluster.Tests {
+			for _, job := range test.Jobs {
+				jobPath := data.Builds.JobPaths[job.Name]
+				for _, number := range job.BuildNumbers {
+					bld, ok := builds[fmt.Sprintf("%s/%s", jobPath, number)]
+					if !ok {
+						continue
+					}
+					if earliest == 0 || bld.Started < earliest {
+						earliest = bld.Started
+					}
+					if bld.Started > yesterday {
+						lastDay++
+					} else if bld.Started > dayBefore {
+						dayBeforeCount++
+					}
+				}
+			}
+		}
+
+		prev, seenBefore := previousByKey[cluster.Key]
+		cluster.FirstSeen = earliest
+		if seenBefore && prev.FirstSeen != 0 && prev.FirstSeen < earliest {
+			cluster.FirstSeen = prev.FirstSeen
+		}
+		cluster.Delta = lastDay - dayBeforeCount
+
+		switch {
+		case previous != nil && !seenBefore && cluster.FirstSeen > yesterday:
+			cluster.Trend = trendNew
+		case lastDay >= minSpikeFailures && lastDay >= spikeFactor*dayBeforeCount:
+			cluster.Trend = trendSpiking
+		case lastDay*2 <= dayBeforeCount:
+			cluster.Trend = trendFading
+		default:
+			cluster.Trend = trendStable
+		}
+	}
+
+	resolved := make([]jsonCluster, 0)
+	for _, cluster := range previous {
+		if !current[cluster.Key] {
+			resolved = append(resolved, cluster)
+		}
+	}
+	return resolved
+}
+
+// resolvedCluster identifies a cluster of the previous output that no longer exists.
+type resolvedCluster struct {
+	Key       string `json:"key"`
+	ID        string `json:"id"`
+	Owner     string `json:"owner"`
+	FirstSeen int    `json:"first_seen,omitempty"`
+}
+
+/*
+alertsOutput holds the clusters that need attention. It has the same form as the main output,
+restricted to the new and spiking clusters and their builds, so that anything that reads the main
+output (like the triage-filer of robots/issue-creator) can read it too, plus the clusters that were
+resolved.
+*/
+type alertsOutput struct {
+	Clustered []jsonCluster     `json:"clustered"`
+	Builds    columns           `json:"builds"`
+	Resolved  []resolvedCluster `json:"resolved"`
+}
+
+// renderAlerts creates the alerts for the annotated data, and the clusters that were resolved as
+// returned by annotateTrends.
+func renderAlerts(data jsonOutput, builds map[string]build, resolved []jsonCluster) alertsOutput {
+	clustered := make([]jsonCluster, 0)
+	for _, cluster := range data.Clustered {
+		if cluster.Trend == trendNew || cluster.Trend == trendSpiking {
+			clustered = append(clustered, cluster)
+		}
+	}
+
+	alerts := alertsOutput{
+		Clustered: clustered,
+		Builds:    clusterBuildsToColumns(clustered, builds),
+		Resolved:  make([]resolvedCluster, 0, len(resolved)),
+	}
+	for _, cluster := range resolved {
+		alerts.Resolved = append(alerts.Resolved, resolvedCluster{cluster.Key, cluster.ID, cluster.Owner, cluster.FirstSeen})
+	}
+	return alerts
+}
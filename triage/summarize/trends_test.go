@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summarize
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestAnnotateTrends(t *testing.T) {
+	now := int(1.5e9)
+	hoursAgo := func(h int) int { return now - h*60*60 }
+
+	// Build number n of each job started n hours ago, and the latest build of job-b just now
+	builds := make(map[string]build)
+	for _, jobName := range []string{"job-a", "job-b"} {
+		for n := 1; n <= 72; n++ {
+			path := fmt.Sprintf("gs://logs/%s/%d", jobName, n)
+			builds[path] = build{Path: path, Job: jobName, Number: n, Started: hoursAgo(n)}
+		}
+	}
+	builds["gs://logs/job-b/100"] = build{Path: "gs://logs/job-b/100", Job: "job-b", Number: 100, Started: now}
+
+	// cluster creates a cluster that failed in the builds of job-a started the given hours ago.
+	cluster := func(key string, hours ...int) jsonCluster {
+		numbers := make([]string, 0, len(hours))
+		for _, h := range hours {
+			numbers = append(numbers, fmt.Sprint(h))
+		}
+		return jsonCluster{Key: key, Tests: []test{{Name: "test", Jobs: []job{{Name: "job-a", BuildNumbers: numbers}}}}}
+	}
+
+	data := jsonOutput{
+		Clustered: []jsonCluster{
+			cluster("brand new", 1, 2),
+			cluster("new to the output, but old", 1, 40),
+			cluster("spiking", 1, 2, 3, 4, 5, 6, 30),
+			cluster("few failures", 1, 2, 30),
+			cluster("stable", 1, 2, 30, 31, 50),
+			cluster("fading", 1, 30, 31, 32),
+			cluster("gone quiet", 50),
+		},
+		Builds: buildsToColumns(builds),
+	}
+	previous := []jsonCluster{
+		{Key: "spiking", FirstSeen: hoursAgo(100)},
+		{Key: "few failures"},
+		{Key: "stable"},
+		{Key: "fading"},
+		{Key: "gone quiet"},
+		{Key: "resolved", ID: "abc", Owner: "node"},
+	}
+
+	resolved := annotateTrends(&data, builds, previous)
+
+	type annotation struct {
+		firstSeen int
+		delta     int
+		trend     string
+	}
+	want := map[string]annotation{
+		"brand new":                  {hoursAgo(2), 2, trendNew},
+		"new to the output, but old": {hoursAgo(40), 0, trendStable},
+		"spiking":                    {hoursAgo(100), 5, trendSpiking},
+		"few failures":               {hoursAgo(30), 1, trendStable},
+		"stable":                     {hoursAgo(50), 0, trendStable},
+		"fading":                     {hoursAgo(32), -2, trendFading},
+		"gone quiet":                 {hoursAgo(50), 0, trendFading},
+	}
+	for _, c := range data.Clustered {
+		got := annotation{c.FirstSeen, c.Delta, c.Trend}
+		if got != want[c.Key] {
+			t.Errorf("annotateTrends() annotated cluster %q with %+v, wanted %+v", c.Key, got, want[c.Key])
+		}
+	}
+
+	if len(resolved) != 1 || resolved[0].Key != "resolved" {
+		t.Errorf("annotateTrends() = %#v, wanted only the resolved cluster", resolved)
+	}
+
+	alerts := renderAlerts(data, builds, resolved)
+	var alerted []string
+	for _, c := range alerts.Clustered {
+		alerted = append(alerted, c.Key)
+	}
+	if wantAlerted := []string{"brand new", "spiking"}; !reflect.DeepEqual(alerted, wantAlerted) {
+		t.Errorf("renderAlerts() alerted clusters %q, wanted %q", alerted, wantAlerted)
+	}
+	if _, ok := alerts.Builds.JobPaths["job-b"]; ok || len(alerts.Builds.Cols.Started) != 72 {
+		t.Errorf("renderAlerts() returned %d builds of jobs %#v, wanted only the 72 builds of job-a", len(alerts.Builds.Cols.Started), alerts.Builds.JobPaths)
+	}
+	if wantResolved := []resolvedCluster{{Key: "resolved", ID: "abc", Owner: "node"}}; !reflect.DeepEqual(alerts.Resolved, wantResolved) {
+		t.Errorf("renderAlerts() resolved clusters %#v, wanted %#v", alerts.Resolved, wantResolved)
+	}
+}
+
+func TestAnnotateTrendsWithoutPrevious(t *testing.T) {
+	now := int(1.5e9)
+	builds := map[string]build{"gs://logs/job/1": {Path: "gs://logs/job/1", Job: "job", Number: 1, Started: now}}
+	data := jsonOutput{
+		Clustered: []jsonCluster{{Key: "key", Tests: []test{{Name: "test", Jobs: []job{{Name: "job", BuildNumbers: []string{"1"}}}}}}},
+		Builds:    buildsToColumns(builds),
+	}
+
+	resolved := annotateTrends(&data, builds, nil)
+
+	// Without a previous output, nothing is known to be new
+	if got := data.Clustered[0]; got.Trend != trendStable || got.FirstSeen != now || got.Delta != 1 {
+		t.Errorf("annotateTrends() annotated %#v, wanted a stable cluster first seen at %d", got, now)
+	}
+	if len(resolved) != 0 {
+		t.Errorf("annotateTrends() = %#v, wanted no resolved clusters", resolved)
+	}
+}